@@ -1,22 +1,33 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"orchid_be/docs"
 	"orchid_be/internal/config"
 	"orchid_be/internal/controller"
+	"orchid_be/internal/job"
+	"orchid_be/internal/mailer"
+	"orchid_be/internal/middleware"
 	"orchid_be/internal/migration"
+	"orchid_be/internal/oidc"
 	"orchid_be/internal/repository"
 	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and the access token.
 func main() {
 	cfg, err := config.LoadConfig("./configs")
 	if err != nil {
@@ -56,13 +67,118 @@ func main() {
 		c.Next()
 	})
 
-	userRepo := repository.NewUserRepository(db)
+	jwtManager, err := utils.NewJWTManager(&cfg.JWT, cfg.Server.Mode == gin.ReleaseMode)
+	if err != nil {
+		log.Fatalf("Failed to initialize JWT manager: %v", err)
+	}
 
-	userService := service.NewUserService(userRepo)
+	mail, err := mailer.NewMailer(&cfg.Mailer)
+	if err != nil {
+		log.Fatalf("Failed to initialize mailer: %v", err)
+	}
 
-	userController := controller.NewUserController(userService)
+	passwordPolicy, err := utils.NewPasswordPolicy(&cfg.PasswordPolicy)
+	if err != nil {
+		log.Fatalf("Failed to load password policy: %v", err)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	oidcRepo := repository.NewOIDCRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	impersonationRepo := repository.NewImpersonationRepository(db)
+	magicLinkRepo := repository.NewMagicLinkRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	technologyRepo := repository.NewTechnologyRepository(db)
+	inventoryRepo := repository.NewInventoryRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	planRepo := repository.NewPlanRepository(db)
+	subscriptionRepo := repository.NewSubscriptionRepository(db)
+	invoiceRepo := repository.NewInvoiceRepository(db)
+	taxRateRepo := repository.NewTaxRateRepository(db)
+	usageRepo := repository.NewUsageRepository(db)
+
+	oidcProviders := oidc.NewProviders(&cfg.OIDC, &http.Client{Timeout: 10 * time.Second})
+
+	userService := service.NewUserService(userRepo, passwordPolicy, jwtManager, mail, cfg.Auth)
+	twoFactorService := service.NewTwoFactorService(userRepo, twoFactorRepo, cfg.Auth)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, sessionRepo, impersonationRepo, roleRepo, twoFactorService, jwtManager, cfg.JWT, cfg.Auth, cfg.LoginProtection)
+	registrationService := service.NewRegistrationService(userRepo, jwtManager, passwordPolicy, mail, cfg.Auth)
+	passwordService := service.NewPasswordService(userRepo, passwordResetRepo, refreshTokenRepo, sessionRepo, passwordPolicy, mail, cfg.Auth)
+	roleService := service.NewRoleService(roleRepo, userRepo)
+	usageService := service.NewUsageService(usageRepo, subscriptionRepo, planRepo, apiKeyRepo, userRepo)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, roleRepo, usageService)
+	oidcService := service.NewOIDCService(oidcRepo, userRepo, authService, oidcProviders, cfg.OIDC)
+	sessionService := service.NewSessionService(sessionRepo, userRepo)
+	impersonationService := service.NewImpersonationService(impersonationRepo, userRepo, roleRepo, jwtManager, cfg.Auth)
+	magicLinkService := service.NewMagicLinkService(magicLinkRepo, userRepo, authService, jwtManager, mail, cfg.Auth, cfg.MagicLink)
+	productService := service.NewProductService(productRepo, categoryRepo, technologyRepo)
+	categoryService := service.NewCrudService[service.CreateCategoryRequest, service.UpdateCategoryRequest](service.CategoryEntity, categoryRepo)
+	technologyService := service.NewCrudService[service.CreateTechnologyRequest, service.UpdateTechnologyRequest](service.TechnologyEntity, technologyRepo)
+	inventoryService := service.NewInventoryService(inventoryRepo, productRepo)
+	planService := service.NewPlanService(planRepo)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, planRepo)
+	invoiceService := service.NewInvoiceService(invoiceRepo, taxRateRepo, subscriptionRepo, planRepo, userRepo, cfg.Billing)
+	metaService := service.NewMetaService(service.UserEntityMeta, service.ProductEntityMeta, categoryService.Meta(), technologyService.Meta())
+
+	if cfg.Auth.BootstrapAdminEmail != "" {
+		if err := roleService.BootstrapAdmin(context.Background(), cfg.Auth.BootstrapAdminEmail); err != nil {
+			log.Printf("Failed to grant admin role to %s: %v", cfg.Auth.BootstrapAdminEmail, err)
+		}
+	}
+
+	authMiddleware := middleware.NewAuthMiddleware(authService, apiKeyService, impersonationService, usageService)
+	loginLimiter := middleware.NewRateLimiter(cfg.LoginProtection.IPMaxRequests, cfg.LoginProtection.IPWindow)
+
+	userController := controller.NewUserController(userService, authMiddleware)
+	authController := controller.NewAuthController(authService, authMiddleware, loginLimiter)
+	registrationController := controller.NewRegistrationController(registrationService, loginLimiter)
+	passwordController := controller.NewPasswordController(passwordService, authMiddleware, loginLimiter)
+	roleController := controller.NewRoleController(roleService, authMiddleware)
+	twoFactorController := controller.NewTwoFactorController(twoFactorService, authMiddleware)
+	apiKeyController := controller.NewAPIKeyController(apiKeyService, authMiddleware)
+	oidcController := controller.NewOIDCController(oidcService, loginLimiter)
+	sessionController := controller.NewSessionController(sessionService, authMiddleware)
+	impersonationController := controller.NewImpersonationController(impersonationService, authMiddleware)
+	magicLinkController := controller.NewMagicLinkController(magicLinkService, loginLimiter)
+	productController := controller.NewProductController(productService, authMiddleware)
+	categoryController := controller.NewCrudController(categoryService, authMiddleware)
+	technologyController := controller.NewCrudController(technologyService, authMiddleware)
+	inventoryController := controller.NewInventoryController(inventoryService, authMiddleware)
+	planController := controller.NewPlanController(planService)
+	subscriptionController := controller.NewSubscriptionController(subscriptionService, authMiddleware)
+	invoiceController := controller.NewInvoiceController(invoiceService, authMiddleware)
+	usageController := controller.NewUsageController(usageService, authMiddleware)
+	metaController := controller.NewMetaController(metaService, authMiddleware)
 
 	userController.SetupRoutes(router)
+	authController.SetupRoutes(router)
+	registrationController.SetupRoutes(router)
+	passwordController.SetupRoutes(router)
+	roleController.SetupRoutes(router)
+	twoFactorController.SetupRoutes(router)
+	apiKeyController.SetupRoutes(router)
+	oidcController.SetupRoutes(router)
+	sessionController.SetupRoutes(router)
+	impersonationController.SetupRoutes(router)
+	magicLinkController.SetupRoutes(router)
+	productController.SetupRoutes(router)
+	categoryController.SetupRoutes(router)
+	technologyController.SetupRoutes(router)
+	inventoryController.SetupRoutes(router)
+	planController.SetupRoutes(router)
+	subscriptionController.SetupRoutes(router)
+	invoiceController.SetupRoutes(router)
+	usageController.SetupRoutes(router)
+	metaController.SetupRoutes(router)
+
+	if cfg.Billing.InvoiceJobEnabled {
+		job.NewInvoiceJob(invoiceService).Start(context.Background())
+	}
 
 	// Setup Swagger documentation
 	docs.SwaggerInfo.BasePath = "/"
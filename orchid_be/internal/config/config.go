@@ -4,13 +4,22 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
+	Server          ServerConfig          `mapstructure:"server"`
+	Database        DatabaseConfig        `mapstructure:"database"`
+	JWT             JWTConfig             `mapstructure:"jwt"`
+	Auth            AuthConfig            `mapstructure:"auth"`
+	PasswordPolicy  PasswordPolicyConfig  `mapstructure:"password_policy"`
+	LoginProtection LoginProtectionConfig `mapstructure:"login_protection"`
+	OIDC            OIDCConfig            `mapstructure:"oidc"`
+	MagicLink       MagicLinkConfig       `mapstructure:"magic_link"`
+	Mailer          MailerConfig          `mapstructure:"mailer"`
+	Billing         BillingConfig         `mapstructure:"billing"`
 }
 
 type ServerConfig struct {
@@ -27,6 +36,88 @@ type DatabaseConfig struct {
 	SSLMode  string `mapstructure:"sslmode"`
 }
 
+type JWTConfig struct {
+	Algorithm       string        `mapstructure:"algorithm"`
+	Secret          string        `mapstructure:"secret"`
+	PrivateKeyPath  string        `mapstructure:"private_key_path"`
+	PublicKeyPath   string        `mapstructure:"public_key_path"`
+	Issuer          string        `mapstructure:"issuer"`
+	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
+}
+
+type AuthConfig struct {
+	FrontendURL          string        `mapstructure:"frontend_url"`
+	EmailVerificationTTL time.Duration `mapstructure:"email_verification_ttl"`
+	PasswordResetTTL     time.Duration `mapstructure:"password_reset_ttl"`
+	BootstrapAdminEmail  string        `mapstructure:"bootstrap_admin_email"`
+	MFATokenTTL          time.Duration `mapstructure:"mfa_token_ttl"`
+	TOTPIssuer           string        `mapstructure:"totp_issuer"`
+	ElevationTTL         time.Duration `mapstructure:"elevation_ttl"`
+	ImpersonationTTL     time.Duration `mapstructure:"impersonation_ttl"`
+}
+
+type PasswordPolicyConfig struct {
+	MinLength           int    `mapstructure:"min_length"`
+	RequireUppercase    bool   `mapstructure:"require_uppercase"`
+	RequireLowercase    bool   `mapstructure:"require_lowercase"`
+	RequireDigit        bool   `mapstructure:"require_digit"`
+	RequireSymbol       bool   `mapstructure:"require_symbol"`
+	CommonPasswordsFile string `mapstructure:"common_passwords_file"`
+}
+
+type LoginProtectionConfig struct {
+	MaxFailedAttempts int           `mapstructure:"max_failed_attempts"`
+	FailureWindow     time.Duration `mapstructure:"failure_window"`
+	LockoutDuration   time.Duration `mapstructure:"lockout_duration"`
+	DelayAfter        int           `mapstructure:"delay_after"`
+	BaseDelay         time.Duration `mapstructure:"base_delay"`
+	MaxDelay          time.Duration `mapstructure:"max_delay"`
+	IPMaxRequests     int           `mapstructure:"ip_max_requests"`
+	IPWindow          time.Duration `mapstructure:"ip_window"`
+}
+
+// OIDCConfig lists the OpenID Connect providers users can log in with, keyed
+// by the name used in the login URL. Providers without a client ID are skipped.
+type OIDCConfig struct {
+	AllowSignUp bool                          `mapstructure:"allow_sign_up"`
+	StateTTL    time.Duration                 `mapstructure:"state_ttl"`
+	Providers   map[string]OIDCProviderConfig `mapstructure:"providers"`
+}
+
+type OIDCProviderConfig struct {
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+// MagicLinkConfig controls passwordless login by emailed link.
+type MagicLinkConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`
+}
+
+type MailerConfig struct {
+	Driver       string `mapstructure:"driver"`
+	From         string `mapstructure:"from"`
+	OutputDir    string `mapstructure:"output_dir"`
+	SMTPHost     string `mapstructure:"smtp_host"`
+	SMTPPort     string `mapstructure:"smtp_port"`
+	SMTPUsername string `mapstructure:"smtp_username"`
+	SMTPPassword string `mapstructure:"smtp_password"`
+}
+
+// BillingConfig controls invoicing. The seller details are printed on every
+// invoice.
+type BillingConfig struct {
+	InvoiceJobEnabled bool   `mapstructure:"invoice_job_enabled"`
+	PaymentDueDays    int    `mapstructure:"payment_due_days"`
+	SellerName        string `mapstructure:"seller_name"`
+	SellerAddress     string `mapstructure:"seller_address"`
+}
+
 func LoadConfig(path string) (*Config, error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigName("config")
@@ -37,6 +128,41 @@ func LoadConfig(path string) (*Config, error) {
 	viper.SetDefault("database.host", "localhost")
 	viper.SetDefault("database.port", "5432")
 	viper.SetDefault("database.sslmode", "disable")
+	viper.SetDefault("jwt.algorithm", "HS256")
+	viper.SetDefault("jwt.issuer", "orchid")
+	viper.SetDefault("jwt.access_token_ttl", "15m")
+	viper.SetDefault("jwt.refresh_token_ttl", "720h")
+	viper.SetDefault("auth.frontend_url", "http://localhost:4321")
+	viper.SetDefault("auth.email_verification_ttl", "24h")
+	viper.SetDefault("auth.password_reset_ttl", "1h")
+	viper.SetDefault("auth.mfa_token_ttl", "5m")
+	viper.SetDefault("auth.totp_issuer", "Orchid")
+	viper.SetDefault("auth.elevation_ttl", "10m")
+	viper.SetDefault("auth.impersonation_ttl", "30m")
+	viper.SetDefault("password_policy.min_length", 8)
+	viper.SetDefault("password_policy.require_uppercase", true)
+	viper.SetDefault("password_policy.require_lowercase", true)
+	viper.SetDefault("password_policy.require_digit", true)
+	viper.SetDefault("password_policy.require_symbol", false)
+	viper.SetDefault("login_protection.max_failed_attempts", 10)
+	viper.SetDefault("login_protection.failure_window", "1h")
+	viper.SetDefault("login_protection.lockout_duration", "15m")
+	viper.SetDefault("login_protection.delay_after", 3)
+	viper.SetDefault("login_protection.base_delay", "1s")
+	viper.SetDefault("login_protection.max_delay", "1m")
+	viper.SetDefault("login_protection.ip_max_requests", 20)
+	viper.SetDefault("login_protection.ip_window", "1m")
+	viper.SetDefault("oidc.allow_sign_up", true)
+	viper.SetDefault("oidc.state_ttl", "10m")
+	viper.SetDefault("magic_link.enabled", true)
+	viper.SetDefault("magic_link.ttl", "15m")
+	viper.SetDefault("mailer.driver", "log")
+	viper.SetDefault("mailer.from", "Orchid <no-reply@orchid.local>")
+	viper.SetDefault("mailer.output_dir", "./tmp/mail")
+	viper.SetDefault("mailer.smtp_port", "587")
+	viper.SetDefault("billing.invoice_job_enabled", true)
+	viper.SetDefault("billing.payment_due_days", 14)
+	viper.SetDefault("billing.seller_name", "Orchid")
 
 	viper.AutomaticEnv()
 
@@ -86,24 +212,23 @@ func processEnvVar(value string) string {
 
 // processEnvVarsInViper processes environment variable substitution for all viper settings
 func processEnvVarsInViper() {
-	// Process top-level and nested settings
-	allSettings := viper.AllSettings()
-	for key, value := range allSettings {
+	processEnvVarsInMap("", viper.AllSettings())
+}
+
+// processEnvVarsInMap walks nested configurations such as oidc.providers.<name>
+func processEnvVarsInMap(prefix string, settings map[string]interface{}) {
+	for key, value := range settings {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
 		if strValue, ok := value.(string); ok {
 			if strings.Contains(strValue, "${") && strings.Contains(strValue, "}") {
 				processedValue := processEnvVar(strValue)
 				viper.Set(key, processedValue)
 			}
 		} else if nestedMap, ok := value.(map[string]interface{}); ok {
-			// Process nested configurations
-			for nestedKey, nestedValue := range nestedMap {
-				if strValue, ok := nestedValue.(string); ok {
-					if strings.Contains(strValue, "${") && strings.Contains(strValue, "}") {
-						processedValue := processEnvVar(strValue)
-						viper.Set(key+"."+nestedKey, processedValue)
-					}
-				}
-			}
+			processEnvVarsInMap(key, nestedMap)
 		}
 	}
 }
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"errors"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyController struct {
+	*BaseController
+	apiKeyService  service.APIKeyService
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewAPIKeyController(apiKeyService service.APIKeyService, authMiddleware *middleware.AuthMiddleware) *APIKeyController {
+	return &APIKeyController{
+		BaseController: NewBaseController(),
+		apiKeyService:  apiKeyService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// GetAPIKeys godoc
+// @Summary List API keys
+// @Description List the current user's API keys. Only the prefix of each key is shown.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/users/me/api-keys [get]
+func (c *APIKeyController) GetAPIKeys(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	keys, err := c.apiKeyService.GetAPIKeys(ctx.Request.Context(), principal.User.ID)
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get API keys", err)
+		return
+	}
+
+	utils.Success(ctx, "API keys retrieved successfully", keys)
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Create an API key limited to the given scopes, which must be permissions the user holds. The full key is returned only in this response. Requires a recent password confirmation via /api/auth/elevation.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key body service.CreateAPIKeyRequest true "API key data"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 402 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/users/me/api-keys [post]
+func (c *APIKeyController) CreateAPIKey(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	var req service.CreateAPIKeyRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	key, err := c.apiKeyService.CreateAPIKey(ctx.Request.Context(), principal.User.ID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrScopeNotGranted) || errors.Is(err, service.ErrUnknownPermission) || errors.Is(err, service.ErrInvalidAPIKeyExpiry) {
+			utils.BadRequest(ctx, "Failed to create API key", err)
+			return
+		}
+		if errors.Is(err, service.ErrPlanLimitExceeded) {
+			utils.PlanLimitExceeded(ctx, "API key limit of the current plan reached", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to create API key", err)
+		return
+	}
+
+	utils.Created(ctx, "API key created, copy it now as it will not be shown again", key)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description Revoke one of the current user's API keys. Revoked keys stop working immediately. Requires a recent password confirmation via /api/auth/elevation.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/me/api-keys/{id} [delete]
+func (c *APIKeyController) RevokeAPIKey(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid API key ID", err)
+		return
+	}
+
+	if err := c.apiKeyService.RevokeAPIKey(ctx.Request.Context(), principal.User.ID, id); err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			utils.NotFound(ctx, "API key not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to revoke API key", err)
+		return
+	}
+
+	utils.Success(ctx, "API key revoked successfully", gin.H{
+		"id": id,
+	})
+}
+
+func (c *APIKeyController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	{
+		keys := api.Group("/users/me/api-keys")
+		keys.Use(c.authMiddleware.RequireAuth(), middleware.RequireSession())
+		{
+			keys.GET("", c.GetAPIKeys)
+			keys.POST("", middleware.ForbidImpersonation(), middleware.RequireElevation(), c.CreateAPIKey)
+			keys.DELETE("/:id", middleware.ForbidImpersonation(), middleware.RequireElevation(), c.RevokeAPIKey)
+		}
+	}
+}
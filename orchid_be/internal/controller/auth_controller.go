@@ -0,0 +1,353 @@
+package controller
+
+import (
+	"errors"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthController struct {
+	*BaseController
+	authService    service.AuthService
+	authMiddleware *middleware.AuthMiddleware
+	loginLimiter   *middleware.RateLimiter
+}
+
+func NewAuthController(authService service.AuthService, authMiddleware *middleware.AuthMiddleware, loginLimiter *middleware.RateLimiter) *AuthController {
+	return &AuthController{
+		BaseController: NewBaseController(),
+		authService:    authService,
+		authMiddleware: authMiddleware,
+		loginLimiter:   loginLimiter,
+	}
+}
+
+// Login godoc
+// @Summary Log in
+// @Description Verify email and password and issue an access token. When two-factor authentication is enabled the response holds an mfa_token for /api/auth/2fa/verify instead.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body service.LoginRequest true "Login credentials"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /api/auth/login [post]
+func (c *AuthController) Login(ctx *gin.Context) {
+	var req service.LoginRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	tokens, challenge, err := c.authService.Login(ctx.Request.Context(), &req, c.GetClientInfo(ctx))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			utils.Unauthorized(ctx, "Invalid email or password", err)
+			return
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) || errors.Is(err, service.ErrAccountDisabled) {
+			utils.Forbidden(ctx, "Account is not active", err)
+			return
+		}
+		if errors.Is(err, service.ErrAccountLocked) {
+			utils.TooManyRequests(ctx, "Account temporarily locked", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to log in", err)
+		return
+	}
+
+	if challenge != nil {
+		utils.Success(ctx, "Two-factor authentication code required", challenge)
+		return
+	}
+
+	utils.Success(ctx, "Logged in successfully", tokens)
+}
+
+// VerifyMFA godoc
+// @Summary Complete two-factor login
+// @Description Exchange the mfa_token from login and an authenticator or recovery code for access and refresh tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body service.VerifyMFARequest true "MFA token and code"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /api/auth/2fa/verify [post]
+func (c *AuthController) VerifyMFA(ctx *gin.Context) {
+	var req service.VerifyMFARequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	tokens, err := c.authService.VerifyMFA(ctx.Request.Context(), &req, c.GetClientInfo(ctx))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrInvalidTwoFactorCode) || errors.Is(err, service.ErrTwoFactorNotEnabled) {
+			utils.Unauthorized(ctx, "Invalid two-factor authentication code", err)
+			return
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) || errors.Is(err, service.ErrAccountDisabled) {
+			utils.Forbidden(ctx, "Account is not active", err)
+			return
+		}
+		if errors.Is(err, service.ErrAccountLocked) {
+			utils.TooManyRequests(ctx, "Account temporarily locked", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to log in", err)
+		return
+	}
+
+	utils.Success(ctx, "Logged in successfully", tokens)
+}
+
+// Refresh godoc
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access and refresh token pair. The presented refresh token is rotated and cannot be used again.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body service.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/auth/refresh [post]
+func (c *AuthController) Refresh(ctx *gin.Context) {
+	var req service.RefreshTokenRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	tokens, err := c.authService.Refresh(ctx.Request.Context(), &req, c.GetClientInfo(ctx))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			utils.Unauthorized(ctx, "Invalid refresh token", err)
+			return
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) || errors.Is(err, service.ErrAccountDisabled) {
+			utils.Forbidden(ctx, "Account is not active", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to refresh tokens", err)
+		return
+	}
+
+	utils.Success(ctx, "Tokens refreshed successfully", tokens)
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Revoke the given refresh token and every token rotated from the same login
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body service.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/auth/logout [post]
+func (c *AuthController) Logout(ctx *gin.Context) {
+	var req service.RefreshTokenRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	if err := c.authService.Logout(ctx.Request.Context(), &req); err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			utils.Unauthorized(ctx, "Invalid refresh token", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to log out", err)
+		return
+	}
+
+	utils.Success(ctx, "Logged out successfully", nil)
+}
+
+// LogoutAll godoc
+// @Summary Log out all devices
+// @Description Revoke every refresh token of the current user
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/auth/logout-all [post]
+func (c *AuthController) LogoutAll(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	if err := c.authService.LogoutAll(ctx.Request.Context(), principal.User.ID); err != nil {
+		utils.InternalServerError(ctx, "Failed to log out all devices", err)
+		return
+	}
+
+	utils.Success(ctx, "Logged out from all devices successfully", nil)
+}
+
+// Me godoc
+// @Summary Get current user
+// @Description Get the user the access token was issued to
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/auth/me [get]
+func (c *AuthController) Me(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	utils.Success(ctx, "User retrieved successfully", principal.User)
+}
+
+// Elevate godoc
+// @Summary Confirm password for sensitive actions
+// @Description Re-enter the password, and the two-factor code when enabled, to let the current session delete users, change email addresses and manage API keys for a short time. Failed attempts count towards the account lockout.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.ElevateRequest true "Password and two-factor code"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /api/auth/elevation [post]
+func (c *AuthController) Elevate(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	var req service.ElevateRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	elevation, err := c.authService.Elevate(ctx.Request.Context(), principal.User.ID, principal.SessionID(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrIncorrectPassword) {
+			utils.Unauthorized(ctx, "Incorrect password", err)
+			return
+		}
+		if errors.Is(err, service.ErrTwoFactorCodeRequired) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+			utils.Unauthorized(ctx, "Invalid two-factor authentication code", err)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrUserNotFound) {
+			utils.Unauthorized(ctx, "Invalid session", err)
+			return
+		}
+		if errors.Is(err, service.ErrAccountLocked) {
+			utils.TooManyRequests(ctx, "Account temporarily locked", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to confirm password", err)
+		return
+	}
+
+	utils.Success(ctx, "Password confirmed", elevation)
+}
+
+// GetElevation godoc
+// @Summary Get elevation status
+// @Description Tell whether the current session may perform sensitive actions and for how long
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/auth/elevation [get]
+func (c *AuthController) GetElevation(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	utils.Success(ctx, "Elevation status retrieved successfully", principal.Elevation())
+}
+
+// DropElevation godoc
+// @Summary End elevation
+// @Description End the current session's sensitive action window before it expires
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/auth/elevation [delete]
+func (c *AuthController) DropElevation(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	if err := c.authService.DropElevation(ctx.Request.Context(), principal.User.ID, principal.SessionID()); err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			utils.Unauthorized(ctx, "Invalid session", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to end elevation", err)
+		return
+	}
+
+	utils.Success(ctx, "Elevation ended", &service.ElevationResponse{})
+}
+
+func (c *AuthController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	{
+		auth := api.Group("/auth")
+		{
+			auth.POST("/login", c.loginLimiter.Limit(), c.Login)
+			auth.POST("/2fa/verify", c.loginLimiter.Limit(), c.VerifyMFA)
+			auth.POST("/refresh", c.Refresh)
+			auth.POST("/logout", c.Logout)
+			auth.POST("/logout-all", c.authMiddleware.RequireAuth(), middleware.ForbidImpersonation(), c.LogoutAll)
+			auth.GET("/me", c.authMiddleware.RequireAuth(), c.Me)
+
+			elevation := auth.Group("/elevation")
+			elevation.Use(c.authMiddleware.RequireAuth(), middleware.RequireSession())
+			{
+				elevation.POST("", c.loginLimiter.Limit(), middleware.ForbidImpersonation(), c.Elevate)
+				elevation.GET("", c.GetElevation)
+				elevation.DELETE("", c.DropElevation)
+			}
+		}
+	}
+}
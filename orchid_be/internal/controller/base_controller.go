@@ -4,6 +4,9 @@ import (
 	"net/http"
 	"strconv"
 
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,6 +25,18 @@ func (c *BaseController) GetIDFromURL(ctx *gin.Context) (int, error) {
 	return id, nil
 }
 
+func (c *BaseController) GetIntParam(ctx *gin.Context, name string) (int, error) {
+	return strconv.Atoi(ctx.Param(name))
+}
+
+// GetClientInfo describes the caller for session tracking.
+func (c *BaseController) GetClientInfo(ctx *gin.Context) service.ClientInfo {
+	return service.ClientInfo{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+}
+
 func (c *BaseController) GetPageAndLimitFromQuery(ctx *gin.Context) (int, int) {
 	page := 1
 	limit := 10
@@ -42,7 +57,10 @@ func (c *BaseController) GetPageAndLimitFromQuery(ctx *gin.Context) (int, int) {
 }
 
 func (c *BaseController) BindJSON(ctx *gin.Context, obj interface{}) error {
-	return ctx.ShouldBindJSON(obj)
+	if err := ctx.ShouldBindJSON(obj); err != nil {
+		return err
+	}
+	return utils.ValidateStruct(obj)
 }
 
 func (c *BaseController) SendPaginationResponse(ctx *gin.Context, data interface{}, total, page, limit int) {
@@ -0,0 +1,274 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CrudController serves the list, get, create, update and delete routes of
+// one entity under /api/<name>. The godoc blocks below list the routes of
+// every entity registered in main.go; add yours when declaring a new one.
+type CrudController[C, U, R any] struct {
+	*BaseController
+	crudService    service.CrudService[C, U, R]
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewCrudController[C, U, R any](crudService service.CrudService[C, U, R], authMiddleware *middleware.AuthMiddleware) *CrudController[C, U, R] {
+	return &CrudController[C, U, R]{
+		BaseController: NewBaseController(),
+		crudService:    crudService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// List godoc
+// @Summary List records
+// @Description Get a paginated list of an entity's records. Filter on the entity's filter columns with ?column=value or ?column[op]=value, where op is eq, ne, gt, gte, lt, lte or in (comma separated values), e.g. ?created_at[gte]=2026-01-01. The filter, sort and search columns of each entity are listed by /api/meta/entities.
+// @Tags entities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param sort query string false "Comma separated columns to sort by, descending when prefixed with -" example(-created_at,name)
+// @Param order query string false "Sort direction of columns without a prefix" Enums(asc, desc)
+// @Param q query string false "Case-insensitive text to search for"
+// @Param name query string false "Filter on name"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/categories [get]
+// @Router /api/technologies [get]
+func (c *CrudController[C, U, R]) List(ctx *gin.Context) {
+	page, limit := c.GetPageAndLimitFromQuery(ctx)
+
+	items, total, err := c.crudService.List(ctx.Request.Context(), listRequest(ctx, page, limit))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidListQuery) || errors.Is(err, service.ErrInvalidEntity) {
+			utils.BadRequest(ctx, "Invalid query", err)
+			return
+		}
+		utils.InternalServerError(ctx, fmt.Sprintf("Failed to get %s", c.crudService.Config().Name), err)
+		return
+	}
+
+	c.SendPaginationResponse(ctx, items, total, page, limit)
+}
+
+// Get godoc
+// @Summary Get record by ID
+// @Description Get a single record of an entity by its ID
+// @Tags entities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Record ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/categories/{id} [get]
+// @Router /api/technologies/{id} [get]
+func (c *CrudController[C, U, R]) Get(ctx *gin.Context) {
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	item, err := c.crudService.Get(ctx.Request.Context(), id)
+	if err != nil {
+		c.handleError(ctx, "retrieve", err)
+		return
+	}
+
+	utils.Success(ctx, c.message("retrieved"), item)
+}
+
+// Create godoc
+// @Summary Create record
+// @Description Create a record of an entity. The body holds the entity's fields.
+// @Tags entities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param record body object true "Record fields"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/categories [post]
+// @Router /api/technologies [post]
+func (c *CrudController[C, U, R]) Create(ctx *gin.Context) {
+	var req C
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	item, err := c.crudService.Create(ctx.Request.Context(), &req)
+	if err != nil {
+		c.handleError(ctx, "create", err)
+		return
+	}
+
+	utils.Created(ctx, c.message("created"), item)
+}
+
+// Update godoc
+// @Summary Update record
+// @Description Update a record of an entity. The body holds the entity's fields.
+// @Tags entities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Record ID"
+// @Param record body object true "Record fields"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/categories/{id} [put]
+// @Router /api/technologies/{id} [put]
+func (c *CrudController[C, U, R]) Update(ctx *gin.Context) {
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	var req U
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	item, err := c.crudService.Update(ctx.Request.Context(), id, &req)
+	if err != nil {
+		c.handleError(ctx, "update", err)
+		return
+	}
+
+	utils.Success(ctx, c.message("updated"), item)
+}
+
+// Delete godoc
+// @Summary Delete record
+// @Description Delete a record of an entity. Refused with 409 while other records still reference it.
+// @Tags entities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Record ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/categories/{id} [delete]
+// @Router /api/technologies/{id} [delete]
+func (c *CrudController[C, U, R]) Delete(ctx *gin.Context) {
+	id, ok := c.getID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.crudService.Delete(ctx.Request.Context(), id); err != nil {
+		c.handleError(ctx, "delete", err)
+		return
+	}
+
+	utils.Success(ctx, c.message("deleted"), gin.H{
+		"id": id,
+	})
+}
+
+func (c *CrudController[C, U, R]) getID(ctx *gin.Context) (int, bool) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, fmt.Sprintf("Invalid %s ID", strings.ToLower(c.crudService.Config().DisplayName)), err)
+		return 0, false
+	}
+	return id, true
+}
+
+func (c *CrudController[C, U, R]) handleError(ctx *gin.Context, action string, err error) {
+	displayName := c.crudService.Config().DisplayName
+
+	switch {
+	case errors.Is(err, service.ErrEntityNotFound):
+		utils.NotFound(ctx, fmt.Sprintf("%s not found", displayName), err)
+	case errors.Is(err, service.ErrEntityExists), errors.Is(err, service.ErrEntityInUse):
+		utils.Conflict(ctx, fmt.Sprintf("Failed to %s %s", action, strings.ToLower(displayName)), err)
+	case errors.Is(err, service.ErrInvalidEntity):
+		utils.BadRequest(ctx, "Invalid request body", err)
+	default:
+		utils.InternalServerError(ctx, fmt.Sprintf("Failed to %s %s", action, strings.ToLower(displayName)), err)
+	}
+}
+
+// message builds the success message, e.g. "Category created successfully".
+func (c *CrudController[C, U, R]) message(action string) string {
+	return fmt.Sprintf("%s %s successfully", c.crudService.Config().DisplayName, action)
+}
+
+// requirePermissionIfSet guards a route with the permission, if the entity
+// sets one.
+func requirePermissionIfSet(name string) gin.HandlerFunc {
+	if name == "" {
+		return func(ctx *gin.Context) {
+			ctx.Next()
+		}
+	}
+	return middleware.RequirePermission(name)
+}
+
+// listRequest reads the sort, order and q parameters of a list, passing every
+// query parameter on as a possible filter.
+func listRequest(ctx *gin.Context, page, limit int) service.ListRequest {
+	filters := make(map[string]string)
+	for name, values := range ctx.Request.URL.Query() {
+		if len(values) > 0 {
+			filters[name] = values[0]
+		}
+	}
+
+	return service.ListRequest{
+		Page:    page,
+		Limit:   limit,
+		Sort:    ctx.Query("sort"),
+		Order:   ctx.Query("order"),
+		Search:  ctx.Query("q"),
+		Filters: filters,
+	}
+}
+
+func (c *CrudController[C, U, R]) SetupRoutes(router *gin.Engine) {
+	config := c.crudService.Config()
+
+	api := router.Group("/api")
+	{
+		records := api.Group("/" + config.Name)
+		records.Use(c.authMiddleware.RequireAuth())
+		{
+			records.GET("", requirePermissionIfSet(config.Permissions.Read), c.List)
+			records.GET("/:id", requirePermissionIfSet(config.Permissions.Read), c.Get)
+			records.POST("", requirePermissionIfSet(config.Permissions.Create), c.Create)
+			records.PUT("/:id", requirePermissionIfSet(config.Permissions.Update), c.Update)
+			records.DELETE("/:id", requirePermissionIfSet(config.Permissions.Delete), c.Delete)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"errors"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImpersonationController struct {
+	*BaseController
+	impersonationService service.ImpersonationService
+	authMiddleware       *middleware.AuthMiddleware
+}
+
+func NewImpersonationController(impersonationService service.ImpersonationService, authMiddleware *middleware.AuthMiddleware) *ImpersonationController {
+	return &ImpersonationController{
+		BaseController:       NewBaseController(),
+		impersonationService: impersonationService,
+		authMiddleware:       authMiddleware,
+	}
+}
+
+// StartImpersonation godoc
+// @Summary Impersonate a user
+// @Description Issue an access token to act as the given user, for support. The token carries the staff member in the act claim, has no refresh token and expires after auth.impersonation_ttl. Password changes, account deletion and other credential changes are refused while impersonating, and every write request is recorded. Users holding permissions the caller lacks cannot be impersonated. Requires users:impersonate.
+// @Tags impersonation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body service.StartImpersonationRequest true "Reason for the impersonation"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/{id}/impersonate [post]
+func (c *ImpersonationController) StartImpersonation(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	userID, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid user ID", err)
+		return
+	}
+
+	var req service.StartImpersonationRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	tokens, err := c.impersonationService.Start(ctx.Request.Context(), principal, userID, &req, c.GetClientInfo(ctx))
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			utils.NotFound(ctx, "User not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrImpersonationReasonRequired) || errors.Is(err, service.ErrCannotImpersonateSelf) {
+			utils.BadRequest(ctx, "Failed to impersonate user", err)
+			return
+		}
+		if errors.Is(err, service.ErrImpersonationNotAllowed) || errors.Is(err, service.ErrEmailNotVerified) || errors.Is(err, service.ErrAccountDisabled) {
+			utils.Forbidden(ctx, "User cannot be impersonated", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to impersonate user", err)
+		return
+	}
+
+	utils.Created(ctx, "Impersonation started", tokens)
+}
+
+// GetCurrentImpersonation godoc
+// @Summary Get current impersonation
+// @Description Describe the impersonation the access token was issued for, for example to show a banner
+// @Tags impersonation
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/impersonation [get]
+func (c *ImpersonationController) GetCurrentImpersonation(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	impersonation, err := c.impersonationService.GetCurrent(ctx.Request.Context(), principal)
+	if err != nil {
+		if errors.Is(err, service.ErrNotImpersonating) {
+			utils.NotFound(ctx, "Not impersonating a user", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to get impersonation", err)
+		return
+	}
+
+	utils.Success(ctx, "Impersonation retrieved successfully", impersonation)
+}
+
+// StopImpersonation godoc
+// @Summary Stop impersonating
+// @Description End the impersonation the access token was issued for. The token stops working immediately.
+// @Tags impersonation
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/impersonation [delete]
+func (c *ImpersonationController) StopImpersonation(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	if err := c.impersonationService.Stop(ctx.Request.Context(), principal, c.GetClientInfo(ctx)); err != nil {
+		if errors.Is(err, service.ErrNotImpersonating) {
+			utils.BadRequest(ctx, "Not impersonating a user", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to stop impersonation", err)
+		return
+	}
+
+	utils.Success(ctx, "Impersonation stopped", nil)
+}
+
+// GetImpersonations godoc
+// @Summary List impersonations
+// @Description List impersonations, newest first. Requires impersonations:read.
+// @Tags impersonation
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/impersonations [get]
+func (c *ImpersonationController) GetImpersonations(ctx *gin.Context) {
+	page, limit := c.GetPageAndLimitFromQuery(ctx)
+
+	impersonations, total, err := c.impersonationService.GetImpersonations(ctx.Request.Context(), page, limit)
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get impersonations", err)
+		return
+	}
+
+	c.SendPaginationResponse(ctx, impersonations, total, page, limit)
+}
+
+// GetImpersonationEvents godoc
+// @Summary Get impersonation audit trail
+// @Description List when the impersonation started and stopped and every write request made during it. Requires impersonations:read.
+// @Tags impersonation
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Impersonation ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/impersonations/{id}/events [get]
+func (c *ImpersonationController) GetImpersonationEvents(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid impersonation ID", err)
+		return
+	}
+
+	events, err := c.impersonationService.GetEvents(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrImpersonationNotFound) {
+			utils.NotFound(ctx, "Impersonation not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to get impersonation events", err)
+		return
+	}
+
+	utils.Success(ctx, "Impersonation events retrieved successfully", events)
+}
+
+func (c *ImpersonationController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	api.Use(c.authMiddleware.RequireAuth())
+	{
+		api.POST("/users/:id/impersonate", middleware.RequireSession(), middleware.ForbidImpersonation(), middleware.RequirePermission(service.PermissionUsersImpersonate), c.StartImpersonation)
+
+		api.GET("/impersonation", c.GetCurrentImpersonation)
+		api.DELETE("/impersonation", c.StopImpersonation)
+
+		impersonations := api.Group("/impersonations")
+		impersonations.Use(middleware.RequirePermission(service.PermissionImpersonationsRead))
+		{
+			impersonations.GET("", c.GetImpersonations)
+			impersonations.GET("/:id/events", c.GetImpersonationEvents)
+		}
+	}
+}
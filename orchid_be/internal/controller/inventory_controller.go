@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"errors"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryController struct {
+	*BaseController
+	inventoryService service.InventoryService
+	authMiddleware   *middleware.AuthMiddleware
+}
+
+func NewInventoryController(inventoryService service.InventoryService, authMiddleware *middleware.AuthMiddleware) *InventoryController {
+	return &InventoryController{
+		BaseController:   NewBaseController(),
+		inventoryService: inventoryService,
+		authMiddleware:   authMiddleware,
+	}
+}
+
+// GetStock godoc
+// @Summary Get product stock
+// @Description Get the on-hand quantity and low-stock threshold of a product. Requires the inventory:read permission.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/products/{id}/stock [get]
+func (c *InventoryController) GetStock(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid product ID", err)
+		return
+	}
+
+	stock, err := c.inventoryService.GetStock(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			utils.NotFound(ctx, "Product not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to get stock", err)
+		return
+	}
+
+	utils.Success(ctx, "Stock retrieved successfully", stock)
+}
+
+// SetLowStockThreshold godoc
+// @Summary Set low-stock threshold
+// @Description Set the quantity at or below which the product is listed by /api/products/low-stock. 0 turns the warning off. Requires the inventory:manage permission.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body service.SetLowStockThresholdRequest true "Threshold"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/products/{id}/stock [put]
+func (c *InventoryController) SetLowStockThreshold(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid product ID", err)
+		return
+	}
+
+	var req service.SetLowStockThresholdRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	stock, err := c.inventoryService.SetLowStockThreshold(ctx.Request.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			utils.NotFound(ctx, "Product not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to set low stock threshold", err)
+		return
+	}
+
+	utils.Success(ctx, "Low stock threshold updated successfully", stock)
+}
+
+// GetMovements godoc
+// @Summary List stock movements
+// @Description Get the paginated stock ledger of a product, newest first. Requires the inventory:read permission.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/products/{id}/stock/movements [get]
+func (c *InventoryController) GetMovements(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid product ID", err)
+		return
+	}
+
+	page, limit := c.GetPageAndLimitFromQuery(ctx)
+
+	movements, total, err := c.inventoryService.GetMovements(ctx.Request.Context(), id, page, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			utils.NotFound(ctx, "Product not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to get stock movements", err)
+		return
+	}
+
+	c.SendPaginationResponse(ctx, movements, total, page, limit)
+}
+
+// RecordMovement godoc
+// @Summary Record stock movement
+// @Description Append a receive, sell, adjust or return movement to the ledger and update the on-hand quantity in the same transaction. A movement that would take stock below zero is refused with 409; concurrent movements of the same product are applied one at a time. Requires the inventory:manage permission.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body service.CreateStockMovementRequest true "Movement"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/products/{id}/stock/movements [post]
+func (c *InventoryController) RecordMovement(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid product ID", err)
+		return
+	}
+
+	var req service.CreateStockMovementRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	movement, err := c.inventoryService.RecordMovement(ctx.Request.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			utils.NotFound(ctx, "Product not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidStockQuantity) {
+			utils.BadRequest(ctx, "Invalid request body", err)
+			return
+		}
+		if errors.Is(err, service.ErrInsufficientStock) {
+			utils.Conflict(ctx, "Insufficient stock", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to record stock movement", err)
+		return
+	}
+
+	utils.Created(ctx, "Stock movement recorded successfully", movement)
+}
+
+// GetLowStock godoc
+// @Summary List low-stock products
+// @Description Get paginated list of products whose on-hand quantity is at or below their low-stock threshold, emptiest first. Requires the inventory:read permission.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/products/low-stock [get]
+func (c *InventoryController) GetLowStock(ctx *gin.Context) {
+	page, limit := c.GetPageAndLimitFromQuery(ctx)
+
+	products, total, err := c.inventoryService.GetLowStock(ctx.Request.Context(), page, limit)
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get low stock products", err)
+		return
+	}
+
+	c.SendPaginationResponse(ctx, products, total, page, limit)
+}
+
+func (c *InventoryController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	{
+		products := api.Group("/products")
+		products.Use(c.authMiddleware.RequireAuth())
+		{
+			products.GET("/low-stock", middleware.RequirePermission(service.PermissionInventoryRead), c.GetLowStock)
+			products.GET("/:id/stock", middleware.RequirePermission(service.PermissionInventoryRead), c.GetStock)
+			products.PUT("/:id/stock", middleware.RequirePermission(service.PermissionInventoryManage), c.SetLowStockThreshold)
+			products.GET("/:id/stock/movements", middleware.RequirePermission(service.PermissionInventoryRead), c.GetMovements)
+			products.POST("/:id/stock/movements", middleware.RequirePermission(service.PermissionInventoryManage), c.RecordMovement)
+		}
+	}
+}
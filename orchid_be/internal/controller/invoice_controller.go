@@ -0,0 +1,448 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvoiceController struct {
+	*BaseController
+	invoiceService service.InvoiceService
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewInvoiceController(invoiceService service.InvoiceService, authMiddleware *middleware.AuthMiddleware) *InvoiceController {
+	return &InvoiceController{
+		BaseController: NewBaseController(),
+		invoiceService: invoiceService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// GetMyInvoices godoc
+// @Summary List my invoices
+// @Description Get paginated list of the invoices issued to the current user, newest first. Drafts are not listed.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/users/me/invoices [get]
+func (c *InvoiceController) GetMyInvoices(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	page, limit := c.GetPageAndLimitFromQuery(ctx)
+
+	invoices, total, err := c.invoiceService.GetUserInvoices(ctx.Request.Context(), principal.User.ID, page, limit)
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get invoices", err)
+		return
+	}
+
+	c.SendPaginationResponse(ctx, invoices, total, page, limit)
+}
+
+// GetMyInvoice godoc
+// @Summary Get one of my invoices
+// @Description Get an invoice of the current user with its line items
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/me/invoices/{id} [get]
+func (c *InvoiceController) GetMyInvoice(ctx *gin.Context) {
+	invoice, ok := c.getMyInvoice(ctx)
+	if !ok {
+		return
+	}
+
+	utils.Success(ctx, "Invoice retrieved successfully", invoice)
+}
+
+// DownloadMyInvoice godoc
+// @Summary Download one of my invoices
+// @Description Download an invoice of the current user as a printable HTML page
+// @Tags invoices
+// @Produce html
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {string} string "Invoice HTML"
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/me/invoices/{id}/download [get]
+func (c *InvoiceController) DownloadMyInvoice(ctx *gin.Context) {
+	invoice, ok := c.getMyInvoice(ctx)
+	if !ok {
+		return
+	}
+
+	c.sendInvoiceHTML(ctx, invoice)
+}
+
+// GetInvoices godoc
+// @Summary Get all invoices
+// @Description Get paginated list of all invoices, newest first. Requires the invoices:read permission.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/invoices [get]
+func (c *InvoiceController) GetInvoices(ctx *gin.Context) {
+	page, limit := c.GetPageAndLimitFromQuery(ctx)
+
+	invoices, total, err := c.invoiceService.GetInvoices(ctx.Request.Context(), page, limit)
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get invoices", err)
+		return
+	}
+
+	c.SendPaginationResponse(ctx, invoices, total, page, limit)
+}
+
+// GetInvoiceByID godoc
+// @Summary Get invoice by ID
+// @Description Get any invoice with its line items. Requires the invoices:read permission.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/invoices/{id} [get]
+func (c *InvoiceController) GetInvoiceByID(ctx *gin.Context) {
+	invoice, ok := c.getInvoice(ctx)
+	if !ok {
+		return
+	}
+
+	utils.Success(ctx, "Invoice retrieved successfully", invoice)
+}
+
+// DownloadInvoice godoc
+// @Summary Download invoice
+// @Description Download any invoice as a printable HTML page. Requires the invoices:read permission.
+// @Tags invoices
+// @Produce html
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {string} string "Invoice HTML"
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/invoices/{id}/download [get]
+func (c *InvoiceController) DownloadInvoice(ctx *gin.Context) {
+	invoice, ok := c.getInvoice(ctx)
+	if !ok {
+		return
+	}
+
+	c.sendInvoiceHTML(ctx, invoice)
+}
+
+// GenerateInvoices godoc
+// @Summary Generate invoices
+// @Description Run the monthly invoice job now: invoice every active subscription whose current period or plan changes are not billed yet, then finalize all drafts. Running it twice does not bill anything twice. Requires the invoices:manage permission.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/invoices/generate [post]
+func (c *InvoiceController) GenerateInvoices(ctx *gin.Context) {
+	result, err := c.invoiceService.GenerateInvoices(ctx.Request.Context(), time.Now())
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to generate invoices", err)
+		return
+	}
+
+	utils.Success(ctx, "Invoices generated successfully", result)
+}
+
+// FinalizeInvoice godoc
+// @Summary Finalize invoice
+// @Description Issue a draft invoice: it gets the next invoice number and becomes open. Requires the invoices:manage permission.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/invoices/{id}/finalize [post]
+func (c *InvoiceController) FinalizeInvoice(ctx *gin.Context) {
+	c.changeStatus(ctx, c.invoiceService.FinalizeInvoice, "Invoice finalized successfully")
+}
+
+// MarkInvoicePaid godoc
+// @Summary Mark invoice paid
+// @Description Record the payment of an open invoice. Requires the invoices:manage permission.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/invoices/{id}/pay [post]
+func (c *InvoiceController) MarkInvoicePaid(ctx *gin.Context) {
+	c.changeStatus(ctx, c.invoiceService.MarkInvoicePaid, "Invoice marked paid successfully")
+}
+
+// VoidInvoice godoc
+// @Summary Void invoice
+// @Description Cancel a draft or open invoice. A voided open invoice keeps its number; a voided draft has none. Requires the invoices:manage permission.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/invoices/{id}/void [post]
+func (c *InvoiceController) VoidInvoice(ctx *gin.Context) {
+	c.changeStatus(ctx, c.invoiceService.VoidInvoice, "Invoice voided successfully")
+}
+
+// GetTaxRates godoc
+// @Summary Get tax rates
+// @Description List the tax rates by country. Customers in a country without a rate are invoiced without tax. Requires the invoices:read permission.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/tax-rates [get]
+func (c *InvoiceController) GetTaxRates(ctx *gin.Context) {
+	taxRates, err := c.invoiceService.GetTaxRates(ctx.Request.Context())
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get tax rates", err)
+		return
+	}
+
+	utils.Success(ctx, "Tax rates retrieved successfully", taxRates)
+}
+
+// SetTaxRate godoc
+// @Summary Set tax rate
+// @Description Create or replace the tax rate of a country. It applies to invoices created from now on. Requires the invoices:manage permission.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param country path string true "ISO 3166-1 alpha-2 country code" example(DE)
+// @Param taxRate body service.SetTaxRateRequest true "Tax rate"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/tax-rates/{country} [put]
+func (c *InvoiceController) SetTaxRate(ctx *gin.Context) {
+	var req service.SetTaxRateRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	taxRate, err := c.invoiceService.SetTaxRate(ctx.Request.Context(), ctx.Param("country"), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCountry) || errors.Is(err, service.ErrInvalidTaxRate) {
+			utils.BadRequest(ctx, "Invalid request body", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to set tax rate", err)
+		return
+	}
+
+	utils.Success(ctx, "Tax rate set successfully", taxRate)
+}
+
+// DeleteTaxRate godoc
+// @Summary Delete tax rate
+// @Description Stop charging tax to customers of a country. Requires the invoices:manage permission.
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param country path string true "ISO 3166-1 alpha-2 country code" example(DE)
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/tax-rates/{country} [delete]
+func (c *InvoiceController) DeleteTaxRate(ctx *gin.Context) {
+	country := ctx.Param("country")
+
+	if err := c.invoiceService.DeleteTaxRate(ctx.Request.Context(), country); err != nil {
+		if errors.Is(err, service.ErrTaxRateNotFound) {
+			utils.NotFound(ctx, "Tax rate not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to delete tax rate", err)
+		return
+	}
+
+	utils.Success(ctx, "Tax rate deleted successfully", gin.H{
+		"country": country,
+	})
+}
+
+func (c *InvoiceController) getMyInvoice(ctx *gin.Context) (*service.InvoiceResponse, bool) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return nil, false
+	}
+
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid invoice ID", err)
+		return nil, false
+	}
+
+	invoice, err := c.invoiceService.GetUserInvoice(ctx.Request.Context(), principal.User.ID, id)
+	if err != nil {
+		utils.NotFound(ctx, "Invoice not found", err)
+		return nil, false
+	}
+
+	return invoice, true
+}
+
+func (c *InvoiceController) getInvoice(ctx *gin.Context) (*service.InvoiceResponse, bool) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid invoice ID", err)
+		return nil, false
+	}
+
+	invoice, err := c.invoiceService.GetInvoiceByID(ctx.Request.Context(), id)
+	if err != nil {
+		utils.NotFound(ctx, "Invoice not found", err)
+		return nil, false
+	}
+
+	return invoice, true
+}
+
+func (c *InvoiceController) changeStatus(ctx *gin.Context, change func(ctx context.Context, id int) (*service.InvoiceResponse, error), message string) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid invoice ID", err)
+		return
+	}
+
+	invoice, err := change(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrInvoiceNotFound) {
+			utils.NotFound(ctx, "Invoice not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidInvoiceStatus) {
+			utils.Conflict(ctx, "Invalid invoice status", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to update invoice", err)
+		return
+	}
+
+	utils.Success(ctx, message, invoice)
+}
+
+func (c *InvoiceController) sendInvoiceHTML(ctx *gin.Context, invoice *service.InvoiceResponse) {
+	body, err := c.invoiceService.RenderInvoice(invoice)
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to render invoice", err)
+		return
+	}
+
+	filename := invoice.Number
+	if filename == "" {
+		filename = fmt.Sprintf("invoice-draft-%d", invoice.ID)
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.html"`, filename))
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", body)
+}
+
+func (c *InvoiceController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	api.Use(c.authMiddleware.RequireAuth())
+	{
+		me := api.Group("/users/me/invoices")
+		{
+			me.GET("", c.GetMyInvoices)
+			me.GET("/:id", c.GetMyInvoice)
+			me.GET("/:id/download", c.DownloadMyInvoice)
+		}
+
+		invoices := api.Group("/invoices")
+		{
+			invoices.GET("", middleware.RequirePermission(service.PermissionInvoicesRead), c.GetInvoices)
+			invoices.POST("/generate", middleware.RequirePermission(service.PermissionInvoicesManage), c.GenerateInvoices)
+			invoices.GET("/:id", middleware.RequirePermission(service.PermissionInvoicesRead), c.GetInvoiceByID)
+			invoices.GET("/:id/download", middleware.RequirePermission(service.PermissionInvoicesRead), c.DownloadInvoice)
+			invoices.POST("/:id/finalize", middleware.RequirePermission(service.PermissionInvoicesManage), c.FinalizeInvoice)
+			invoices.POST("/:id/pay", middleware.RequirePermission(service.PermissionInvoicesManage), c.MarkInvoicePaid)
+			invoices.POST("/:id/void", middleware.RequirePermission(service.PermissionInvoicesManage), c.VoidInvoice)
+		}
+
+		taxRates := api.Group("/tax-rates")
+		{
+			taxRates.GET("", middleware.RequirePermission(service.PermissionInvoicesRead), c.GetTaxRates)
+			taxRates.PUT("/:country", middleware.RequirePermission(service.PermissionInvoicesManage), c.SetTaxRate)
+			taxRates.DELETE("/:country", middleware.RequirePermission(service.PermissionInvoicesManage), c.DeleteTaxRate)
+		}
+	}
+}
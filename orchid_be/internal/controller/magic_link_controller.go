@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"errors"
+	"log"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MagicLinkController struct {
+	*BaseController
+	magicLinkService service.MagicLinkService
+	loginLimiter     *middleware.RateLimiter
+}
+
+func NewMagicLinkController(magicLinkService service.MagicLinkService, loginLimiter *middleware.RateLimiter) *MagicLinkController {
+	return &MagicLinkController{
+		BaseController:   NewBaseController(),
+		magicLinkService: magicLinkService,
+		loginLimiter:     loginLimiter,
+	}
+}
+
+// RequestMagicLink godoc
+// @Summary Request login link
+// @Description Email a single-use login link that expires after magic_link.ttl. The response is the same whether or not the email belongs to an account.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body service.MagicLinkRequest true "Email address"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /api/auth/magic-link [post]
+func (c *MagicLinkController) RequestMagicLink(ctx *gin.Context) {
+	var req service.MagicLinkRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	if err := c.magicLinkService.RequestLink(ctx.Request.Context(), &req); err != nil {
+		if errors.Is(err, service.ErrMagicLinkDisabled) {
+			utils.NotFound(ctx, "Magic link login is disabled", err)
+			return
+		}
+		// Answer as usual so failures do not leak which emails exist
+		log.Printf("Failed to process magic link request: %v", err)
+	}
+
+	utils.Success(ctx, "If an account exists for this email, a login link has been sent", nil)
+}
+
+// ConsumeMagicLink godoc
+// @Summary Log in with login link
+// @Description Exchange the token from a login link for access and refresh tokens. When two-factor authentication is enabled the response holds an mfa_token for /api/auth/2fa/verify instead.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body service.ConsumeMagicLinkRequest true "Login link token"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /api/auth/magic-link/consume [post]
+func (c *MagicLinkController) ConsumeMagicLink(ctx *gin.Context) {
+	var req service.ConsumeMagicLinkRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	tokens, challenge, err := c.magicLinkService.ConsumeLink(ctx.Request.Context(), &req, c.GetClientInfo(ctx))
+	if err != nil {
+		if errors.Is(err, service.ErrMagicLinkDisabled) {
+			utils.NotFound(ctx, "Magic link login is disabled", err)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrUserNotFound) {
+			utils.Unauthorized(ctx, "Invalid or expired login link", err)
+			return
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) || errors.Is(err, service.ErrAccountDisabled) {
+			utils.Forbidden(ctx, "Account is not active", err)
+			return
+		}
+		if errors.Is(err, service.ErrAccountLocked) {
+			utils.TooManyRequests(ctx, "Account temporarily locked", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to log in", err)
+		return
+	}
+
+	if challenge != nil {
+		utils.Success(ctx, "Two-factor authentication code required", challenge)
+		return
+	}
+
+	utils.Success(ctx, "Logged in successfully", tokens)
+}
+
+func (c *MagicLinkController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	{
+		magicLink := api.Group("/auth/magic-link")
+		magicLink.Use(c.loginLimiter.Limit())
+		{
+			magicLink.POST("", c.RequestMagicLink)
+			magicLink.POST("/consume", c.ConsumeMagicLink)
+		}
+	}
+}
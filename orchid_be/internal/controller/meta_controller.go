@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"errors"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetaController struct {
+	*BaseController
+	metaService    service.MetaService
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewMetaController(metaService service.MetaService, authMiddleware *middleware.AuthMiddleware) *MetaController {
+	return &MetaController{
+		BaseController: NewBaseController(),
+		metaService:    metaService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// GetEntities godoc
+// @Summary List entity metadata
+// @Description Describe every entity the dashboard renders: its table columns with sortable, filterable and searchable flags, its form fields with types, validation rules and options, its default sort and the permissions its routes require. Generated from the tags of the backend's request and response structs.
+// @Tags meta
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /api/meta/entities [get]
+func (c *MetaController) GetEntities(ctx *gin.Context) {
+	utils.Success(ctx, "Entity metadata retrieved successfully", c.metaService.GetEntities())
+}
+
+// GetEntity godoc
+// @Summary Get entity metadata
+// @Description Describe one entity, e.g. users, products, categories or technologies
+// @Tags meta
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Entity type" example(users)
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/meta/entities/{type} [get]
+func (c *MetaController) GetEntity(ctx *gin.Context) {
+	entity, err := c.metaService.GetEntity(ctx.Param("type"))
+	if err != nil {
+		if errors.Is(err, service.ErrEntityTypeNotFound) {
+			utils.NotFound(ctx, "Entity type not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to get entity metadata", err)
+		return
+	}
+
+	utils.Success(ctx, "Entity metadata retrieved successfully", entity)
+}
+
+func (c *MetaController) SetupRoutes(router *gin.Engine) {
+	meta := router.Group("/api/meta")
+	meta.Use(c.authMiddleware.RequireAuth())
+	{
+		meta.GET("/entities", c.GetEntities)
+		meta.GET("/entities/:type", c.GetEntity)
+	}
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"errors"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCController struct {
+	*BaseController
+	oidcService  service.OIDCService
+	loginLimiter *middleware.RateLimiter
+}
+
+func NewOIDCController(oidcService service.OIDCService, loginLimiter *middleware.RateLimiter) *OIDCController {
+	return &OIDCController{
+		BaseController: NewBaseController(),
+		oidcService:    oidcService,
+		loginLimiter:   loginLimiter,
+	}
+}
+
+// GetProviders godoc
+// @Summary List identity providers
+// @Description List the OpenID Connect providers users can log in with
+// @Tags oidc
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /api/auth/oidc/providers [get]
+func (c *OIDCController) GetProviders(ctx *gin.Context) {
+	utils.Success(ctx, "Identity providers retrieved successfully", c.oidcService.GetProviders())
+}
+
+// StartLogin godoc
+// @Summary Start identity provider login
+// @Description Create a one-time login state and return the provider authorization URL (authorization code flow with PKCE). Keep the state and compare it with the one the provider redirects back with.
+// @Tags oidc
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /api/auth/oidc/{provider}/login [get]
+func (c *OIDCController) StartLogin(ctx *gin.Context) {
+	authorization, err := c.oidcService.StartLogin(ctx.Request.Context(), ctx.Param("provider"))
+	if err != nil {
+		if errors.Is(err, service.ErrOIDCProviderNotFound) {
+			utils.NotFound(ctx, "Identity provider not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to start login", err)
+		return
+	}
+
+	utils.Success(ctx, "Redirect to the identity provider to continue", authorization)
+}
+
+// FinishLogin godoc
+// @Summary Complete identity provider login
+// @Description Exchange the code and state from the provider redirect for access and refresh tokens. The account is linked by verified email on first login and created if none exists. When two-factor authentication is enabled the response holds an mfa_token for /api/auth/2fa/verify instead.
+// @Tags oidc
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param request body service.OIDCCallbackRequest true "Authorization code and state"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /api/auth/oidc/{provider}/callback [post]
+func (c *OIDCController) FinishLogin(ctx *gin.Context) {
+	var req service.OIDCCallbackRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	tokens, challenge, err := c.oidcService.FinishLogin(ctx.Request.Context(), ctx.Param("provider"), &req, c.GetClientInfo(ctx))
+	if err != nil {
+		if errors.Is(err, service.ErrOIDCProviderNotFound) {
+			utils.NotFound(ctx, "Identity provider not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidOIDCState) {
+			utils.BadRequest(ctx, "Invalid or expired login state", err)
+			return
+		}
+		if errors.Is(err, service.ErrOIDCLoginFailed) {
+			utils.Unauthorized(ctx, "Identity provider login failed", err)
+			return
+		}
+		if errors.Is(err, service.ErrOIDCEmailNotVerified) || errors.Is(err, service.ErrOIDCSignUpDisabled) || errors.Is(err, service.ErrOIDCAccountNotVerified) {
+			utils.Forbidden(ctx, "Account cannot be linked", err)
+			return
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) || errors.Is(err, service.ErrAccountDisabled) {
+			utils.Forbidden(ctx, "Account is not active", err)
+			return
+		}
+		if errors.Is(err, service.ErrAccountLocked) {
+			utils.TooManyRequests(ctx, "Account temporarily locked", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to log in", err)
+		return
+	}
+
+	if challenge != nil {
+		utils.Success(ctx, "Two-factor authentication code required", challenge)
+		return
+	}
+
+	utils.Success(ctx, "Logged in successfully", tokens)
+}
+
+func (c *OIDCController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	{
+		oidc := api.Group("/auth/oidc")
+		{
+			oidc.GET("/providers", c.GetProviders)
+			oidc.GET("/:provider/login", c.loginLimiter.Limit(), c.StartLogin)
+			oidc.POST("/:provider/callback", c.loginLimiter.Limit(), c.FinishLogin)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"errors"
+	"log"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordController struct {
+	*BaseController
+	passwordService service.PasswordService
+	authMiddleware  *middleware.AuthMiddleware
+	loginLimiter    *middleware.RateLimiter
+}
+
+func NewPasswordController(passwordService service.PasswordService, authMiddleware *middleware.AuthMiddleware, loginLimiter *middleware.RateLimiter) *PasswordController {
+	return &PasswordController{
+		BaseController:  NewBaseController(),
+		passwordService: passwordService,
+		authMiddleware:  authMiddleware,
+		loginLimiter:    loginLimiter,
+	}
+}
+
+// ForgotPassword godoc
+// @Summary Request password reset
+// @Description Email a single-use password reset link. The response is the same whether or not the email belongs to an account.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body service.ForgotPasswordRequest true "Email address"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /api/auth/forgot-password [post]
+func (c *PasswordController) ForgotPassword(ctx *gin.Context) {
+	var req service.ForgotPasswordRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	if err := c.passwordService.RequestPasswordReset(ctx.Request.Context(), &req); err != nil {
+		// Answer as usual so failures do not leak which emails exist
+		log.Printf("Failed to process password reset request: %v", err)
+	}
+
+	utils.Success(ctx, "If an account exists for this email, a password reset link has been sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password with the token from the reset email. All existing sessions are revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body service.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/auth/reset-password [post]
+func (c *PasswordController) ResetPassword(ctx *gin.Context) {
+	var req service.ResetPasswordRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	if err := c.passwordService.ResetPassword(ctx.Request.Context(), &req); err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			utils.BadRequest(ctx, "Invalid or expired reset link", err)
+			return
+		}
+		if errors.Is(err, utils.ErrWeakPassword) {
+			utils.BadRequest(ctx, "Password does not meet the password policy", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to reset password", err)
+		return
+	}
+
+	utils.Success(ctx, "Password has been reset successfully", nil)
+}
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change the current user's password. The current password is required and other devices are signed out.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/users/me/password [put]
+func (c *PasswordController) ChangePassword(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	var req service.ChangePasswordRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	if err := c.passwordService.ChangePassword(ctx.Request.Context(), principal.User.ID, principal.SessionID(), &req); err != nil {
+		if errors.Is(err, service.ErrIncorrectPassword) || errors.Is(err, service.ErrPasswordUnchanged) || errors.Is(err, utils.ErrWeakPassword) {
+			utils.BadRequest(ctx, "Failed to change password", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to change password", err)
+		return
+	}
+
+	utils.Success(ctx, "Password changed successfully", nil)
+}
+
+func (c *PasswordController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	{
+		auth := api.Group("/auth")
+		{
+			auth.POST("/forgot-password", c.loginLimiter.Limit(), c.ForgotPassword)
+			auth.POST("/reset-password", c.ResetPassword)
+		}
+
+		me := api.Group("/users/me")
+		me.Use(c.authMiddleware.RequireAuth(), middleware.RequireSession())
+		{
+			me.PUT("/password", middleware.ForbidImpersonation(), c.ChangePassword)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlanController struct {
+	*BaseController
+	planService service.PlanService
+}
+
+func NewPlanController(planService service.PlanService) *PlanController {
+	return &PlanController{
+		BaseController: NewBaseController(),
+		planService:    planService,
+	}
+}
+
+// GetPlans godoc
+// @Summary Get pricing plans
+// @Description List the plans open for subscription, for the pricing page. Limits maps a limit name to its maximum; a missing name is unlimited.
+// @Tags plans
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/plans [get]
+func (c *PlanController) GetPlans(ctx *gin.Context) {
+	plans, err := c.planService.GetPlans(ctx.Request.Context())
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get plans", err)
+		return
+	}
+
+	utils.Success(ctx, "Plans retrieved successfully", plans)
+}
+
+// GetPlanByID godoc
+// @Summary Get plan by ID
+// @Description Get a single plan by its ID
+// @Tags plans
+// @Accept json
+// @Produce json
+// @Param id path int true "Plan ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/plans/{id} [get]
+func (c *PlanController) GetPlanByID(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid plan ID", err)
+		return
+	}
+
+	plan, err := c.planService.GetPlanByID(ctx.Request.Context(), id)
+	if err != nil {
+		utils.NotFound(ctx, "Plan not found", err)
+		return
+	}
+
+	utils.Success(ctx, "Plan retrieved successfully", plan)
+}
+
+func (c *PlanController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	{
+		plans := api.Group("/plans")
+		{
+			plans.GET("", c.GetPlans)
+			plans.GET("/:id", c.GetPlanByID)
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package controller
+
+import (
+	"errors"
+	"time"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProductController struct {
+	*BaseController
+	productService service.ProductService
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewProductController(productService service.ProductService, authMiddleware *middleware.AuthMiddleware) *ProductController {
+	return &ProductController{
+		BaseController: NewBaseController(),
+		productService: productService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// GetProducts godoc
+// @Summary Get all products
+// @Description Get paginated list of products, newest first. Prices are decimal strings; sale_price is the price with the discount applied. Price and discount are the ones in effect now, or at the given time.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param at query string false "RFC 3339 time to read prices at, defaults to now" example(2026-12-24T00:00:00Z)
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/products [get]
+func (c *ProductController) GetProducts(ctx *gin.Context) {
+	page, limit := c.GetPageAndLimitFromQuery(ctx)
+
+	at, err := c.getAtFromQuery(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid at parameter", err)
+		return
+	}
+
+	products, total, err := c.productService.GetAllProducts(ctx.Request.Context(), page, limit, at)
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get products", err)
+		return
+	}
+
+	c.SendPaginationResponse(ctx, products, total, page, limit)
+}
+
+// GetProductOptions godoc
+// @Summary Get product form options
+// @Description List every category and technology as value/label pairs for the select boxes of the product form. The value is the id to send as category_id or technology_id.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/products/options [get]
+func (c *ProductController) GetProductOptions(ctx *gin.Context) {
+	options, err := c.productService.GetOptions(ctx.Request.Context())
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get product options", err)
+		return
+	}
+
+	utils.Success(ctx, "Product options retrieved successfully", options)
+}
+
+// GetProductByID godoc
+// @Summary Get product by ID
+// @Description Get a single product by its ID, with the price and discount in effect now or at the given time
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param at query string false "RFC 3339 time to read prices at, defaults to now" example(2026-12-24T00:00:00Z)
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/products/{id} [get]
+func (c *ProductController) GetProductByID(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid product ID", err)
+		return
+	}
+
+	at, err := c.getAtFromQuery(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid at parameter", err)
+		return
+	}
+
+	product, err := c.productService.GetProductByID(ctx.Request.Context(), id, at)
+	if err != nil {
+		utils.NotFound(ctx, "Product not found", err)
+		return
+	}
+
+	utils.Success(ctx, "Product retrieved successfully", product)
+}
+
+// CreateProduct godoc
+// @Summary Create new product
+// @Description Create a product. Price is a decimal string with up to 2 decimal places, discount a percentage from 0 to 100. category_id and technology_id must exist. Requires the products:create permission.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param product body service.CreateProductRequest true "Product data"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/products [post]
+func (c *ProductController) CreateProduct(ctx *gin.Context) {
+	var req service.CreateProductRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	product, err := c.productService.CreateProduct(ctx.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPrice) || errors.Is(err, service.ErrInvalidDiscount) {
+			utils.BadRequest(ctx, "Invalid request body", err)
+			return
+		}
+		if errors.Is(err, service.ErrCategoryNotFound) || errors.Is(err, service.ErrTechnologyNotFound) {
+			utils.BadRequest(ctx, "Invalid request body", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to create product", err)
+		return
+	}
+
+	utils.Created(ctx, "Product created successfully", product)
+}
+
+// UpdateProduct godoc
+// @Summary Update product
+// @Description Update the given fields of a product. A new price or discount takes effect immediately and is recorded in the price history. Requires the products:update permission.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param product body service.UpdateProductRequest true "Product data"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/products/{id} [put]
+func (c *ProductController) UpdateProduct(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid product ID", err)
+		return
+	}
+
+	var req service.UpdateProductRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	product, err := c.productService.UpdateProduct(ctx.Request.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			utils.NotFound(ctx, "Product not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidPrice) || errors.Is(err, service.ErrInvalidDiscount) {
+			utils.BadRequest(ctx, "Invalid request body", err)
+			return
+		}
+		if errors.Is(err, service.ErrCategoryNotFound) || errors.Is(err, service.ErrTechnologyNotFound) {
+			utils.BadRequest(ctx, "Invalid request body", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to update product", err)
+		return
+	}
+
+	utils.Success(ctx, "Product updated successfully", product)
+}
+
+// DeleteProduct godoc
+// @Summary Delete product
+// @Description Delete a product by ID. Requires the products:delete permission.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/products/{id} [delete]
+func (c *ProductController) DeleteProduct(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid product ID", err)
+		return
+	}
+
+	if err := c.productService.DeleteProduct(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			utils.NotFound(ctx, "Product not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to delete product", err)
+		return
+	}
+
+	utils.Success(ctx, "Product deleted successfully", gin.H{
+		"id": id,
+	})
+}
+
+// GetPriceHistory godoc
+// @Summary Get product price history
+// @Description Get paginated list of price and discount changes of a product, including scheduled ones, latest start first. Requires the products:update permission.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/products/{id}/prices [get]
+func (c *ProductController) GetPriceHistory(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid product ID", err)
+		return
+	}
+
+	page, limit := c.GetPageAndLimitFromQuery(ctx)
+
+	prices, total, err := c.productService.GetPriceHistory(ctx.Request.Context(), id, page, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			utils.NotFound(ctx, "Product not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to get price history", err)
+		return
+	}
+
+	c.SendPaginationResponse(ctx, prices, total, page, limit)
+}
+
+// SchedulePriceChange godoc
+// @Summary Schedule price change
+// @Description Set a new price, discount or both from starts_at on. Without ends_at the change stays until a later one; with ends_at the previous value applies again afterwards. Requires the products:update permission.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param price body service.SchedulePriceChangeRequest true "Price change"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/products/{id}/prices [post]
+func (c *ProductController) SchedulePriceChange(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid product ID", err)
+		return
+	}
+
+	var req service.SchedulePriceChangeRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	price, err := c.productService.SchedulePriceChange(ctx.Request.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			utils.NotFound(ctx, "Product not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidPrice) || errors.Is(err, service.ErrInvalidDiscount) ||
+			errors.Is(err, service.ErrEmptyPriceChange) || errors.Is(err, service.ErrInvalidPriceSchedule) {
+			utils.BadRequest(ctx, "Invalid request body", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to schedule price change", err)
+		return
+	}
+
+	utils.Created(ctx, "Price change scheduled successfully", price)
+}
+
+// CancelPriceChange godoc
+// @Summary Cancel scheduled price change
+// @Description Remove a price change that has not started yet. Changes already in effect stay in the history. Requires the products:update permission.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param price_id path int true "Price change ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/products/{id}/prices/{price_id} [delete]
+func (c *ProductController) CancelPriceChange(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid product ID", err)
+		return
+	}
+
+	priceID, err := c.GetIntParam(ctx, "price_id")
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid price change ID", err)
+		return
+	}
+
+	if err := c.productService.CancelPriceChange(ctx.Request.Context(), id, priceID); err != nil {
+		if errors.Is(err, service.ErrPriceChangeNotFound) {
+			utils.NotFound(ctx, "Price change not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to cancel price change", err)
+		return
+	}
+
+	utils.Success(ctx, "Price change cancelled successfully", gin.H{
+		"id": priceID,
+	})
+}
+
+// getAtFromQuery reads the optional at query parameter. The zero time means
+// it was not given.
+func (c *ProductController) getAtFromQuery(ctx *gin.Context) (time.Time, error) {
+	value := ctx.Query("at")
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func (c *ProductController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	{
+		products := api.Group("/products")
+		products.Use(c.authMiddleware.RequireAuth())
+		{
+			products.GET("", c.GetProducts)
+			products.GET("/options", c.GetProductOptions)
+			products.GET("/:id", c.GetProductByID)
+			products.POST("", middleware.RequirePermission(service.PermissionProductsCreate), c.CreateProduct)
+			products.PUT("/:id", middleware.RequirePermission(service.PermissionProductsUpdate), c.UpdateProduct)
+			products.DELETE("/:id", middleware.RequirePermission(service.PermissionProductsDelete), c.DeleteProduct)
+			products.GET("/:id/prices", middleware.RequirePermission(service.PermissionProductsUpdate), c.GetPriceHistory)
+			products.POST("/:id/prices", middleware.RequirePermission(service.PermissionProductsUpdate), c.SchedulePriceChange)
+			products.DELETE("/:id/prices/:price_id", middleware.RequirePermission(service.PermissionProductsUpdate), c.CancelPriceChange)
+		}
+	}
+}
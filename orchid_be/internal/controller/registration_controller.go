@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"errors"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RegistrationController struct {
+	*BaseController
+	registrationService service.RegistrationService
+	loginLimiter        *middleware.RateLimiter
+}
+
+func NewRegistrationController(registrationService service.RegistrationService, loginLimiter *middleware.RateLimiter) *RegistrationController {
+	return &RegistrationController{
+		BaseController:      NewBaseController(),
+		registrationService: registrationService,
+		loginLimiter:        loginLimiter,
+	}
+}
+
+// Register godoc
+// @Summary Sign up
+// @Description Create a pending account and email a verification link
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param user body service.RegisterRequest true "Sign-up data"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/auth/register [post]
+func (c *RegistrationController) Register(ctx *gin.Context) {
+	var req service.RegisterRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	user, err := c.registrationService.Register(ctx.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrEmailAlreadyExists) {
+			utils.Conflict(ctx, "Failed to register", err)
+			return
+		}
+		if errors.Is(err, utils.ErrWeakPassword) {
+			utils.BadRequest(ctx, "Password does not meet the password policy", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to register", err)
+		return
+	}
+
+	utils.Created(ctx, "Registration successful, please check your email to verify your account", user)
+}
+
+// VerifyEmail godoc
+// @Summary Verify email
+// @Description Activate a pending account with the token from the verification email
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body service.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /api/auth/verify-email [post]
+func (c *RegistrationController) VerifyEmail(ctx *gin.Context) {
+	var req service.VerifyEmailRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	user, err := c.registrationService.VerifyEmail(ctx.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			utils.BadRequest(ctx, "Invalid or expired verification link", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to verify email", err)
+		return
+	}
+
+	utils.Success(ctx, "Email verified successfully", user)
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Send a new verification link if the account's email is not verified yet. Always succeeds so it cannot be used to discover accounts.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body service.ResendVerificationRequest true "Email address"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /api/auth/resend-verification [post]
+func (c *RegistrationController) ResendVerification(ctx *gin.Context) {
+	var req service.ResendVerificationRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	if err := c.registrationService.ResendVerification(ctx.Request.Context(), &req); err != nil {
+		utils.InternalServerError(ctx, "Failed to send verification email", err)
+		return
+	}
+
+	utils.Success(ctx, "If the account is awaiting verification, a new link has been sent", nil)
+}
+
+func (c *RegistrationController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	{
+		auth := api.Group("/auth")
+		{
+			auth.POST("/register", c.Register)
+			auth.POST("/verify-email", c.VerifyEmail)
+			auth.POST("/resend-verification", c.loginLimiter.Limit(), c.ResendVerification)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package controller
+
+import (
+	"errors"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleController struct {
+	*BaseController
+	roleService    service.RoleService
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewRoleController(roleService service.RoleService, authMiddleware *middleware.AuthMiddleware) *RoleController {
+	return &RoleController{
+		BaseController: NewBaseController(),
+		roleService:    roleService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// GetRoles godoc
+// @Summary List roles
+// @Description List every role with its permissions. Requires roles:read.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/roles [get]
+func (c *RoleController) GetRoles(ctx *gin.Context) {
+	roles, err := c.roleService.GetAllRoles(ctx.Request.Context())
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get roles", err)
+		return
+	}
+
+	utils.Success(ctx, "Roles retrieved successfully", roles)
+}
+
+// CreateRole godoc
+// @Summary Create role
+// @Description Create a role granting the given permissions. Requires roles:manage.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role body service.CreateRoleRequest true "Role data"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/roles [post]
+func (c *RoleController) CreateRole(ctx *gin.Context) {
+	var req service.CreateRoleRequest
+
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	role, err := c.roleService.CreateRole(ctx.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrRoleAlreadyExists) {
+			utils.Conflict(ctx, "Failed to create role", err)
+			return
+		}
+		if errors.Is(err, service.ErrUnknownPermission) {
+			utils.BadRequest(ctx, "Failed to create role", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to create role", err)
+		return
+	}
+
+	utils.Created(ctx, "Role created successfully", role)
+}
+
+// DeleteRole godoc
+// @Summary Delete role
+// @Description Delete a role and remove it from every user. The admin role cannot be deleted. Requires roles:manage.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/roles/{id} [delete]
+func (c *RoleController) DeleteRole(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid role ID", err)
+		return
+	}
+
+	if err := c.roleService.DeleteRole(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrRoleNotFound) {
+			utils.NotFound(ctx, "Role not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrRoleProtected) {
+			utils.BadRequest(ctx, "Failed to delete role", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to delete role", err)
+		return
+	}
+
+	utils.Success(ctx, "Role deleted successfully", gin.H{
+		"id": id,
+	})
+}
+
+// GetPermissions godoc
+// @Summary List permissions
+// @Description List every permission that can be granted to a role. Requires roles:read.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/permissions [get]
+func (c *RoleController) GetPermissions(ctx *gin.Context) {
+	permissions, err := c.roleService.GetAllPermissions(ctx.Request.Context())
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get permissions", err)
+		return
+	}
+
+	utils.Success(ctx, "Permissions retrieved successfully", permissions)
+}
+
+// GetUserRoles godoc
+// @Summary List user roles
+// @Description List the roles assigned to a user. Users can always see their own roles; anyone else needs roles:read.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/{id}/roles [get]
+func (c *RoleController) GetUserRoles(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid user ID", err)
+		return
+	}
+
+	roles, err := c.roleService.GetUserRoles(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			utils.NotFound(ctx, "User not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to get user roles", err)
+		return
+	}
+
+	utils.Success(ctx, "User roles retrieved successfully", roles)
+}
+
+// AssignRole godoc
+// @Summary Assign role
+// @Description Assign a role to a user by role name. Requires roles:manage.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param role body service.AssignRoleRequest true "Role to assign"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/{id}/roles [post]
+func (c *RoleController) AssignRole(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid user ID", err)
+		return
+	}
+
+	var req service.AssignRoleRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	roles, err := c.roleService.AssignRole(ctx.Request.Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			utils.NotFound(ctx, "User not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrRoleNotFound) {
+			utils.NotFound(ctx, "Role not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to assign role", err)
+		return
+	}
+
+	utils.Success(ctx, "Role assigned successfully", roles)
+}
+
+// RemoveRole godoc
+// @Summary Remove role
+// @Description Remove a role from a user. The last admin cannot lose the admin role. Requires roles:manage.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param role_id path int true "Role ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/{id}/roles/{role_id} [delete]
+func (c *RoleController) RemoveRole(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid user ID", err)
+		return
+	}
+
+	roleID, err := c.GetIntParam(ctx, "role_id")
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid role ID", err)
+		return
+	}
+
+	if err := c.roleService.RemoveRole(ctx.Request.Context(), id, roleID); err != nil {
+		if errors.Is(err, service.ErrRoleNotFound) {
+			utils.NotFound(ctx, "Role not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrLastAdmin) {
+			utils.BadRequest(ctx, "Failed to remove role", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to remove role", err)
+		return
+	}
+
+	utils.Success(ctx, "Role removed successfully", gin.H{
+		"user_id": id,
+		"role_id": roleID,
+	})
+}
+
+func (c *RoleController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	api.Use(c.authMiddleware.RequireAuth())
+	{
+		roles := api.Group("/roles")
+		{
+			roles.GET("", middleware.RequirePermission(service.PermissionRolesRead), c.GetRoles)
+			roles.POST("", middleware.RequirePermission(service.PermissionRolesManage), c.CreateRole)
+			roles.DELETE("/:id", middleware.RequirePermission(service.PermissionRolesManage), c.DeleteRole)
+		}
+
+		api.GET("/permissions", middleware.RequirePermission(service.PermissionRolesRead), c.GetPermissions)
+
+		userRoles := api.Group("/users/:id/roles")
+		{
+			userRoles.GET("", middleware.RequirePermissionOrSelf(service.PermissionRolesRead), c.GetUserRoles)
+			userRoles.POST("", middleware.RequirePermission(service.PermissionRolesManage), c.AssignRole)
+			userRoles.DELETE("/:role_id", middleware.RequirePermission(service.PermissionRolesManage), c.RemoveRole)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"errors"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionController struct {
+	*BaseController
+	sessionService service.SessionService
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewSessionController(sessionService service.SessionService, authMiddleware *middleware.AuthMiddleware) *SessionController {
+	return &SessionController{
+		BaseController: NewBaseController(),
+		sessionService: sessionService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// GetMySessions godoc
+// @Summary List my sessions
+// @Description List the devices the current user is logged in on. The session of this request is marked as current.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/users/me/sessions [get]
+func (c *SessionController) GetMySessions(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	sessions, err := c.sessionService.GetSessions(ctx.Request.Context(), principal.User.ID, principal.SessionID())
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get sessions", err)
+		return
+	}
+
+	utils.Success(ctx, "Sessions retrieved successfully", sessions)
+}
+
+// RevokeMySession godoc
+// @Summary Revoke one of my sessions
+// @Description Log out one of the current user's devices. Its refresh token and access tokens stop working immediately.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/me/sessions/{id} [delete]
+func (c *SessionController) RevokeMySession(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid session ID", err)
+		return
+	}
+
+	c.revokeSession(ctx, principal.User.ID, id)
+}
+
+// GetUserSessions godoc
+// @Summary List a user's sessions
+// @Description List the devices any user is logged in on. Requires sessions:read.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/{id}/sessions [get]
+func (c *SessionController) GetUserSessions(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	userID, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid user ID", err)
+		return
+	}
+
+	sessions, err := c.sessionService.GetSessions(ctx.Request.Context(), userID, principal.SessionID())
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			utils.NotFound(ctx, "User not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to get sessions", err)
+		return
+	}
+
+	utils.Success(ctx, "Sessions retrieved successfully", sessions)
+}
+
+// RevokeUserSession godoc
+// @Summary Revoke a user's session
+// @Description Log out one device of any user, for example after a stolen laptop. Requires sessions:revoke.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param session_id path int true "Session ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/{id}/sessions/{session_id} [delete]
+func (c *SessionController) RevokeUserSession(ctx *gin.Context) {
+	userID, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid user ID", err)
+		return
+	}
+
+	id, err := c.GetIntParam(ctx, "session_id")
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid session ID", err)
+		return
+	}
+
+	c.revokeSession(ctx, userID, id)
+}
+
+func (c *SessionController) revokeSession(ctx *gin.Context, userID, id int) {
+	if err := c.sessionService.RevokeSession(ctx.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			utils.NotFound(ctx, "Session not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to revoke session", err)
+		return
+	}
+
+	utils.Success(ctx, "Session revoked successfully", gin.H{
+		"id": id,
+	})
+}
+
+func (c *SessionController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	api.Use(c.authMiddleware.RequireAuth())
+	{
+		me := api.Group("/users/me/sessions")
+		me.Use(middleware.RequireSession())
+		{
+			me.GET("", c.GetMySessions)
+			me.DELETE("/:id", middleware.ForbidImpersonation(), c.RevokeMySession)
+		}
+
+		api.GET("/users/:id/sessions", middleware.RequirePermission(service.PermissionSessionsRead), c.GetUserSessions)
+		api.DELETE("/users/:id/sessions/:session_id", middleware.RequirePermission(service.PermissionSessionsRevoke), c.RevokeUserSession)
+	}
+}
@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionController struct {
+	*BaseController
+	subscriptionService service.SubscriptionService
+	authMiddleware      *middleware.AuthMiddleware
+}
+
+func NewSubscriptionController(subscriptionService service.SubscriptionService, authMiddleware *middleware.AuthMiddleware) *SubscriptionController {
+	return &SubscriptionController{
+		BaseController:      NewBaseController(),
+		subscriptionService: subscriptionService,
+		authMiddleware:      authMiddleware,
+	}
+}
+
+// GetMySubscription godoc
+// @Summary Get my subscription
+// @Description Get the current user's active subscription with its plan and current billing period
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/me/subscription [get]
+func (c *SubscriptionController) GetMySubscription(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	subscription, err := c.subscriptionService.GetSubscription(ctx.Request.Context(), principal.User.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			utils.NotFound(ctx, "Subscription not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to get subscription", err)
+		return
+	}
+
+	utils.Success(ctx, "Subscription retrieved successfully", subscription)
+}
+
+// Subscribe godoc
+// @Summary Subscribe to a plan
+// @Description Start a subscription for the current user. The first period starts now and lasts one plan interval.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param subscription body service.SubscriptionPlanRequest true "Plan to subscribe to"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/users/me/subscription [post]
+func (c *SubscriptionController) Subscribe(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	var req service.SubscriptionPlanRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	subscription, err := c.subscriptionService.Subscribe(ctx.Request.Context(), principal.User.ID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrAlreadySubscribed) {
+			utils.Conflict(ctx, "Already subscribed", err)
+			return
+		}
+		if errors.Is(err, service.ErrPlanNotFound) {
+			utils.BadRequest(ctx, "Invalid request body", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to subscribe", err)
+		return
+	}
+
+	utils.Created(ctx, "Subscribed successfully", subscription)
+}
+
+// PreviewPlanChange godoc
+// @Summary Preview a plan change
+// @Description Show the proration of switching the current user's subscription to another plan without changing it. Credit is the unused part of the current plan, charge the new plan until the end of the period, amount_due the difference (negative when the user is owed a credit).
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param plan_id query int true "Plan to switch to"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/me/subscription/proration [get]
+func (c *SubscriptionController) PreviewPlanChange(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	planID, err := strconv.Atoi(ctx.Query("plan_id"))
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid plan_id parameter", err)
+		return
+	}
+
+	proration, err := c.subscriptionService.PreviewPlanChange(ctx.Request.Context(), principal.User.ID, planID)
+	if err != nil {
+		c.handlePlanChangeError(ctx, err)
+		return
+	}
+
+	utils.Success(ctx, "Proration calculated successfully", proration)
+}
+
+// ChangePlan godoc
+// @Summary Change plan
+// @Description Upgrade or downgrade the current user's subscription right away. Within the same interval the billing period is kept and the change is prorated; switching between monthly and yearly starts a new period with the unused part credited. The response includes the proration recorded for billing.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param subscription body service.SubscriptionPlanRequest true "Plan to switch to"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/users/me/subscription [put]
+func (c *SubscriptionController) ChangePlan(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	var req service.SubscriptionPlanRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	change, err := c.subscriptionService.ChangePlan(ctx.Request.Context(), principal.User.ID, &req)
+	if err != nil {
+		c.handlePlanChangeError(ctx, err)
+		return
+	}
+
+	utils.Success(ctx, "Plan changed successfully", change)
+}
+
+// CancelSubscription godoc
+// @Summary Cancel my subscription
+// @Description Cancel the current user's subscription at the end of the current period, or right away with immediately set. Nothing is refunded. Changing plan before the period ends withdraws a pending cancellation.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param subscription body service.CancelSubscriptionRequest false "Cancellation options"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/users/me/subscription/cancel [post]
+func (c *SubscriptionController) CancelSubscription(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	// The body is optional, an empty one cancels at period end
+	var req service.CancelSubscriptionRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := c.BindJSON(ctx, &req); err != nil {
+			utils.BadRequest(ctx, "Invalid request body", err)
+			return
+		}
+	}
+
+	subscription, err := c.subscriptionService.Cancel(ctx.Request.Context(), principal.User.ID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			utils.NotFound(ctx, "Subscription not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrSubscriptionChanged) {
+			utils.Conflict(ctx, "Subscription changed", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to cancel subscription", err)
+		return
+	}
+
+	utils.Success(ctx, "Subscription canceled successfully", subscription)
+}
+
+func (c *SubscriptionController) handlePlanChangeError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSubscriptionNotFound):
+		utils.NotFound(ctx, "Subscription not found", err)
+	case errors.Is(err, service.ErrPlanNotFound), errors.Is(err, service.ErrSamePlan):
+		utils.BadRequest(ctx, "Invalid plan", err)
+	case errors.Is(err, service.ErrSubscriptionChanged):
+		utils.Conflict(ctx, "Subscription changed", err)
+	default:
+		utils.InternalServerError(ctx, "Failed to change plan", err)
+	}
+}
+
+func (c *SubscriptionController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	api.Use(c.authMiddleware.RequireAuth())
+	{
+		me := api.Group("/users/me/subscription")
+		{
+			me.GET("", c.GetMySubscription)
+			me.POST("", middleware.RequireSession(), middleware.ForbidImpersonation(), c.Subscribe)
+			me.GET("/proration", c.PreviewPlanChange)
+			me.PUT("", middleware.RequireSession(), middleware.ForbidImpersonation(), c.ChangePlan)
+			me.POST("/cancel", middleware.RequireSession(), middleware.ForbidImpersonation(), c.CancelSubscription)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package controller
+
+import (
+	"errors"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorController struct {
+	*BaseController
+	twoFactorService service.TwoFactorService
+	authMiddleware   *middleware.AuthMiddleware
+}
+
+func NewTwoFactorController(twoFactorService service.TwoFactorService, authMiddleware *middleware.AuthMiddleware) *TwoFactorController {
+	return &TwoFactorController{
+		BaseController:   NewBaseController(),
+		twoFactorService: twoFactorService,
+		authMiddleware:   authMiddleware,
+	}
+}
+
+// GetStatus godoc
+// @Summary Two-factor status
+// @Description Show whether two-factor authentication is enabled for the current user and how many recovery codes are left
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/users/me/2fa [get]
+func (c *TwoFactorController) GetStatus(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	status, err := c.twoFactorService.GetStatus(ctx.Request.Context(), principal.User.ID)
+	if err != nil {
+		utils.InternalServerError(ctx, "Failed to get two-factor status", err)
+		return
+	}
+
+	utils.Success(ctx, "Two-factor status retrieved successfully", status)
+}
+
+// Setup godoc
+// @Summary Start two-factor setup
+// @Description Generate a new authenticator secret and otpauth URI for a QR code. Two-factor authentication is enabled only after the confirm step.
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/users/me/2fa/setup [post]
+func (c *TwoFactorController) Setup(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	setup, err := c.twoFactorService.Setup(ctx.Request.Context(), principal.User.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrTwoFactorAlreadyEnabled) {
+			utils.Conflict(ctx, "Failed to start two-factor setup", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to start two-factor setup", err)
+		return
+	}
+
+	utils.Success(ctx, "Scan the QR code and confirm with a code from your authenticator app", setup)
+}
+
+// Confirm godoc
+// @Summary Confirm two-factor setup
+// @Description Enable two-factor authentication with a code from the authenticator app. The response contains recovery codes that are shown only once.
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.TwoFactorCodeRequest true "Authenticator code"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /api/users/me/2fa/confirm [post]
+func (c *TwoFactorController) Confirm(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	var req service.TwoFactorCodeRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	codes, err := c.twoFactorService.Confirm(ctx.Request.Context(), principal.User.ID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrTwoFactorAlreadyEnabled) {
+			utils.Conflict(ctx, "Failed to enable two-factor authentication", err)
+			return
+		}
+		if errors.Is(err, service.ErrTwoFactorSetupRequired) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+			utils.BadRequest(ctx, "Failed to enable two-factor authentication", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to enable two-factor authentication", err)
+		return
+	}
+
+	utils.Success(ctx, "Two-factor authentication enabled, store the recovery codes somewhere safe", codes)
+}
+
+// Disable godoc
+// @Summary Disable two-factor authentication
+// @Description Turn off two-factor authentication for the current user. Requires the password and an authenticator or recovery code.
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.DisableTwoFactorRequest true "Password and code"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /api/users/me/2fa/disable [post]
+func (c *TwoFactorController) Disable(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	var req service.DisableTwoFactorRequest
+	if err := c.BindJSON(ctx, &req); err != nil {
+		utils.BadRequest(ctx, "Invalid request body", err)
+		return
+	}
+
+	if err := c.twoFactorService.Disable(ctx.Request.Context(), principal.User.ID, &req); err != nil {
+		if errors.Is(err, service.ErrIncorrectPassword) || errors.Is(err, service.ErrInvalidTwoFactorCode) || errors.Is(err, service.ErrTwoFactorNotEnabled) {
+			utils.BadRequest(ctx, "Failed to disable two-factor authentication", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to disable two-factor authentication", err)
+		return
+	}
+
+	utils.Success(ctx, "Two-factor authentication disabled", nil)
+}
+
+// Reset godoc
+// @Summary Reset a user's two-factor authentication
+// @Description Remove the authenticator and recovery codes of another user, for example after a lost device. Requires users:reset_2fa.
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/{id}/2fa [delete]
+func (c *TwoFactorController) Reset(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid user ID", err)
+		return
+	}
+
+	if err := c.twoFactorService.Reset(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			utils.NotFound(ctx, "User not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrTwoFactorNotEnabled) {
+			utils.NotFound(ctx, "Two-factor authentication is not enabled", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to reset two-factor authentication", err)
+		return
+	}
+
+	utils.Success(ctx, "Two-factor authentication reset successfully", gin.H{
+		"id": id,
+	})
+}
+
+func (c *TwoFactorController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	api.Use(c.authMiddleware.RequireAuth())
+	{
+		me := api.Group("/users/me/2fa")
+		me.Use(middleware.RequireSession())
+		{
+			me.GET("", c.GetStatus)
+			me.POST("/setup", middleware.ForbidImpersonation(), c.Setup)
+			me.POST("/confirm", middleware.ForbidImpersonation(), c.Confirm)
+			me.POST("/disable", middleware.ForbidImpersonation(), c.Disable)
+		}
+
+		api.DELETE("/users/:id/2fa", middleware.RequirePermission(service.PermissionUsersResetTwoFactor), c.Reset)
+	}
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"errors"
+
+	"orchid_be/internal/middleware"
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UsageController struct {
+	*BaseController
+	usageService   service.UsageService
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewUsageController(usageService service.UsageService, authMiddleware *middleware.AuthMiddleware) *UsageController {
+	return &UsageController{
+		BaseController: NewBaseController(),
+		usageService:   usageService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// GetMyUsage godoc
+// @Summary Get my usage
+// @Description Get the current user's usage of every metered limit in the current billing period, with the limits of their plan
+// @Tags usage
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api/users/me/usage [get]
+func (c *UsageController) GetMyUsage(ctx *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(ctx)
+	if !ok {
+		utils.Unauthorized(ctx, "Authentication required", nil)
+		return
+	}
+
+	c.sendUsage(ctx, principal.User.ID)
+}
+
+// GetUserUsage godoc
+// @Summary Get a user's usage
+// @Description Get any user's usage of every metered limit in the current billing period. Requires usage:read unless it is the user's own.
+// @Tags usage
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/{id}/usage [get]
+func (c *UsageController) GetUserUsage(ctx *gin.Context) {
+	userID, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid user ID", err)
+		return
+	}
+
+	c.sendUsage(ctx, userID)
+}
+
+func (c *UsageController) sendUsage(ctx *gin.Context, userID int) {
+	usage, err := c.usageService.GetUsage(ctx.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			utils.NotFound(ctx, "User not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to get usage", err)
+		return
+	}
+
+	utils.Success(ctx, "Usage retrieved successfully", usage)
+}
+
+func (c *UsageController) SetupRoutes(router *gin.Engine) {
+	api := router.Group("/api")
+	api.Use(c.authMiddleware.RequireAuth())
+	{
+		api.GET("/users/me/usage", c.GetMyUsage)
+		api.GET("/users/:id/usage", middleware.RequirePermissionOrSelf(service.PermissionUsageRead), c.GetUserUsage)
+	}
+}
@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"errors"
+
+	"orchid_be/internal/middleware"
 	"orchid_be/internal/service"
 	"orchid_be/internal/utils"
 
@@ -9,32 +12,52 @@ import (
 
 type UserController struct {
 	*BaseController
-	userService service.UserService
+	userService    service.UserService
+	authMiddleware *middleware.AuthMiddleware
 }
 
-func NewUserController(userService service.UserService) *UserController {
+func NewUserController(userService service.UserService, authMiddleware *middleware.AuthMiddleware) *UserController {
 	return &UserController{
 		BaseController: NewBaseController(),
 		userService:    userService,
+		authMiddleware: authMiddleware,
 	}
 }
 
 // GetUsers godoc
 // @Summary Get all users
-// @Description Get paginated list of users
+// @Description Get paginated list of users. Requires the users:list permission.
+// @Description Sort on id, name, email, status, position, country, created_at or updated_at; newest first by default.
+// @Description Filter on name, email, status, position, country, created_at or updated_at with ?column=value or ?column[op]=value, where op is eq, ne, gt, gte, lt, lte or in (comma separated values), e.g. ?status[in]=active,pending&created_at[gte]=2026-01-01T00:00:00Z.
+// @Description q searches name, email and position, ignoring case.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param sort query string false "Comma separated columns to sort by, descending when prefixed with -" example(-name,email)
+// @Param order query string false "Sort direction of columns without a prefix" Enums(asc, desc)
+// @Param q query string false "Case-insensitive text to search for in name, email and position"
+// @Param status query string false "Filter on status" Enums(active, inactive, pending)
+// @Param country query string false "Filter on ISO 3166-1 alpha-2 country code" example(US)
+// @Param created_at[gte] query string false "Only users created at or after this RFC 3339 time" example(2026-01-01T00:00:00Z)
+// @Param created_at[lt] query string false "Only users created before this RFC 3339 time" example(2026-02-01T00:00:00Z)
 // @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /api/users [get]
 func (c *UserController) GetUsers(ctx *gin.Context) {
 	page, limit := c.GetPageAndLimitFromQuery(ctx)
 
-	users, total, err := c.userService.GetAllUsers(ctx.Request.Context(), page, limit)
+	users, total, err := c.userService.GetAllUsers(ctx.Request.Context(), listRequest(ctx, page, limit))
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidListQuery) {
+			utils.BadRequest(ctx, "Invalid query", err)
+			return
+		}
 		utils.InternalServerError(ctx, "Failed to get users", err)
 		return
 	}
@@ -44,13 +67,16 @@ func (c *UserController) GetUsers(ctx *gin.Context) {
 
 // GetUserByID godoc
 // @Summary Get user by ID
-// @Description Get a single user by their ID
+// @Description Get a single user by their ID. Users can always read their own record; anyone else needs users:read.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "User ID"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Failure 404 {object} utils.Response
 // @Router /api/users/{id} [get]
 func (c *UserController) GetUserByID(ctx *gin.Context) {
@@ -71,13 +97,16 @@ func (c *UserController) GetUserByID(ctx *gin.Context) {
 
 // CreateUser godoc
 // @Summary Create new user
-// @Description Create a new user with name, email and password
+// @Description Create a new user with name, email and password. Requires the users:create permission.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param user body service.CreateUserRequest true "User data"
 // @Success 201 {object} utils.Response
 // @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Router /api/users [post]
 func (c *UserController) CreateUser(ctx *gin.Context) {
 	var req service.CreateUserRequest
@@ -98,14 +127,17 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 
 // UpdateUser godoc
 // @Summary Update user
-// @Description Update user information by ID
+// @Description Update user information by ID. Users can always edit their own record; anyone else needs users:update, which is also required to change the status. Country must be an ISO 3166-1 alpha-2 code. Changing the email address requires a recent password confirmation via /api/auth/elevation; the new address is unverified until the link sent to it is confirmed.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "User ID"
 // @Param user body service.UpdateUserRequest true "User data"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Failure 404 {object} utils.Response
 // @Router /api/users/{id} [put]
 func (c *UserController) UpdateUser(ctx *gin.Context) {
@@ -123,6 +155,14 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 
 	user, err := c.userService.UpdateUser(ctx.Request.Context(), id, &req)
 	if err != nil {
+		if errors.Is(err, service.ErrReauthenticationRequired) {
+			utils.Forbidden(ctx, "Confirm your password to continue", err)
+			return
+		}
+		if errors.Is(err, service.ErrStatusChangeNotAllowed) {
+			utils.Forbidden(ctx, "Insufficient permissions", err)
+			return
+		}
 		utils.BadRequest(ctx, "Failed to update user", err)
 		return
 	}
@@ -132,13 +172,16 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 
 // DeleteUser godoc
 // @Summary Delete user
-// @Description Delete a user by ID
+// @Description Delete a user by ID. Requires the users:delete permission and a recent password confirmation via /api/auth/elevation.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "User ID"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Failure 404 {object} utils.Response
 // @Router /api/users/{id} [delete]
 func (c *UserController) DeleteUser(ctx *gin.Context) {
@@ -158,16 +201,52 @@ func (c *UserController) DeleteUser(ctx *gin.Context) {
 	})
 }
 
+// UnlockUser godoc
+// @Summary Unlock user
+// @Description Clear failed login attempts and lift a temporary lockout. Requires the users:unlock permission.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api/users/{id}/unlock [post]
+func (c *UserController) UnlockUser(ctx *gin.Context) {
+	id, err := c.GetIDFromURL(ctx)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid user ID", err)
+		return
+	}
+
+	user, err := c.userService.UnlockUser(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			utils.NotFound(ctx, "User not found", err)
+			return
+		}
+		utils.InternalServerError(ctx, "Failed to unlock user", err)
+		return
+	}
+
+	utils.Success(ctx, "User unlocked successfully", user)
+}
+
 func (c *UserController) SetupRoutes(router *gin.Engine) {
 	api := router.Group("/api")
 	{
 		users := api.Group("/users")
+		users.Use(c.authMiddleware.RequireAuth())
 		{
-			users.GET("", c.GetUsers)
-			users.GET("/:id", c.GetUserByID)
-			users.POST("", c.CreateUser)
-			users.PUT("/:id", c.UpdateUser)
-			users.DELETE("/:id", c.DeleteUser)
+			users.GET("", middleware.RequirePermission(service.PermissionUsersList), c.GetUsers)
+			users.GET("/:id", middleware.RequirePermissionOrSelf(service.PermissionUsersRead), c.GetUserByID)
+			users.POST("", middleware.RequirePermission(service.PermissionUsersCreate), c.CreateUser)
+			users.PUT("/:id", middleware.RequirePermissionOrSelf(service.PermissionUsersUpdate), c.UpdateUser)
+			users.DELETE("/:id", middleware.ForbidImpersonation(), middleware.RequirePermission(service.PermissionUsersDelete), middleware.RequireElevation(), c.DeleteUser)
+			users.POST("/:id/unlock", middleware.RequirePermission(service.PermissionUsersUnlock), c.UnlockUser)
 		}
 	}
 }
@@ -6,13 +6,330 @@ package db
 
 import (
 	"database/sql"
+	"time"
 )
 
-type User struct {
+type ApiKey struct {
+	ID         int32        `json:"id"`
+	UserID     int32        `json:"user_id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	KeyHash    string       `json:"key_hash"`
+	ExpiresAt  sql.NullTime `json:"expires_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+	CreatedAt  sql.NullTime `json:"created_at"`
+}
+
+type ApiKeyPermission struct {
+	ApiKeyID     int32 `json:"api_key_id"`
+	PermissionID int32 `json:"permission_id"`
+}
+
+type Category struct {
+	ID        int32        `json:"id"`
+	Name      string       `json:"name"`
+	CreatedAt sql.NullTime `json:"created_at"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+}
+
+type Impersonation struct {
+	ID        int32        `json:"id"`
+	ActorID   int32        `json:"actor_id"`
+	SubjectID int32        `json:"subject_id"`
+	Reason    string       `json:"reason"`
+	IpAddress string       `json:"ip_address"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	EndedAt   sql.NullTime `json:"ended_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type ImpersonationEvent struct {
+	ID              int32        `json:"id"`
+	ImpersonationID int32        `json:"impersonation_id"`
+	Action          string       `json:"action"`
+	Method          string       `json:"method"`
+	Path            string       `json:"path"`
+	StatusCode      int32        `json:"status_code"`
+	IpAddress       string       `json:"ip_address"`
+	CreatedAt       sql.NullTime `json:"created_at"`
+}
+
+type InventoryLevel struct {
+	ProductID         int32        `json:"product_id"`
+	OnHand            int32        `json:"on_hand"`
+	LowStockThreshold int32        `json:"low_stock_threshold"`
+	UpdatedAt         sql.NullTime `json:"updated_at"`
+}
+
+type Invoice struct {
+	ID             int32          `json:"id"`
+	Number         sql.NullString `json:"number"`
+	UserID         sql.NullInt32  `json:"user_id"`
+	SubscriptionID sql.NullInt32  `json:"subscription_id"`
+	Status         string         `json:"status"`
+	CustomerName   string         `json:"customer_name"`
+	CustomerEmail  string         `json:"customer_email"`
+	Country        string         `json:"country"`
+	PeriodStart    time.Time      `json:"period_start"`
+	PeriodEnd      time.Time      `json:"period_end"`
+	Subtotal       string         `json:"subtotal"`
+	TaxName        string         `json:"tax_name"`
+	TaxRate        string         `json:"tax_rate"`
+	TaxAmount      string         `json:"tax_amount"`
+	Total          string         `json:"total"`
+	IssuedAt       sql.NullTime   `json:"issued_at"`
+	DueAt          sql.NullTime   `json:"due_at"`
+	PaidAt         sql.NullTime   `json:"paid_at"`
+	VoidedAt       sql.NullTime   `json:"voided_at"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	UpdatedAt      sql.NullTime   `json:"updated_at"`
+}
+
+type InvoiceLineItem struct {
+	ID          int32  `json:"id"`
+	InvoiceID   int32  `json:"invoice_id"`
+	Description string `json:"description"`
+	Quantity    int32  `json:"quantity"`
+	UnitPrice   string `json:"unit_price"`
+	Amount      string `json:"amount"`
+}
+
+type InvoiceNumberCounter struct {
+	Year       int32 `json:"year"`
+	LastNumber int32 `json:"last_number"`
+}
+
+type MagicLinkToken struct {
+	ID        int32        `json:"id"`
+	UserID    int32        `json:"user_id"`
+	TokenHash string       `json:"token_hash"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type OidcLoginState struct {
+	StateHash    string       `json:"state_hash"`
+	Provider     string       `json:"provider"`
+	CodeVerifier string       `json:"code_verifier"`
+	Nonce        string       `json:"nonce"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	CreatedAt    sql.NullTime `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        int32        `json:"id"`
+	UserID    int32        `json:"user_id"`
+	TokenHash string       `json:"token_hash"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type Permission struct {
+	ID          int32        `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+}
+
+type Plan struct {
+	ID              int32        `json:"id"`
+	Name            string       `json:"name"`
+	Description     string       `json:"description"`
+	Price           string       `json:"price"`
+	BillingInterval string       `json:"billing_interval"`
+	SupportMonths   int32        `json:"support_months"`
+	UpdateMonths    int32        `json:"update_months"`
+	IsActive        bool         `json:"is_active"`
+	CreatedAt       sql.NullTime `json:"created_at"`
+	UpdatedAt       sql.NullTime `json:"updated_at"`
+}
+
+type PlanLimit struct {
+	PlanID   int32  `json:"plan_id"`
+	Name     string `json:"name"`
+	MaxValue int32  `json:"max_value"`
+}
+
+type Product struct {
 	ID           int32        `json:"id"`
 	Name         string       `json:"name"`
-	Email        string       `json:"email"`
-	PasswordHash string       `json:"password_hash"`
+	Description  string       `json:"description"`
+	Price        string       `json:"price"`
+	Discount     string       `json:"discount"`
 	CreatedAt    sql.NullTime `json:"created_at"`
 	UpdatedAt    sql.NullTime `json:"updated_at"`
+	CategoryID   int32        `json:"category_id"`
+	TechnologyID int32        `json:"technology_id"`
+}
+
+type ProductPrice struct {
+	ID        int32          `json:"id"`
+	ProductID int32          `json:"product_id"`
+	Price     sql.NullString `json:"price"`
+	Discount  sql.NullString `json:"discount"`
+	StartsAt  time.Time      `json:"starts_at"`
+	EndsAt    sql.NullTime   `json:"ends_at"`
+	CreatedBy sql.NullInt32  `json:"created_by"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+}
+
+type RecoveryCode struct {
+	ID        int32        `json:"id"`
+	UserID    int32        `json:"user_id"`
+	CodeHash  string       `json:"code_hash"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type RefreshToken struct {
+	ID        int32        `json:"id"`
+	UserID    int32        `json:"user_id"`
+	TokenHash string       `json:"token_hash"`
+	FamilyID  string       `json:"family_id"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	RotatedAt sql.NullTime `json:"rotated_at"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type Role struct {
+	ID          int32        `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+	UpdatedAt   sql.NullTime `json:"updated_at"`
+}
+
+type RolePermission struct {
+	RoleID       int32 `json:"role_id"`
+	PermissionID int32 `json:"permission_id"`
+}
+
+type Session struct {
+	ID            int32        `json:"id"`
+	UserID        int32        `json:"user_id"`
+	FamilyID      string       `json:"family_id"`
+	Device        string       `json:"device"`
+	UserAgent     string       `json:"user_agent"`
+	IpAddress     string       `json:"ip_address"`
+	ExpiresAt     time.Time    `json:"expires_at"`
+	LastSeenAt    sql.NullTime `json:"last_seen_at"`
+	RevokedAt     sql.NullTime `json:"revoked_at"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	ElevatedUntil sql.NullTime `json:"elevated_until"`
+}
+
+type StockMovement struct {
+	ID           int32         `json:"id"`
+	ProductID    int32         `json:"product_id"`
+	Type         string        `json:"type"`
+	Quantity     int32         `json:"quantity"`
+	BalanceAfter int32         `json:"balance_after"`
+	Note         string        `json:"note"`
+	CreatedBy    sql.NullInt32 `json:"created_by"`
+	CreatedAt    sql.NullTime  `json:"created_at"`
+}
+
+type Subscription struct {
+	ID                 int32        `json:"id"`
+	UserID             int32        `json:"user_id"`
+	PlanID             int32        `json:"plan_id"`
+	Status             string       `json:"status"`
+	CurrentPeriodStart time.Time    `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time    `json:"current_period_end"`
+	CancelAtPeriodEnd  bool         `json:"cancel_at_period_end"`
+	CanceledAt         sql.NullTime `json:"canceled_at"`
+	CreatedAt          sql.NullTime `json:"created_at"`
+	UpdatedAt          sql.NullTime `json:"updated_at"`
+}
+
+type SubscriptionChange struct {
+	ID             int32         `json:"id"`
+	SubscriptionID int32         `json:"subscription_id"`
+	FromPlanID     int32         `json:"from_plan_id"`
+	ToPlanID       int32         `json:"to_plan_id"`
+	Credit         string        `json:"credit"`
+	Charge         string        `json:"charge"`
+	AmountDue      string        `json:"amount_due"`
+	CreatedAt      sql.NullTime  `json:"created_at"`
+	InvoiceID      sql.NullInt32 `json:"invoice_id"`
+}
+
+type TaxRate struct {
+	Country   string       `json:"country"`
+	Name      string       `json:"name"`
+	Rate      string       `json:"rate"`
+	CreatedAt sql.NullTime `json:"created_at"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+}
+
+type Technology struct {
+	ID        int32        `json:"id"`
+	Name      string       `json:"name"`
+	CreatedAt sql.NullTime `json:"created_at"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+}
+
+type TotpSecret struct {
+	UserID       int32        `json:"user_id"`
+	Secret       string       `json:"secret"`
+	ConfirmedAt  sql.NullTime `json:"confirmed_at"`
+	LastUsedStep int64        `json:"last_used_step"`
+	CreatedAt    sql.NullTime `json:"created_at"`
+	UpdatedAt    sql.NullTime `json:"updated_at"`
+}
+
+type UsageCounter struct {
+	UserID      int32        `json:"user_id"`
+	Metric      string       `json:"metric"`
+	PeriodStart time.Time    `json:"period_start"`
+	PeriodEnd   time.Time    `json:"period_end"`
+	Quantity    int64        `json:"quantity"`
+	UpdatedAt   sql.NullTime `json:"updated_at"`
+}
+
+type UsageEvent struct {
+	ID          int64        `json:"id"`
+	UserID      int32        `json:"user_id"`
+	Metric      string       `json:"metric"`
+	Quantity    int32        `json:"quantity"`
+	PeriodStart time.Time    `json:"period_start"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+}
+
+type User struct {
+	ID                  int32        `json:"id"`
+	Name                string       `json:"name"`
+	Email               string       `json:"email"`
+	PasswordHash        string       `json:"password_hash"`
+	CreatedAt           sql.NullTime `json:"created_at"`
+	UpdatedAt           sql.NullTime `json:"updated_at"`
+	Status              string       `json:"status"`
+	EmailVerifiedAt     sql.NullTime `json:"email_verified_at"`
+	FailedLoginAttempts int32        `json:"failed_login_attempts"`
+	LastFailedLoginAt   sql.NullTime `json:"last_failed_login_at"`
+	LockedUntil         sql.NullTime `json:"locked_until"`
+	Avatar              string       `json:"avatar"`
+	Biography           string       `json:"biography"`
+	Position            string       `json:"position"`
+	Country             string       `json:"country"`
+}
+
+type UserIdentity struct {
+	ID          int32        `json:"id"`
+	UserID      int32        `json:"user_id"`
+	Provider    string       `json:"provider"`
+	Subject     string       `json:"subject"`
+	Email       string       `json:"email"`
+	LastLoginAt sql.NullTime `json:"last_login_at"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+}
+
+type UserRole struct {
+	UserID    int32        `json:"user_id"`
+	RoleID    int32        `json:"role_id"`
+	CreatedAt sql.NullTime `json:"created_at"`
 }
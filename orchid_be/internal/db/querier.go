@@ -6,16 +6,147 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
-	CountUsers(ctx context.Context) (int64, error)
+	AddApiKeyPermission(ctx context.Context, arg AddApiKeyPermissionParams) error
+	AddRolePermission(ctx context.Context, arg AddRolePermissionParams) error
+	ApplyStockChange(ctx context.Context, arg ApplyStockChangeParams) (InventoryLevel, error)
+	AssignUserRole(ctx context.Context, arg AssignUserRoleParams) error
+	CancelSubscription(ctx context.Context, arg CancelSubscriptionParams) (Subscription, error)
+	ConfirmTotpSecret(ctx context.Context, arg ConfirmTotpSecretParams) (int64, error)
+	ConsumeMagicLinkToken(ctx context.Context, arg ConsumeMagicLinkTokenParams) (MagicLinkToken, error)
+	ConsumeOidcLoginState(ctx context.Context, stateHash string) (OidcLoginState, error)
+	CountActiveApiKeys(ctx context.Context, arg CountActiveApiKeysParams) (int64, error)
+	CountImpersonations(ctx context.Context) (int64, error)
+	CountInvoices(ctx context.Context) (int64, error)
+	CountIssuedInvoicesByUserID(ctx context.Context, userID sql.NullInt32) (int64, error)
+	CountLowStockProducts(ctx context.Context) (int64, error)
+	CountProductPrices(ctx context.Context, productID int32) (int64, error)
+	CountProducts(ctx context.Context) (int64, error)
+	CountRoleUsers(ctx context.Context, roleID int32) (int64, error)
+	CountStockMovements(ctx context.Context, productID int32) (int64, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID int32) (int64, error)
+	CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error)
+	CreateImpersonation(ctx context.Context, arg CreateImpersonationParams) (Impersonation, error)
+	CreateImpersonationEvent(ctx context.Context, arg CreateImpersonationEventParams) error
+	CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error)
+	CreateInvoiceLineItem(ctx context.Context, arg CreateInvoiceLineItemParams) (InvoiceLineItem, error)
+	CreateMagicLinkToken(ctx context.Context, arg CreateMagicLinkTokenParams) (MagicLinkToken, error)
+	CreateOidcLoginState(ctx context.Context, arg CreateOidcLoginStateParams) error
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error)
+	CreateProductPrice(ctx context.Context, arg CreateProductPriceParams) (ProductPrice, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateRole(ctx context.Context, arg CreateRoleParams) (Role, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateStockMovement(ctx context.Context, arg CreateStockMovementParams) (StockMovement, error)
+	CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error)
+	CreateSubscriptionChange(ctx context.Context, arg CreateSubscriptionChangeParams) (SubscriptionChange, error)
+	CreateUsageEvent(ctx context.Context, arg CreateUsageEventParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	DeleteExpiredOidcLoginStates(ctx context.Context, expiresAt time.Time) error
+	DeletePendingProductPrice(ctx context.Context, arg DeletePendingProductPriceParams) (int64, error)
+	DeleteProduct(ctx context.Context, id int32) (int64, error)
+	DeleteRecoveryCodes(ctx context.Context, userID int32) error
+	DeleteRole(ctx context.Context, id int32) error
+	DeleteTaxRate(ctx context.Context, country string) (int64, error)
+	DeleteTotpSecret(ctx context.Context, userID int32) error
 	DeleteUser(ctx context.Context, id int32) error
-	GetAllUsers(ctx context.Context, arg GetAllUsersParams) ([]User, error)
+	EndImpersonation(ctx context.Context, arg EndImpersonationParams) (int64, error)
+	EnsureInventoryLevel(ctx context.Context, arg EnsureInventoryLevelParams) error
+	EnsureUsageCounter(ctx context.Context, arg EnsureUsageCounterParams) error
+	FinalizeInvoice(ctx context.Context, arg FinalizeInvoiceParams) (Invoice, error)
+	GetActiveSubscriptionByUserID(ctx context.Context, userID int32) (Subscription, error)
+	GetAllPricedProducts(ctx context.Context, arg GetAllPricedProductsParams) ([]GetAllPricedProductsRow, error)
+	GetApiKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	GetImpersonationByID(ctx context.Context, id int32) (Impersonation, error)
+	GetInventoryLevel(ctx context.Context, productID int32) (InventoryLevel, error)
+	GetInvoiceByID(ctx context.Context, id int32) (Invoice, error)
+	GetInvoiceBySubscriptionPeriod(ctx context.Context, arg GetInvoiceBySubscriptionPeriodParams) (Invoice, error)
+	GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	GetPermissionByName(ctx context.Context, name string) (Permission, error)
+	GetPlanByID(ctx context.Context, id int32) (Plan, error)
+	GetPlanLimits(ctx context.Context, planID int32) ([]PlanLimit, error)
+	GetPricedProduct(ctx context.Context, arg GetPricedProductParams) (GetPricedProductRow, error)
+	GetProductByID(ctx context.Context, id int32) (Product, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetRoleByID(ctx context.Context, id int32) (Role, error)
+	GetRoleByName(ctx context.Context, name string) (Role, error)
+	GetSessionByID(ctx context.Context, id int32) (Session, error)
+	GetTaxRate(ctx context.Context, country string) (TaxRate, error)
+	GetTotpSecret(ctx context.Context, userID int32) (TotpSecret, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id int32) (User, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error)
+	IncrementUsageCounter(ctx context.Context, arg IncrementUsageCounterParams) (UsageCounter, error)
+	InvalidateUserMagicLinkTokens(ctx context.Context, arg InvalidateUserMagicLinkTokensParams) error
+	InvalidateUserPasswordResetTokens(ctx context.Context, arg InvalidateUserPasswordResetTokensParams) error
+	ListActivePlans(ctx context.Context) ([]Plan, error)
+	ListActiveSubscriptions(ctx context.Context) ([]Subscription, error)
+	ListActiveUserSessions(ctx context.Context, arg ListActiveUserSessionsParams) ([]Session, error)
+	ListApiKeyScopes(ctx context.Context, apiKeyID int32) ([]string, error)
+	ListCategories(ctx context.Context) ([]Category, error)
+	ListDraftInvoices(ctx context.Context) ([]Invoice, error)
+	ListImpersonationEvents(ctx context.Context, impersonationID int32) ([]ImpersonationEvent, error)
+	ListImpersonations(ctx context.Context, arg ListImpersonationsParams) ([]Impersonation, error)
+	ListInvoiceLineItems(ctx context.Context, invoiceID int32) ([]InvoiceLineItem, error)
+	ListInvoices(ctx context.Context, arg ListInvoicesParams) ([]Invoice, error)
+	ListIssuedInvoicesByUserID(ctx context.Context, arg ListIssuedInvoicesByUserIDParams) ([]Invoice, error)
+	ListLowStockProducts(ctx context.Context, arg ListLowStockProductsParams) ([]ListLowStockProductsRow, error)
+	ListPermissions(ctx context.Context) ([]Permission, error)
+	ListPlanLimits(ctx context.Context) ([]PlanLimit, error)
+	ListPlans(ctx context.Context) ([]Plan, error)
+	ListProductPrices(ctx context.Context, arg ListProductPricesParams) ([]ProductPrice, error)
+	ListRolePermissions(ctx context.Context, roleID int32) ([]Permission, error)
+	ListRoles(ctx context.Context) ([]Role, error)
+	ListStockMovements(ctx context.Context, arg ListStockMovementsParams) ([]StockMovement, error)
+	ListTaxRates(ctx context.Context) ([]TaxRate, error)
+	ListTechnologies(ctx context.Context) ([]Technology, error)
+	ListUnbilledSubscriptionChanges(ctx context.Context, subscriptionID int32) ([]SubscriptionChange, error)
+	ListUsageCounters(ctx context.Context, arg ListUsageCountersParams) ([]UsageCounter, error)
+	ListUserApiKeys(ctx context.Context, userID int32) ([]ApiKey, error)
+	ListUserPermissionNames(ctx context.Context, userID int32) ([]string, error)
+	ListUserRoles(ctx context.Context, userID int32) ([]Role, error)
+	LockApiKeyOwner(ctx context.Context, userID int32) (int32, error)
+	LockRole(ctx context.Context, id int32) (string, error)
+	LockUser(ctx context.Context, arg LockUserParams) error
+	MarkInvoicePaid(ctx context.Context, arg MarkInvoicePaidParams) (Invoice, error)
+	MarkPasswordResetTokenUsed(ctx context.Context, arg MarkPasswordResetTokenUsedParams) (int64, error)
+	MarkRefreshTokenRotated(ctx context.Context, arg MarkRefreshTokenRotatedParams) (int64, error)
+	NextInvoiceNumber(ctx context.Context, year int32) (int32, error)
+	RecordFailedLogin(ctx context.Context, arg RecordFailedLoginParams) (int32, error)
+	RefreshSession(ctx context.Context, arg RefreshSessionParams) (Session, error)
+	RemoveUserRole(ctx context.Context, arg RemoveUserRoleParams) (int64, error)
+	ResetFailedLogins(ctx context.Context, id int32) error
+	RevokeApiKey(ctx context.Context, arg RevokeApiKeyParams) (int64, error)
+	RevokeOtherUserRefreshTokens(ctx context.Context, arg RevokeOtherUserRefreshTokensParams) error
+	RevokeOtherUserSessions(ctx context.Context, arg RevokeOtherUserSessionsParams) error
+	RevokeRefreshTokenFamily(ctx context.Context, arg RevokeRefreshTokenFamilyParams) error
+	RevokeSession(ctx context.Context, arg RevokeSessionParams) (string, error)
+	RevokeSessionByFamily(ctx context.Context, arg RevokeSessionByFamilyParams) error
+	RevokeUserRefreshTokens(ctx context.Context, arg RevokeUserRefreshTokensParams) error
+	RevokeUserSessions(ctx context.Context, arg RevokeUserSessionsParams) error
+	SetLowStockThreshold(ctx context.Context, arg SetLowStockThresholdParams) (InventoryLevel, error)
+	SetSessionElevation(ctx context.Context, arg SetSessionElevationParams) (int64, error)
+	SetSubscriptionChangeInvoice(ctx context.Context, arg SetSubscriptionChangeInvoiceParams) error
+	TouchApiKey(ctx context.Context, arg TouchApiKeyParams) error
+	TouchSession(ctx context.Context, arg TouchSessionParams) error
+	TouchUserIdentity(ctx context.Context, arg TouchUserIdentityParams) error
+	UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error)
+	UpdateSubscriptionPeriod(ctx context.Context, arg UpdateSubscriptionPeriodParams) (Subscription, error)
+	UpdateTotpLastUsedStep(ctx context.Context, arg UpdateTotpLastUsedStepParams) (int64, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpsertTaxRate(ctx context.Context, arg UpsertTaxRateParams) (TaxRate, error)
+	UpsertTotpSecret(ctx context.Context, arg UpsertTotpSecretParams) (TotpSecret, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
+	VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error)
+	VoidInvoice(ctx context.Context, arg VoidInvoiceParams) (Invoice, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -10,27 +10,21 @@ import (
 	"database/sql"
 )
 
-const countUsers = `-- name: CountUsers :one
-SELECT COUNT(*) FROM users
-`
-
-func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countUsers)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (name, email, password_hash, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, name, email, password_hash, created_at, updated_at
+INSERT INTO users (name, email, password_hash, status, avatar, biography, position, country, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, name, email, password_hash, created_at, updated_at, status, email_verified_at, failed_login_attempts, last_failed_login_at, locked_until, avatar, biography, position, country
 `
 
 type CreateUserParams struct {
 	Name         string       `json:"name"`
 	Email        string       `json:"email"`
 	PasswordHash string       `json:"password_hash"`
+	Status       string       `json:"status"`
+	Avatar       string       `json:"avatar"`
+	Biography    string       `json:"biography"`
+	Position     string       `json:"position"`
+	Country      string       `json:"country"`
 	CreatedAt    sql.NullTime `json:"created_at"`
 	UpdatedAt    sql.NullTime `json:"updated_at"`
 }
@@ -40,6 +34,11 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.Name,
 		arg.Email,
 		arg.PasswordHash,
+		arg.Status,
+		arg.Avatar,
+		arg.Biography,
+		arg.Position,
+		arg.Country,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -51,6 +50,15 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Avatar,
+		&i.Biography,
+		&i.Position,
+		&i.Country,
 	)
 	return i, err
 }
@@ -64,50 +72,8 @@ func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
 	return err
 }
 
-const getAllUsers = `-- name: GetAllUsers :many
-SELECT id, name, email, password_hash, created_at, updated_at
-FROM users
-ORDER BY created_at DESC
-LIMIT $1 OFFSET $2
-`
-
-type GetAllUsersParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
-}
-
-func (q *Queries) GetAllUsers(ctx context.Context, arg GetAllUsersParams) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, getAllUsers, arg.Limit, arg.Offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []User
-	for rows.Next() {
-		var i User
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Email,
-			&i.PasswordHash,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, password_hash, created_at, updated_at
+SELECT id, name, email, password_hash, created_at, updated_at, status, email_verified_at, failed_login_attempts, last_failed_login_at, locked_until, avatar, biography, position, country
 FROM users
 WHERE email = $1 LIMIT 1
 `
@@ -122,12 +88,21 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Avatar,
+		&i.Biography,
+		&i.Position,
+		&i.Country,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, name, email, password_hash, created_at, updated_at
+SELECT id, name, email, password_hash, created_at, updated_at, status, email_verified_at, failed_login_attempts, last_failed_login_at, locked_until, avatar, biography, position, country
 FROM users
 WHERE id = $1 LIMIT 1
 `
@@ -142,15 +117,76 @@ func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Avatar,
+		&i.Biography,
+		&i.Position,
+		&i.Country,
 	)
 	return i, err
 }
 
+const lockUser = `-- name: LockUser :exec
+UPDATE users
+SET locked_until = $2
+WHERE id = $1
+`
+
+type LockUserParams struct {
+	ID          int32        `json:"id"`
+	LockedUntil sql.NullTime `json:"locked_until"`
+}
+
+func (q *Queries) LockUser(ctx context.Context, arg LockUserParams) error {
+	_, err := q.db.ExecContext(ctx, lockUser, arg.ID, arg.LockedUntil)
+	return err
+}
+
+const recordFailedLogin = `-- name: RecordFailedLogin :one
+UPDATE users
+SET failed_login_attempts = CASE
+        WHEN last_failed_login_at IS NULL OR last_failed_login_at < $1 THEN 1
+        ELSE failed_login_attempts + 1
+    END,
+    last_failed_login_at = $2
+WHERE id = $3
+RETURNING failed_login_attempts
+`
+
+type RecordFailedLoginParams struct {
+	WindowStart sql.NullTime `json:"window_start"`
+	FailedAt    sql.NullTime `json:"failed_at"`
+	ID          int32        `json:"id"`
+}
+
+func (q *Queries) RecordFailedLogin(ctx context.Context, arg RecordFailedLoginParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, recordFailedLogin, arg.WindowStart, arg.FailedAt, arg.ID)
+	var failedLoginAttempts int32
+	err := row.Scan(&failedLoginAttempts)
+	return failedLoginAttempts, err
+}
+
+const resetFailedLogins = `-- name: ResetFailedLogins :exec
+UPDATE users
+SET failed_login_attempts = 0, last_failed_login_at = NULL, locked_until = NULL
+WHERE id = $1
+`
+
+func (q *Queries) ResetFailedLogins(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, resetFailedLogins, id)
+	return err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
-SET name = $2, email = $3, password_hash = $4, updated_at = $5
+SET name = $2, email = $3, password_hash = $4, status = $5, avatar = $6, biography = $7, position = $8, country = $9, updated_at = $10,
+    email_verified_at = CASE WHEN email = $3 THEN email_verified_at END
 WHERE id = $1
-RETURNING id, name, email, password_hash, created_at, updated_at
+RETURNING id, name, email, password_hash, created_at, updated_at, status, email_verified_at, failed_login_attempts, last_failed_login_at, locked_until, avatar, biography, position, country
 `
 
 type UpdateUserParams struct {
@@ -158,6 +194,11 @@ type UpdateUserParams struct {
 	Name         string       `json:"name"`
 	Email        string       `json:"email"`
 	PasswordHash string       `json:"password_hash"`
+	Status       string       `json:"status"`
+	Avatar       string       `json:"avatar"`
+	Biography    string       `json:"biography"`
+	Position     string       `json:"position"`
+	Country      string       `json:"country"`
 	UpdatedAt    sql.NullTime `json:"updated_at"`
 }
 
@@ -167,6 +208,11 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Name,
 		arg.Email,
 		arg.PasswordHash,
+		arg.Status,
+		arg.Avatar,
+		arg.Biography,
+		arg.Position,
+		arg.Country,
 		arg.UpdatedAt,
 	)
 	var i User
@@ -177,6 +223,87 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Avatar,
+		&i.Biography,
+		&i.Position,
+		&i.Country,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE users
+SET password_hash = $2, updated_at = $3
+WHERE id = $1
+RETURNING id, name, email, password_hash, created_at, updated_at, status, email_verified_at, failed_login_attempts, last_failed_login_at, locked_until, avatar, biography, position, country
+`
+
+type UpdateUserPasswordParams struct {
+	ID           int32        `json:"id"`
+	PasswordHash string       `json:"password_hash"`
+	UpdatedAt    sql.NullTime `json:"updated_at"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserPassword, arg.ID, arg.PasswordHash, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Avatar,
+		&i.Biography,
+		&i.Position,
+		&i.Country,
+	)
+	return i, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users
+SET status = $2, email_verified_at = $3, updated_at = $3
+WHERE id = $1
+RETURNING id, name, email, password_hash, created_at, updated_at, status, email_verified_at, failed_login_attempts, last_failed_login_at, locked_until, avatar, biography, position, country
+`
+
+type VerifyUserEmailParams struct {
+	ID              int32        `json:"id"`
+	Status          string       `json:"status"`
+	EmailVerifiedAt sql.NullTime `json:"email_verified_at"`
+}
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, verifyUserEmail, arg.ID, arg.Status, arg.EmailVerifiedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Avatar,
+		&i.Biography,
+		&i.Position,
+		&i.Country,
 	)
 	return i, err
 }
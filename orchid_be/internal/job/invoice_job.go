@@ -0,0 +1,53 @@
+package job
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"orchid_be/internal/service"
+)
+
+// InvoiceJob generates the subscription invoices at midnight UTC on the first
+// day of every month.
+type InvoiceJob struct {
+	invoiceService service.InvoiceService
+}
+
+func NewInvoiceJob(invoiceService service.InvoiceService) *InvoiceJob {
+	return &InvoiceJob{
+		invoiceService: invoiceService,
+	}
+}
+
+// Start runs the job in the background until ctx is done. A run that was
+// missed while the server was down is not made up for; trigger it with
+// POST /api/invoices/generate instead.
+func (j *InvoiceJob) Start(ctx context.Context) {
+	go func() {
+		for {
+			timer := time.NewTimer(time.Until(nextMonthStart(time.Now())))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				j.run(ctx)
+			}
+		}
+	}()
+}
+
+func (j *InvoiceJob) run(ctx context.Context) {
+	result, err := j.invoiceService.GenerateInvoices(ctx, time.Now())
+	if err != nil {
+		log.Printf("Failed to generate invoices: %v", err)
+		return
+	}
+	log.Printf("Generated %d invoices, finalized %d", result.Created, result.Finalized)
+}
+
+func nextMonthStart(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
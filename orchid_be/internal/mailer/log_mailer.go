@@ -0,0 +1,20 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// LogMailer writes emails to the application log, for local development.
+type LogMailer struct {
+	from string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{from: from}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("Mail from %s to %s\nSubject: %s\n\n%s", m.from, msg.To, msg.Subject, msg.Body)
+	return nil
+}
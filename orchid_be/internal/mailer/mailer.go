@@ -0,0 +1,70 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"orchid_be/internal/config"
+)
+
+const (
+	DriverLog  = "log"
+	DriverFile = "file"
+	DriverSMTP = "smtp"
+)
+
+// sendTimeout bounds a delivery started by SendInBackground.
+const sendTimeout = 30 * time.Second
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers transactional emails such as account verification links.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+func NewMailer(cfg *config.MailerConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case DriverLog, "":
+		return NewLogMailer(cfg.From), nil
+	case DriverFile:
+		return NewFileMailer(cfg.From, cfg.OutputDir)
+	case DriverSMTP:
+		return NewSMTPMailer(cfg), nil
+	default:
+		return nil, fmt.Errorf("unsupported mailer driver %q", cfg.Driver)
+	}
+}
+
+// SendInBackground delivers msg without making the caller wait, so a request
+// takes about as long whether or not it sends an email. The delivery outlives
+// ctx, and failures are only logged.
+func SendInBackground(ctx context.Context, m Mailer, msg Message) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+	go func() {
+		defer cancel()
+		if err := m.Send(ctx, msg); err != nil {
+			log.Printf("Failed to send %q email to %s: %v", msg.Subject, msg.To, err)
+		}
+	}()
+}
+
+func buildMessage(from string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	return []byte(b.String())
+}
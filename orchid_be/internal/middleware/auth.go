@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"orchid_be/internal/service"
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errMissingToken    = errors.New("missing or malformed Authorization header")
+	errSessionRequired = errors.New("this action cannot be performed with an API key")
+	errImpersonating   = errors.New("this action cannot be performed while impersonating a user")
+)
+
+type AuthMiddleware struct {
+	authService          service.AuthService
+	apiKeyService        service.APIKeyService
+	impersonationService service.ImpersonationService
+	usageService         service.UsageService
+}
+
+func NewAuthMiddleware(authService service.AuthService, apiKeyService service.APIKeyService, impersonationService service.ImpersonationService, usageService service.UsageService) *AuthMiddleware {
+	return &AuthMiddleware{
+		authService:          authService,
+		apiKeyService:        apiKeyService,
+		impersonationService: impersonationService,
+		usageService:         usageService,
+	}
+}
+
+// RequireAuth rejects requests without a valid bearer access token or API key
+// and stores the authenticated principal in the request context. Requests
+// made with an API key count as api_calls of the key's owner and are rejected
+// with 402 once the plan's limit for the period is reached. Write requests
+// made while impersonating are added to the impersonation's audit trail once
+// they have been handled.
+func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, ok := bearerToken(ctx.GetHeader("Authorization"))
+		if !ok {
+			utils.Unauthorized(ctx, "Authentication required", errMissingToken)
+			ctx.Abort()
+			return
+		}
+
+		var principal *service.Principal
+		var err error
+		if service.IsAPIKey(token) {
+			principal, err = m.apiKeyService.Authenticate(ctx.Request.Context(), token)
+		} else {
+			principal, err = m.authService.Authenticate(ctx.Request.Context(), token)
+		}
+		if err != nil {
+			utils.Unauthorized(ctx, "Authentication required", err)
+			ctx.Abort()
+			return
+		}
+
+		if principal.IsAPIKey() {
+			err := m.usageService.RecordUsage(ctx.Request.Context(), principal.User.ID, service.UsageMetricAPICalls, 1)
+			if errors.Is(err, service.ErrPlanLimitExceeded) {
+				utils.PlanLimitExceeded(ctx, "API call limit of the current plan reached", err)
+				ctx.Abort()
+				return
+			}
+			// Metering problems must not take the API down
+			if err != nil {
+				log.Printf("Failed to record api call of user %d: %v", principal.User.ID, err)
+			}
+		}
+
+		ctx.Request = ctx.Request.WithContext(service.WithPrincipal(ctx.Request.Context(), principal))
+		ctx.Next()
+
+		if principal.IsImpersonated() && isWriteMethod(ctx.Request.Method) {
+			// Record the write even if the client already went away
+			recordCtx := context.WithoutCancel(ctx.Request.Context())
+			client := service.ClientInfo{UserAgent: ctx.Request.UserAgent(), IPAddress: ctx.ClientIP()}
+			err := m.impersonationService.RecordWrite(recordCtx, principal, ctx.Request.Method, ctx.Request.URL.Path, ctx.Writer.Status(), client)
+			if err != nil {
+				log.Printf("Failed to record write of impersonation %d: %v", principal.ImpersonationID, err)
+			}
+		}
+	}
+}
+
+// RequireSession rejects API keys for actions that must be taken by a signed-in
+// person, such as managing credentials. It must run after RequireAuth.
+func RequireSession() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, ok := CurrentPrincipal(ctx)
+		if !ok {
+			utils.Unauthorized(ctx, "Authentication required", errMissingToken)
+			ctx.Abort()
+			return
+		}
+
+		if principal.IsAPIKey() {
+			utils.Forbidden(ctx, "Sign in to perform this action", errSessionRequired)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequireElevation guards sensitive actions. The session must have confirmed
+// the password through /api/auth/elevation within the elevation window; API
+// keys are always refused. It must run after RequireAuth.
+func RequireElevation() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, ok := CurrentPrincipal(ctx)
+		if !ok {
+			utils.Unauthorized(ctx, "Authentication required", errMissingToken)
+			ctx.Abort()
+			return
+		}
+
+		if !principal.IsElevated() {
+			utils.Forbidden(ctx, "Confirm your password to continue", service.ErrReauthenticationRequired)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// ForbidImpersonation blocks actions support staff must never take on a
+// user's behalf, such as changing credentials or deleting accounts. It must
+// run after RequireAuth.
+func ForbidImpersonation() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, ok := CurrentPrincipal(ctx)
+		if !ok {
+			utils.Unauthorized(ctx, "Authentication required", errMissingToken)
+			ctx.Abort()
+			return
+		}
+
+		if principal.IsImpersonated() {
+			utils.Forbidden(ctx, "Not allowed while impersonating a user", errImpersonating)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// CurrentPrincipal returns the principal stored by RequireAuth.
+func CurrentPrincipal(ctx *gin.Context) (*service.Principal, bool) {
+	return service.PrincipalFromContext(ctx.Request.Context())
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
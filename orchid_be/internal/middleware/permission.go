@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission lets the request through only when the principal set by
+// RequireAuth holds the given permission. It must run after RequireAuth.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, ok := CurrentPrincipal(ctx)
+		if !ok {
+			utils.Unauthorized(ctx, "Authentication required", errMissingToken)
+			ctx.Abort()
+			return
+		}
+
+		if !principal.HasPermission(permission) {
+			utils.Forbidden(ctx, "Insufficient permissions", fmt.Errorf("missing permission %s", permission))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequirePermissionOrSelf behaves like RequirePermission but also admits users
+// acting on their own record, identified by the :id path parameter.
+func RequirePermissionOrSelf(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, ok := CurrentPrincipal(ctx)
+		if !ok {
+			utils.Unauthorized(ctx, "Authentication required", errMissingToken)
+			ctx.Abort()
+			return
+		}
+
+		if id, err := strconv.Atoi(ctx.Param("id")); err == nil && id == principal.User.ID {
+			ctx.Next()
+			return
+		}
+
+		if !principal.HasPermission(permission) {
+			utils.Forbidden(ctx, "Insufficient permissions", fmt.Errorf("missing permission %s", permission))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
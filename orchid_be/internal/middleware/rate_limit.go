@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"orchid_be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errRateLimited = errors.New("too many requests from this address")
+
+// RateLimiter allows a fixed number of requests per client IP in each window.
+// Counters live in memory, so every API instance enforces its own limit.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	clients   map[string]*rateWindow
+	nextSweep time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter returns a limiter. A limit of zero or less disables it.
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		clients: make(map[string]*rateWindow),
+	}
+}
+
+// Allow counts a request for key and reports whether it is within the limit.
+// When it is not, the returned duration is how long until the window resets.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	if l.limit <= 0 || l.window <= 0 {
+		return true, 0
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	entry, ok := l.clients[key]
+	if !ok || now.Sub(entry.start) >= l.window {
+		l.clients[key] = &rateWindow{start: now, count: 1}
+		return true, 0
+	}
+
+	if entry.count >= l.limit {
+		return false, entry.start.Add(l.window).Sub(now)
+	}
+
+	entry.count++
+	return true, 0
+}
+
+// Limit rejects requests from client IPs that exceeded the limit with 429.
+func (l *RateLimiter) Limit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		allowed, retryAfter := l.Allow(ctx.ClientIP())
+		if !allowed {
+			seconds := int(retryAfter.Seconds())
+			if seconds < 1 {
+				seconds = 1
+			}
+			ctx.Header("Retry-After", strconv.Itoa(seconds))
+			utils.TooManyRequests(ctx, "Too many requests, please try again later", errRateLimited)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// sweep drops expired windows so the map does not grow without bound.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Before(l.nextSweep) {
+		return
+	}
+
+	for key, entry := range l.clients {
+		if now.Sub(entry.start) >= l.window {
+			delete(l.clients, key)
+		}
+	}
+
+	l.nextSweep = now.Add(l.window)
+}
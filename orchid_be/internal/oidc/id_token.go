@@ -0,0 +1,319 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// clockSkew tolerates small clock differences with the provider
+	clockSkew = time.Minute
+	// keysRefreshInterval limits how often an unknown key ID refetches the JWKS
+	keysRefreshInterval = time.Minute
+)
+
+// IDToken holds the verified claims of an ID token.
+type IDToken struct {
+	Issuer          string       `json:"iss"`
+	Subject         string       `json:"sub"`
+	Audience        audience     `json:"aud"`
+	AuthorizedParty string       `json:"azp,omitempty"`
+	ExpiresAt       int64        `json:"exp"`
+	IssuedAt        int64        `json:"iat"`
+	Nonce           string       `json:"nonce,omitempty"`
+	Email           string       `json:"email,omitempty"`
+	EmailVerified   flexibleBool `json:"email_verified,omitempty"`
+	Name            string       `json:"name,omitempty"`
+}
+
+type idTokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// audience accepts both the single string and the array form of "aud".
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a audience) contains(value string) bool {
+	for _, v := range a {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// flexibleBool accepts providers that send email_verified as the string "true".
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var value bool
+	if err := json.Unmarshal(data, &value); err == nil {
+		*b = flexibleBool(value)
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*b = flexibleBool(strings.EqualFold(text, "true"))
+	return nil
+}
+
+// VerifyIDToken checks the signature against the provider's published keys and
+// validates issuer, audience, expiry and nonce.
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDToken, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidIDToken
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidIDToken
+	}
+	var header idTokenHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, ErrInvalidIDToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidIDToken
+	}
+
+	key, err := p.getKey(ctx, discovery.JWKSURI, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(header.Algorithm, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidIDToken
+	}
+	var token IDToken
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return nil, ErrInvalidIDToken
+	}
+
+	if token.Issuer != discovery.Issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrInvalidIDToken)
+	}
+	if !token.Audience.contains(p.config.ClientID) {
+		return nil, fmt.Errorf("%w: token was issued for another client", ErrInvalidIDToken)
+	}
+	if len(token.Audience) > 1 && token.AuthorizedParty != p.config.ClientID {
+		return nil, fmt.Errorf("%w: token was issued for another client", ErrInvalidIDToken)
+	}
+
+	now := time.Now()
+	if token.ExpiresAt == 0 || now.Add(-clockSkew).Unix() >= token.ExpiresAt {
+		return nil, fmt.Errorf("%w: token has expired", ErrInvalidIDToken)
+	}
+	if token.IssuedAt > now.Add(clockSkew).Unix() {
+		return nil, fmt.Errorf("%w: token is issued in the future", ErrInvalidIDToken)
+	}
+
+	if token.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	if token.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return &token, nil
+}
+
+// getKey finds the signing key by ID. An unknown ID triggers one refetch so
+// key rotation at the provider is picked up.
+func (p *Provider) getKey(ctx context.Context, jwksURI, keyID string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(keyID); ok {
+		return key, nil
+	}
+
+	if p.keys != nil && time.Since(p.keysFetchedAt) < keysRefreshInterval {
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidIDToken, keyID)
+	}
+
+	var keySet jsonWebKeySet
+	if err := p.getJSON(ctx, jwksURI, &keySet); err != nil {
+		return nil, fmt.Errorf("failed to load signing keys of %s: %w", p.name, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(keySet.Keys))
+	for _, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Skip key types we cannot use instead of failing the whole set
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+
+	if key, ok := p.lookupKey(keyID); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidIDToken, keyID)
+}
+
+func (p *Provider) lookupKey(keyID string) (crypto.PublicKey, bool) {
+	if key, ok := p.keys[keyID]; ok {
+		return key, true
+	}
+
+	// Tokens without a key ID are only accepted when the choice is unambiguous
+	if keyID == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	return nil, false
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+// verifySignature only accepts asymmetric algorithms; "none" and HMAC are
+// rejected because the client secret must never validate an ID token here.
+func verifySignature(algorithm string, key crypto.PublicKey, signingInput, signature []byte) error {
+	var hash crypto.Hash
+	switch algorithm {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported algorithm %q", algorithm)
+	}
+
+	hasher := hash.New()
+	hasher.Write(signingInput)
+	digest := hasher.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(algorithm, "RS") {
+			return fmt.Errorf("algorithm %q does not match an RSA key", algorithm)
+		}
+		return rsa.VerifyPKCS1v15(pub, hash, digest, signature)
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(algorithm, "ES") {
+			return fmt.Errorf("algorithm %q does not match an EC key", algorithm)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("invalid signature length")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return fmt.Errorf("signature verification failed")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported key")
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,191 @@
+// Package oidctest runs a local stand-in OpenID Connect provider for tests. It
+// serves discovery, a JWKS and a token endpoint, and signs ID tokens with a
+// freshly generated RSA key.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"orchid_be/internal/oidc"
+)
+
+const KeyID = "test-key"
+
+// Server is the stand-in provider. Tests hand out authorization codes with
+// IssueCode instead of going through a login page.
+type Server struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+	Key          *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]grant
+}
+
+type grant struct {
+	claims        map[string]any
+	codeChallenge string
+}
+
+// NewServer starts a provider for clientID that is closed with the test.
+func NewServer(t testing.TB, clientID string) *Server {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: "test-secret",
+		Key:          key,
+		codes:        make(map[string]grant),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/jwks", s.jwks)
+	mux.HandleFunc("/token", s.token)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Issuer is the issuer to configure, the server's base URL.
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// Claims returns valid ID token claims for subject, issued now for the
+// server's client.
+func (s *Server) Claims(subject, nonce string) map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"iss":   s.Issuer(),
+		"sub":   subject,
+		"aud":   s.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": nonce,
+	}
+}
+
+// IssueCode registers an authorization code that the token endpoint
+// exchanges for an ID token with claims, once, and only together with the
+// PKCE verifier matching codeChallenge.
+func (s *Server) IssueCode(claims map[string]any, codeChallenge string) string {
+	code := base64.RawURLEncoding.EncodeToString(randomBytes(16))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.codes[code] = grant{claims: claims, codeChallenge: codeChallenge}
+
+	return code
+}
+
+// SignIDToken signs claims with the server's key as an RS256 ID token.
+func (s *Server) SignIDToken(claims map[string]any) string {
+	return SignToken(map[string]any{"alg": "RS256", "kid": KeyID, "typ": "JWT"}, claims, func(input []byte) []byte {
+		digest := sha256.Sum256(input)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, s.Key, crypto.SHA256, digest[:])
+		if err != nil {
+			panic(err)
+		}
+		return signature
+	})
+}
+
+// SignToken builds a compact JWS from header and claims, signing it with
+// sign, which may be nil for an unsigned token.
+func SignToken(header, claims map[string]any, sign func(input []byte) []byte) string {
+	headerJSON, _ := json.Marshal(header)
+	claimsJSON, _ := json.Marshal(claims)
+
+	input := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	var signature []byte
+	if sign != nil {
+		signature = sign([]byte(input))
+	}
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                 s.Issuer(),
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]any{{
+			"kty": "RSA",
+			"kid": KeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(s.Key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.Key.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_request"})
+		return
+	}
+
+	if r.PostForm.Get("client_id") != s.ClientID || r.PostForm.Get("client_secret") != s.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "invalid_client"})
+		return
+	}
+
+	s.mu.Lock()
+	code := r.PostForm.Get("code")
+	grant, ok := s.codes[code]
+	delete(s.codes, code)
+	s.mu.Unlock()
+
+	if !ok || oidc.CodeChallenge(r.PostForm.Get("code_verifier")) != grant.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"id_token":     s.SignIDToken(grant.claims),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}
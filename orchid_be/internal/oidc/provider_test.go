@@ -0,0 +1,234 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"orchid_be/internal/config"
+	"orchid_be/internal/oidc"
+	"orchid_be/internal/oidc/oidctest"
+)
+
+const clientID = "orchid"
+
+func newProvider(t *testing.T) (*oidc.Provider, *oidctest.Server) {
+	t.Helper()
+
+	server := oidctest.NewServer(t, clientID)
+	provider := oidc.NewProvider("test", config.OIDCProviderConfig{
+		Issuer:       server.Issuer(),
+		ClientID:     clientID,
+		ClientSecret: server.ClientSecret,
+		RedirectURL:  "http://localhost:4321/auth/oidc/test/callback",
+	}, server.Client())
+
+	return provider, server
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// RFC 7636, appendix B
+	got := oidc.CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Fatalf("CodeChallenge() = %q, want %q", got, want)
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	provider, server := newProvider(t)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("invalid authorization URL %q: %v", authURL, err)
+	}
+
+	if got, want := parsed.Scheme+"://"+parsed.Host+parsed.Path, server.URL+"/authorize"; got != want {
+		t.Errorf("authorization endpoint = %q, want %q from discovery", got, want)
+	}
+
+	query := parsed.Query()
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             clientID,
+		"state":                 "state",
+		"nonce":                 "nonce",
+		"scope":                 "openid email profile",
+		"code_challenge":        oidc.CodeChallenge("verifier"),
+		"code_challenge_method": "S256",
+	}
+	for name, value := range want {
+		if got := query.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+	if query.Has("code_verifier") {
+		t.Error("the code verifier must not leave the server")
+	}
+}
+
+func TestDiscoveryRejectsOtherIssuer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"issuer":"https://evil.example","authorization_endpoint":"a","token_endpoint":"t","jwks_uri":"j"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider := oidc.NewProvider("test", config.OIDCProviderConfig{Issuer: server.URL, ClientID: clientID}, server.Client())
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); err == nil {
+		t.Fatal("AuthCodeURL() accepted a discovery document for another issuer")
+	}
+}
+
+func TestExchange(t *testing.T) {
+	provider, server := newProvider(t)
+	ctx := context.Background()
+
+	code := server.IssueCode(server.Claims("subject", "nonce"), oidc.CodeChallenge("verifier"))
+
+	if _, err := provider.Exchange(ctx, code, "wrong-verifier"); !errors.Is(err, oidc.ErrExchangeFailed) {
+		t.Fatalf("Exchange() with the wrong verifier error = %v, want ErrExchangeFailed", err)
+	}
+
+	code = server.IssueCode(server.Claims("subject", "nonce"), oidc.CodeChallenge("verifier"))
+	token, err := provider.Exchange(ctx, code, "verifier")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+
+	claims, err := provider.VerifyIDToken(ctx, token.IDToken, "nonce")
+	if err != nil {
+		t.Fatalf("VerifyIDToken() error = %v", err)
+	}
+	if claims.Subject != "subject" {
+		t.Errorf("Subject = %q, want %q", claims.Subject, "subject")
+	}
+
+	if _, err := provider.Exchange(ctx, code, "verifier"); !errors.Is(err, oidc.ErrExchangeFailed) {
+		t.Fatalf("Exchange() reused a code, error = %v", err)
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	provider, server := newProvider(t)
+
+	hs256 := func(input []byte) []byte {
+		mac := hmac.New(sha256.New, []byte(server.ClientSecret))
+		mac.Write(input)
+		return mac.Sum(nil)
+	}
+
+	tests := []struct {
+		name    string
+		token   func(claims map[string]any) string
+		claims  func(claims map[string]any)
+		wantErr bool
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "verified email as string",
+			claims: func(c map[string]any) {
+				c["email"] = "ann@example.com"
+				c["email_verified"] = "true"
+			},
+		},
+		{
+			name:    "other audience",
+			claims:  func(c map[string]any) { c["aud"] = "someone-else" },
+			wantErr: true,
+		},
+		{
+			name:   "several audiences with our client as azp",
+			claims: func(c map[string]any) { c["aud"] = []string{clientID, "api"}; c["azp"] = clientID },
+		},
+		{
+			name:    "several audiences without azp",
+			claims:  func(c map[string]any) { c["aud"] = []string{clientID, "api"} },
+			wantErr: true,
+		},
+		{
+			name:    "several audiences with another azp",
+			claims:  func(c map[string]any) { c["aud"] = []string{clientID, "api"}; c["azp"] = "api" },
+			wantErr: true,
+		},
+		{
+			name:    "wrong nonce",
+			claims:  func(c map[string]any) { c["nonce"] = "replayed" },
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			claims:  func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+			wantErr: true,
+		},
+		{
+			name:    "no expiry",
+			claims:  func(c map[string]any) { delete(c, "exp") },
+			wantErr: true,
+		},
+		{
+			name:    "other issuer",
+			claims:  func(c map[string]any) { c["iss"] = "https://evil.example" },
+			wantErr: true,
+		},
+		{
+			name: "HS256 signed with the client secret",
+			token: func(c map[string]any) string {
+				return oidctest.SignToken(map[string]any{"alg": "HS256", "kid": oidctest.KeyID}, c, hs256)
+			},
+			wantErr: true,
+		},
+		{
+			name: "alg none",
+			token: func(c map[string]any) string {
+				return oidctest.SignToken(map[string]any{"alg": "none", "kid": oidctest.KeyID}, c, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "tampered claims",
+			token: func(c map[string]any) string {
+				valid := server.SignIDToken(c)
+				c["sub"] = "admin"
+				forged := server.SignIDToken(c)
+				return forged[:strings.LastIndex(forged, ".")] + valid[strings.LastIndex(valid, "."):]
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := server.Claims("subject", "nonce")
+			if tt.claims != nil {
+				tt.claims(claims)
+			}
+
+			token := server.SignIDToken(claims)
+			if tt.token != nil {
+				token = tt.token(claims)
+			}
+
+			_, err := provider.VerifyIDToken(context.Background(), token, "nonce")
+			if tt.wantErr && !errors.Is(err, oidc.ErrInvalidIDToken) {
+				t.Fatalf("VerifyIDToken() error = %v, want ErrInvalidIDToken", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("VerifyIDToken() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, userID int, name, prefix, keyHash string, expiresAt *time.Time, permissionIDs []int, maxActive *int) (db.ApiKey, bool, error)
+	GetByHash(ctx context.Context, keyHash string) (db.ApiKey, error)
+	GetAllForUser(ctx context.Context, userID int) ([]db.ApiKey, error)
+	GetScopes(ctx context.Context, id int) ([]string, error)
+	CountActiveForUser(ctx context.Context, userID int) (int, error)
+	Revoke(ctx context.Context, id, userID int) (bool, error)
+	Touch(ctx context.Context, id int, staleBefore time.Time) error
+}
+
+type apiKeyRepository struct {
+	*BaseRepository
+}
+
+func NewAPIKeyRepository(database *sql.DB) APIKeyRepository {
+	return &apiKeyRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+// Create stores the key and its scopes in one transaction. With maxActive
+// set, it reports false, and stores nothing, when the user already holds that
+// many active keys. The user row stays locked until the key is stored, so
+// concurrent creates are counted one after another and cannot go past the
+// limit together.
+func (r *apiKeyRepository) Create(ctx context.Context, userID int, name, prefix, keyHash string, expiresAt *time.Time, permissionIDs []int, maxActive *int) (db.ApiKey, bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return db.ApiKey{}, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+
+	if maxActive != nil {
+		if _, err := queries.LockApiKeyOwner(ctx, int32(userID)); err != nil {
+			if err == sql.ErrNoRows {
+				return db.ApiKey{}, false, fmt.Errorf("user with id %d not found", userID)
+			}
+			return db.ApiKey{}, false, fmt.Errorf("failed to lock user: %w", err)
+		}
+
+		count, err := queries.CountActiveApiKeys(ctx, db.CountActiveApiKeysParams{
+			UserID:    int32(userID),
+			ExpiresAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		if err != nil {
+			return db.ApiKey{}, false, fmt.Errorf("failed to count api keys: %w", err)
+		}
+		if count >= int64(*maxActive) {
+			return db.ApiKey{}, false, nil
+		}
+	}
+
+	createAPIKeyParams := db.CreateApiKeyParams{
+		UserID:    int32(userID),
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	if expiresAt != nil {
+		createAPIKeyParams.ExpiresAt = sql.NullTime{Time: *expiresAt, Valid: true}
+	}
+
+	key, err := queries.CreateApiKey(ctx, createAPIKeyParams)
+	if err != nil {
+		return db.ApiKey{}, false, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	for _, permissionID := range permissionIDs {
+		err := queries.AddApiKeyPermission(ctx, db.AddApiKeyPermissionParams{
+			ApiKeyID:     key.ID,
+			PermissionID: int32(permissionID),
+		})
+		if err != nil {
+			return db.ApiKey{}, false, fmt.Errorf("failed to add api key scope: %w", err)
+		}
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return db.ApiKey{}, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return key, true, nil
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (db.ApiKey, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	key, err := r.GetQueries().GetApiKeyByHash(ctx, keyHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.ApiKey{}, fmt.Errorf("api key not found")
+		}
+		return db.ApiKey{}, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *apiKeyRepository) GetAllForUser(ctx context.Context, userID int) ([]db.ApiKey, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	keys, err := r.GetQueries().ListUserApiKeys(ctx, int32(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) GetScopes(ctx context.Context, id int) ([]string, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	scopes, err := r.GetQueries().ListApiKeyScopes(ctx, int32(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key scopes: %w", err)
+	}
+
+	return scopes, nil
+}
+
+// CountActiveForUser counts the user's keys that are neither revoked nor expired.
+func (r *apiKeyRepository) CountActiveForUser(ctx context.Context, userID int) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := r.GetQueries().CountActiveApiKeys(ctx, db.CountActiveApiKeysParams{
+		UserID:    int32(userID),
+		ExpiresAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count api keys: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// Revoke reports false if the key does not belong to the user or is already revoked.
+func (r *apiKeyRepository) Revoke(ctx context.Context, id, userID int) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.GetQueries().RevokeApiKey(ctx, db.RevokeApiKeyParams{
+		ID:        int32(id),
+		UserID:    int32(userID),
+		RevokedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// Touch records that the key was used. The write is skipped when last_used_at
+// is newer than staleBefore so busy keys do not update the row on every request.
+func (r *apiKeyRepository) Touch(ctx context.Context, id int, staleBefore time.Time) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := r.GetQueries().TouchApiKey(ctx, db.TouchApiKeyParams{
+		UsedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+		ID:          int32(id),
+		StaleBefore: sql.NullTime{Time: staleBefore, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update api key last use: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type CategoryRepository interface {
+	CrudRepository[db.Category]
+	List(ctx context.Context) ([]db.Category, error)
+}
+
+type categoryRepository struct {
+	CrudRepository[db.Category]
+	*BaseRepository
+}
+
+func NewCategoryRepository(database *sql.DB) CategoryRepository {
+	return &categoryRepository{
+		CrudRepository: NewCrudRepository[db.Category](database, "categories"),
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+// List returns every category ordered by name.
+func (r *categoryRepository) List(ctx context.Context) ([]db.Category, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	categories, err := r.GetQueries().ListCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	return categories, nil
+}
@@ -0,0 +1,440 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"orchid_be/internal/utils"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrRecordNotFound  = errors.New("record not found")
+	ErrDuplicateRecord = errors.New("record already exists")
+	ErrRecordInUse     = errors.New("record is still referenced")
+	ErrInvalidValue    = errors.New("invalid value")
+)
+
+// Filter operators, used as ?column[op]=value. "in" takes a comma separated
+// list of values.
+const (
+	FilterEqual          = "eq"
+	FilterNotEqual       = "ne"
+	FilterGreater        = "gt"
+	FilterGreaterOrEqual = "gte"
+	FilterLess           = "lt"
+	FilterLessOrEqual    = "lte"
+	FilterIn             = "in"
+)
+
+var filterOperators = map[string]string{
+	FilterEqual:          "=",
+	FilterNotEqual:       "<>",
+	FilterGreater:        ">",
+	FilterGreaterOrEqual: ">=",
+	FilterLess:           "<",
+	FilterLessOrEqual:    "<=",
+	FilterIn:             "= ANY",
+}
+
+// likeEscaper escapes the wildcards of LIKE so a search matches them
+// literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// ListQuery narrows and orders a list. Every filter has to match. Search
+// keeps the rows where any of SearchColumns contains it, ignoring case. Rows
+// are ordered by Sorts in turn and then by id.
+type ListQuery struct {
+	Filters       []Filter
+	Search        string
+	SearchColumns []string
+	Sorts         []Sort
+}
+
+// Filter compares a column with a value, which Postgres converts to the
+// column's type; a value it cannot convert is an ErrInvalidValue.
+type Filter struct {
+	Column   string
+	Operator string
+	Value    string
+}
+
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// IsFilterOperator reports whether op is one of the filter operators.
+func IsFilterOperator(op string) bool {
+	_, ok := filterOperators[op]
+	return ok
+}
+
+// CrudRepository reads and writes the rows of one table as values of T.
+type CrudRepository[T any] interface {
+	Columns() []string
+	GetByID(ctx context.Context, id int) (T, error)
+	GetAll(ctx context.Context, query ListQuery, limit, offset int) ([]T, error)
+	Count(ctx context.Context, query ListQuery) (int, error)
+	Create(ctx context.Context, values map[string]any) (T, error)
+	Update(ctx context.Context, id int, values map[string]any) (T, error)
+	Delete(ctx context.Context, id int) (bool, error)
+}
+
+type crudRepository[T any] struct {
+	*BaseRepository
+	table   string
+	columns []string
+	fields  []int
+}
+
+// NewCrudRepository serves table with rows of T, normally the model sqlc
+// generated for it. The columns are taken from the json tags of T, which sqlc
+// sets to the column names, and the table needs an integer id primary key.
+// Statements are built from those names only; every value is bound as a
+// parameter.
+func NewCrudRepository[T any](database *sql.DB, table string) CrudRepository[T] {
+	r := &crudRepository[T]{
+		BaseRepository: NewBaseRepository(database),
+		table:          table,
+	}
+
+	model := reflect.TypeOf((*T)(nil)).Elem()
+	for i := 0; i < model.NumField(); i++ {
+		if name := utils.JSONName(model.Field(i)); name != "" {
+			r.columns = append(r.columns, name)
+			r.fields = append(r.fields, i)
+		}
+	}
+
+	return r
+}
+
+func (r *crudRepository[T]) Columns() []string {
+	return r.columns
+}
+
+func (r *crudRepository[T]) GetByID(ctx context.Context, id int) (T, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", r.columnList(), pq.QuoteIdentifier(r.table))
+
+	item, err := r.scan(r.GetDB().QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return item, ErrRecordNotFound
+		}
+		return item, fmt.Errorf("failed to get %s: %w", r.table, err)
+	}
+
+	return item, nil
+}
+
+func (r *crudRepository[T]) GetAll(ctx context.Context, listQuery ListQuery, limit, offset int) ([]T, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	where, args, err := r.where(listQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy, err := r.orderBy(listQuery.Sorts)
+	if err != nil {
+		return nil, err
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s LIMIT $%d OFFSET $%d",
+		r.columnList(), pq.QuoteIdentifier(r.table), where, orderBy, len(args)-1, len(args))
+
+	rows, err := r.GetDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", r.table, constraintError(err))
+	}
+	defer rows.Close()
+
+	items := []T{}
+	for rows.Next() {
+		item, err := r.scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", r.table, err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", r.table, err)
+	}
+
+	return items, nil
+}
+
+func (r *crudRepository[T]) Count(ctx context.Context, listQuery ListQuery) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	where, args, err := r.where(listQuery)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", pq.QuoteIdentifier(r.table), where)
+
+	var count int
+	if err := r.GetDB().QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count %s: %w", r.table, constraintError(err))
+	}
+
+	return count, nil
+}
+
+// Create inserts a row with the given column values. created_at and
+// updated_at are set to now when the table has them and they are not given.
+func (r *crudRepository[T]) Create(ctx context.Context, values map[string]any) (T, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	values = r.withTimestamp(values, "created_at", now)
+	values = r.withTimestamp(values, "updated_at", now)
+
+	names, args, err := r.assignments(values)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	columns := make([]string, len(names))
+	placeholders := make([]string, len(names))
+	for i, name := range names {
+		columns[i] = pq.QuoteIdentifier(name)
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s",
+		pq.QuoteIdentifier(r.table), strings.Join(columns, ", "), strings.Join(placeholders, ", "), r.columnList())
+
+	item, err := r.scan(r.GetDB().QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return item, fmt.Errorf("failed to create %s: %w", r.table, constraintError(err))
+	}
+
+	return item, nil
+}
+
+// Update sets the given column values of the row, and updated_at to now when
+// the table has it.
+func (r *crudRepository[T]) Update(ctx context.Context, id int, values map[string]any) (T, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	values = r.withTimestamp(values, "updated_at", time.Now())
+
+	names, args, err := r.assignments(values)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if len(names) == 0 {
+		return r.GetByID(ctx, id)
+	}
+
+	set := make([]string, len(names))
+	for i, name := range names {
+		set[i] = fmt.Sprintf("%s = $%d", pq.QuoteIdentifier(name), i+1)
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d RETURNING %s",
+		pq.QuoteIdentifier(r.table), strings.Join(set, ", "), len(args), r.columnList())
+
+	item, err := r.scan(r.GetDB().QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return item, ErrRecordNotFound
+		}
+		return item, fmt.Errorf("failed to update %s: %w", r.table, constraintError(err))
+	}
+
+	return item, nil
+}
+
+// Delete removes the row. It reports false if no row has the id, and returns
+// ErrRecordInUse if other rows still reference it.
+func (r *crudRepository[T]) Delete(ctx context.Context, id int) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", pq.QuoteIdentifier(r.table))
+
+	result, err := r.GetDB().ExecContext(ctx, query, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return false, ErrRecordInUse
+		}
+		return false, fmt.Errorf("failed to delete %s: %w", r.table, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete %s: %w", r.table, err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *crudRepository[T]) columnList() string {
+	columns := make([]string, len(r.columns))
+	for i, column := range r.columns {
+		columns[i] = pq.QuoteIdentifier(column)
+	}
+	return strings.Join(columns, ", ")
+}
+
+func (r *crudRepository[T]) hasColumn(name string) bool {
+	for _, column := range r.columns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// where builds the WHERE clause of the filters and the search. Only column
+// names known from T reach the statement; values are bound as parameters.
+func (r *crudRepository[T]) where(listQuery ListQuery) (string, []any, error) {
+	var conditions []string
+	var args []any
+
+	for _, filter := range listQuery.Filters {
+		operator, ok := filterOperators[filter.Operator]
+		if !ok {
+			return "", nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidValue, filter.Operator)
+		}
+		if !r.hasColumn(filter.Column) {
+			return "", nil, fmt.Errorf("%w: unknown column %q", ErrInvalidValue, filter.Column)
+		}
+
+		if filter.Operator == FilterIn {
+			args = append(args, pq.Array(strings.Split(filter.Value, ",")))
+			conditions = append(conditions, fmt.Sprintf("%s %s($%d)", pq.QuoteIdentifier(filter.Column), operator, len(args)))
+		} else {
+			args = append(args, filter.Value)
+			conditions = append(conditions, fmt.Sprintf("%s %s $%d", pq.QuoteIdentifier(filter.Column), operator, len(args)))
+		}
+	}
+
+	if listQuery.Search != "" && len(listQuery.SearchColumns) > 0 {
+		args = append(args, "%"+likeEscaper.Replace(listQuery.Search)+"%")
+
+		matches := make([]string, len(listQuery.SearchColumns))
+		for i, column := range listQuery.SearchColumns {
+			if !r.hasColumn(column) {
+				return "", nil, fmt.Errorf("%w: unknown column %q", ErrInvalidValue, column)
+			}
+			matches[i] = fmt.Sprintf("%s ILIKE $%d", pq.QuoteIdentifier(column), len(args))
+		}
+		conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+	}
+
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// orderBy builds the ORDER BY list of the sorts, ending with id so pages
+// never overlap.
+func (r *crudRepository[T]) orderBy(sorts []Sort) (string, error) {
+	terms := make([]string, 0, len(sorts)+1)
+	for _, order := range sorts {
+		if !r.hasColumn(order.Column) {
+			return "", fmt.Errorf("%w: unknown column %q", ErrInvalidValue, order.Column)
+		}
+
+		term := pq.QuoteIdentifier(order.Column)
+		if order.Desc {
+			term += " DESC"
+		}
+		terms = append(terms, term)
+
+		if order.Column == "id" {
+			return strings.Join(terms, ", "), nil
+		}
+	}
+
+	return strings.Join(append(terms, "id"), ", "), nil
+}
+
+// assignments returns the column names of values in a fixed order with the
+// matching arguments. The id cannot be written.
+func (r *crudRepository[T]) assignments(values map[string]any) ([]string, []any, error) {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		if name == "id" || !r.hasColumn(name) {
+			return nil, nil, fmt.Errorf("%w: cannot write column %q", ErrInvalidValue, name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make([]any, len(names))
+	for i, name := range names {
+		args[i] = values[name]
+	}
+
+	return names, args, nil
+}
+
+func (r *crudRepository[T]) withTimestamp(values map[string]any, column string, now time.Time) map[string]any {
+	if _, ok := values[column]; ok || !r.hasColumn(column) {
+		return values
+	}
+
+	withTimestamp := make(map[string]any, len(values)+1)
+	for name, value := range values {
+		withTimestamp[name] = value
+	}
+	withTimestamp[column] = now
+	return withTimestamp
+}
+
+func (r *crudRepository[T]) scan(row interface{ Scan(dest ...any) error }) (T, error) {
+	var item T
+	value := reflect.ValueOf(&item).Elem()
+
+	dest := make([]any, len(r.fields))
+	for i, field := range r.fields {
+		dest[i] = value.Field(field).Addr().Interface()
+	}
+
+	err := row.Scan(dest...)
+	return item, err
+}
+
+// constraintError turns the errors Postgres raises for bad input into
+// ErrDuplicateRecord or ErrInvalidValue, so callers can answer with a client
+// error instead of a failure.
+func constraintError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch {
+	case pqErr.Code == "23505":
+		return ErrDuplicateRecord
+	case pqErr.Code.Class() == "22", pqErr.Code.Class() == "23":
+		return fmt.Errorf("%w: %s", ErrInvalidValue, pqErr.Message)
+	}
+	return err
+}
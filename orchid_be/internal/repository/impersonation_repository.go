@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+// Actions recorded in the impersonation audit trail.
+const (
+	ImpersonationActionStart = "start"
+	ImpersonationActionStop  = "stop"
+	ImpersonationActionWrite = "write"
+)
+
+type ImpersonationRepository interface {
+	Start(ctx context.Context, actorID, subjectID int, reason, ipAddress string, expiresAt time.Time) (db.Impersonation, error)
+	GetByID(ctx context.Context, id int) (db.Impersonation, error)
+	GetAll(ctx context.Context, limit, offset int) ([]db.Impersonation, error)
+	Count(ctx context.Context) (int, error)
+	End(ctx context.Context, id int, ipAddress string) (bool, error)
+	RecordEvent(ctx context.Context, impersonationID int, action, method, path string, statusCode int, ipAddress string) error
+	GetEvents(ctx context.Context, impersonationID int) ([]db.ImpersonationEvent, error)
+}
+
+type impersonationRepository struct {
+	*BaseRepository
+}
+
+func NewImpersonationRepository(database *sql.DB) ImpersonationRepository {
+	return &impersonationRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+// Start creates the impersonation together with the first entry of its audit
+// trail.
+func (r *impersonationRepository) Start(ctx context.Context, actorID, subjectID int, reason, ipAddress string, expiresAt time.Time) (db.Impersonation, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return db.Impersonation{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+
+	impersonation, err := queries.CreateImpersonation(ctx, db.CreateImpersonationParams{
+		ActorID:   int32(actorID),
+		SubjectID: int32(subjectID),
+		Reason:    reason,
+		IpAddress: ipAddress,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return db.Impersonation{}, fmt.Errorf("failed to create impersonation: %w", err)
+	}
+
+	err = queries.CreateImpersonationEvent(ctx, db.CreateImpersonationEventParams{
+		ImpersonationID: impersonation.ID,
+		Action:          ImpersonationActionStart,
+		IpAddress:       ipAddress,
+		CreatedAt:       now,
+	})
+	if err != nil {
+		return db.Impersonation{}, fmt.Errorf("failed to record impersonation event: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return db.Impersonation{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return impersonation, nil
+}
+
+func (r *impersonationRepository) GetByID(ctx context.Context, id int) (db.Impersonation, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	impersonation, err := r.GetQueries().GetImpersonationByID(ctx, int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Impersonation{}, fmt.Errorf("impersonation not found")
+		}
+		return db.Impersonation{}, fmt.Errorf("failed to get impersonation: %w", err)
+	}
+
+	return impersonation, nil
+}
+
+func (r *impersonationRepository) GetAll(ctx context.Context, limit, offset int) ([]db.Impersonation, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	impersonations, err := r.GetQueries().ListImpersonations(ctx, db.ListImpersonationsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get impersonations: %w", err)
+	}
+
+	return impersonations, nil
+}
+
+func (r *impersonationRepository) Count(ctx context.Context) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := r.GetQueries().CountImpersonations(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count impersonations: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// End closes the impersonation and records it in the audit trail. It reports
+// false if the impersonation had already ended.
+func (r *impersonationRepository) End(ctx context.Context, id int, ipAddress string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+
+	rows, err := queries.EndImpersonation(ctx, db.EndImpersonationParams{
+		ID:      int32(id),
+		EndedAt: now,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to end impersonation: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	err = queries.CreateImpersonationEvent(ctx, db.CreateImpersonationEventParams{
+		ImpersonationID: int32(id),
+		Action:          ImpersonationActionStop,
+		IpAddress:       ipAddress,
+		CreatedAt:       now,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to record impersonation event: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *impersonationRepository) RecordEvent(ctx context.Context, impersonationID int, action, method, path string, statusCode int, ipAddress string) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := r.GetQueries().CreateImpersonationEvent(ctx, db.CreateImpersonationEventParams{
+		ImpersonationID: int32(impersonationID),
+		Action:          action,
+		Method:          method,
+		Path:            path,
+		StatusCode:      int32(statusCode),
+		IpAddress:       ipAddress,
+		CreatedAt:       sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record impersonation event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *impersonationRepository) GetEvents(ctx context.Context, impersonationID int) ([]db.ImpersonationEvent, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	events, err := r.GetQueries().ListImpersonationEvents(ctx, int32(impersonationID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get impersonation events: %w", err)
+	}
+
+	return events, nil
+}
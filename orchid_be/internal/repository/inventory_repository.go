@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type InventoryRepository interface {
+	GetLevel(ctx context.Context, productID int) (db.InventoryLevel, error)
+	RecordMovement(ctx context.Context, productID int, movementType string, delta int, note string, createdBy int) (db.StockMovement, bool, error)
+	SetLowStockThreshold(ctx context.Context, productID, threshold int) (db.InventoryLevel, error)
+	GetMovements(ctx context.Context, productID, limit, offset int) ([]db.StockMovement, error)
+	CountMovements(ctx context.Context, productID int) (int, error)
+	GetLowStock(ctx context.Context, limit, offset int) ([]db.ListLowStockProductsRow, error)
+	CountLowStock(ctx context.Context) (int, error)
+}
+
+type inventoryRepository struct {
+	*BaseRepository
+}
+
+func NewInventoryRepository(database *sql.DB) InventoryRepository {
+	return &inventoryRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+func (r *inventoryRepository) GetLevel(ctx context.Context, productID int) (db.InventoryLevel, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	level, err := r.GetQueries().GetInventoryLevel(ctx, int32(productID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.InventoryLevel{}, fmt.Errorf("inventory level not found")
+		}
+		return db.InventoryLevel{}, fmt.Errorf("failed to get inventory level: %w", err)
+	}
+
+	return level, nil
+}
+
+// RecordMovement changes the on-hand quantity by delta and appends the
+// movement to the ledger in one transaction. The conditional update locks the
+// inventory row, so concurrent movements of a product run one after another
+// and each sees the balance left by the previous one. It reports false, and
+// changes nothing, if the movement would take the quantity below zero.
+func (r *inventoryRepository) RecordMovement(ctx context.Context, productID int, movementType string, delta int, note string, createdBy int) (db.StockMovement, bool, error) {
+	if delta < math.MinInt32 || delta > math.MaxInt32 {
+		return db.StockMovement{}, false, fmt.Errorf("stock change %d is out of range", delta)
+	}
+
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return db.StockMovement{}, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+
+	err = queries.EnsureInventoryLevel(ctx, db.EnsureInventoryLevelParams{
+		ProductID: int32(productID),
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return db.StockMovement{}, false, fmt.Errorf("failed to create inventory level: %w", err)
+	}
+
+	level, err := queries.ApplyStockChange(ctx, db.ApplyStockChangeParams{
+		Delta:     int32(delta),
+		UpdatedAt: now,
+		ProductID: int32(productID),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.StockMovement{}, false, nil
+		}
+		return db.StockMovement{}, false, fmt.Errorf("failed to update inventory level: %w", err)
+	}
+
+	createStockMovementParams := db.CreateStockMovementParams{
+		ProductID:    int32(productID),
+		Type:         movementType,
+		Quantity:     int32(delta),
+		BalanceAfter: level.OnHand,
+		Note:         note,
+		CreatedAt:    now,
+	}
+	if createdBy != 0 {
+		createStockMovementParams.CreatedBy = sql.NullInt32{Int32: int32(createdBy), Valid: true}
+	}
+
+	movement, err := queries.CreateStockMovement(ctx, createStockMovementParams)
+	if err != nil {
+		return db.StockMovement{}, false, fmt.Errorf("failed to create stock movement: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return db.StockMovement{}, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return movement, true, nil
+}
+
+func (r *inventoryRepository) SetLowStockThreshold(ctx context.Context, productID, threshold int) (db.InventoryLevel, error) {
+	if threshold < 0 || threshold > math.MaxInt32 {
+		return db.InventoryLevel{}, fmt.Errorf("low stock threshold %d is out of range", threshold)
+	}
+
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	level, err := r.GetQueries().SetLowStockThreshold(ctx, db.SetLowStockThresholdParams{
+		ProductID:         int32(productID),
+		LowStockThreshold: int32(threshold),
+		UpdatedAt:         sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return db.InventoryLevel{}, fmt.Errorf("failed to set low stock threshold: %w", err)
+	}
+
+	return level, nil
+}
+
+func (r *inventoryRepository) GetMovements(ctx context.Context, productID, limit, offset int) ([]db.StockMovement, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	movements, err := r.GetQueries().ListStockMovements(ctx, db.ListStockMovementsParams{
+		ProductID: int32(productID),
+		Limit:     int32(limit),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock movements: %w", err)
+	}
+
+	return movements, nil
+}
+
+func (r *inventoryRepository) CountMovements(ctx context.Context, productID int) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := r.GetQueries().CountStockMovements(ctx, int32(productID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count stock movements: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// GetLowStock returns the products at or below their low-stock threshold,
+// emptiest first. Products without a threshold are never low on stock.
+func (r *inventoryRepository) GetLowStock(ctx context.Context, limit, offset int) ([]db.ListLowStockProductsRow, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.GetQueries().ListLowStockProducts(ctx, db.ListLowStockProductsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get low stock products: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *inventoryRepository) CountLowStock(ctx context.Context) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := r.GetQueries().CountLowStockProducts(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count low stock products: %w", err)
+	}
+
+	return int(count), nil
+}
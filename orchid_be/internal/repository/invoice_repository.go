@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+// InvoiceDraft is a new invoice with its line items. Amounts are decimal
+// strings. ChangeIDs lists the subscription changes it bills, they are
+// linked to the invoice so they are not billed twice.
+type InvoiceDraft struct {
+	UserID         int
+	SubscriptionID int
+	CustomerName   string
+	CustomerEmail  string
+	Country        string
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	Subtotal       string
+	TaxName        string
+	TaxRate        string
+	TaxAmount      string
+	Total          string
+	Items          []InvoiceItem
+	ChangeIDs      []int
+}
+
+type InvoiceItem struct {
+	Description string
+	Quantity    int
+	UnitPrice   string
+	Amount      string
+}
+
+type InvoiceRepository interface {
+	CreateDraft(ctx context.Context, draft InvoiceDraft) (db.Invoice, error)
+	GetByID(ctx context.Context, id int) (db.Invoice, error)
+	GetBySubscriptionPeriod(ctx context.Context, subscriptionID int, periodStart time.Time) (db.Invoice, error)
+	GetAll(ctx context.Context, limit, offset int) ([]db.Invoice, error)
+	Count(ctx context.Context) (int, error)
+	GetIssuedByUserID(ctx context.Context, userID, limit, offset int) ([]db.Invoice, error)
+	CountIssuedByUserID(ctx context.Context, userID int) (int, error)
+	GetDrafts(ctx context.Context) ([]db.Invoice, error)
+	GetLineItems(ctx context.Context, invoiceID int) ([]db.InvoiceLineItem, error)
+	Finalize(ctx context.Context, id int, issuedAt, dueAt time.Time) (db.Invoice, bool, error)
+	MarkPaid(ctx context.Context, id int, paidAt time.Time) (db.Invoice, bool, error)
+	Void(ctx context.Context, id int, voidedAt time.Time) (db.Invoice, bool, error)
+}
+
+type invoiceRepository struct {
+	*BaseRepository
+}
+
+func NewInvoiceRepository(database *sql.DB) InvoiceRepository {
+	return &invoiceRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+// CreateDraft stores the invoice, its line items and the link to the billed
+// subscription changes in one transaction.
+func (r *invoiceRepository) CreateDraft(ctx context.Context, draft InvoiceDraft) (db.Invoice, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return db.Invoice{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+
+	createInvoiceParams := db.CreateInvoiceParams{
+		CustomerName:  draft.CustomerName,
+		CustomerEmail: draft.CustomerEmail,
+		Country:       draft.Country,
+		PeriodStart:   draft.PeriodStart,
+		PeriodEnd:     draft.PeriodEnd,
+		Subtotal:      draft.Subtotal,
+		TaxName:       draft.TaxName,
+		TaxRate:       draft.TaxRate,
+		TaxAmount:     draft.TaxAmount,
+		Total:         draft.Total,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if draft.UserID != 0 {
+		createInvoiceParams.UserID = sql.NullInt32{Int32: int32(draft.UserID), Valid: true}
+	}
+	if draft.SubscriptionID != 0 {
+		createInvoiceParams.SubscriptionID = sql.NullInt32{Int32: int32(draft.SubscriptionID), Valid: true}
+	}
+
+	invoice, err := queries.CreateInvoice(ctx, createInvoiceParams)
+	if err != nil {
+		return db.Invoice{}, fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	for _, item := range draft.Items {
+		_, err := queries.CreateInvoiceLineItem(ctx, db.CreateInvoiceLineItemParams{
+			InvoiceID:   invoice.ID,
+			Description: item.Description,
+			Quantity:    int32(item.Quantity),
+			UnitPrice:   item.UnitPrice,
+			Amount:      item.Amount,
+		})
+		if err != nil {
+			return db.Invoice{}, fmt.Errorf("failed to create invoice line item: %w", err)
+		}
+	}
+
+	for _, changeID := range draft.ChangeIDs {
+		err := queries.SetSubscriptionChangeInvoice(ctx, db.SetSubscriptionChangeInvoiceParams{
+			ID:        int32(changeID),
+			InvoiceID: sql.NullInt32{Int32: invoice.ID, Valid: true},
+		})
+		if err != nil {
+			return db.Invoice{}, fmt.Errorf("failed to link subscription change: %w", err)
+		}
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return db.Invoice{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return invoice, nil
+}
+
+func (r *invoiceRepository) GetByID(ctx context.Context, id int) (db.Invoice, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	invoice, err := r.GetQueries().GetInvoiceByID(ctx, int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Invoice{}, fmt.Errorf("invoice not found")
+		}
+		return db.Invoice{}, fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// GetBySubscriptionPeriod returns ErrRecordNotFound when the period has not
+// been invoiced yet.
+func (r *invoiceRepository) GetBySubscriptionPeriod(ctx context.Context, subscriptionID int, periodStart time.Time) (db.Invoice, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	invoice, err := r.GetQueries().GetInvoiceBySubscriptionPeriod(ctx, db.GetInvoiceBySubscriptionPeriodParams{
+		SubscriptionID: sql.NullInt32{Int32: int32(subscriptionID), Valid: true},
+		PeriodStart:    periodStart,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Invoice{}, ErrRecordNotFound
+		}
+		return db.Invoice{}, fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	return invoice, nil
+}
+
+func (r *invoiceRepository) GetAll(ctx context.Context, limit, offset int) ([]db.Invoice, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	invoices, err := r.GetQueries().ListInvoices(ctx, db.ListInvoicesParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoices: %w", err)
+	}
+
+	return invoices, nil
+}
+
+func (r *invoiceRepository) Count(ctx context.Context) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := r.GetQueries().CountInvoices(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count invoices: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// GetIssuedByUserID returns the user's invoices except drafts, newest first.
+func (r *invoiceRepository) GetIssuedByUserID(ctx context.Context, userID, limit, offset int) ([]db.Invoice, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	invoices, err := r.GetQueries().ListIssuedInvoicesByUserID(ctx, db.ListIssuedInvoicesByUserIDParams{
+		UserID: sql.NullInt32{Int32: int32(userID), Valid: true},
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoices: %w", err)
+	}
+
+	return invoices, nil
+}
+
+func (r *invoiceRepository) CountIssuedByUserID(ctx context.Context, userID int) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := r.GetQueries().CountIssuedInvoicesByUserID(ctx, sql.NullInt32{Int32: int32(userID), Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count invoices: %w", err)
+	}
+
+	return int(count), nil
+}
+
+func (r *invoiceRepository) GetDrafts(ctx context.Context) ([]db.Invoice, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	invoices, err := r.GetQueries().ListDraftInvoices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft invoices: %w", err)
+	}
+
+	return invoices, nil
+}
+
+func (r *invoiceRepository) GetLineItems(ctx context.Context, invoiceID int) ([]db.InvoiceLineItem, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	items, err := r.GetQueries().ListInvoiceLineItems(ctx, int32(invoiceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice line items: %w", err)
+	}
+
+	return items, nil
+}
+
+// Finalize issues a draft invoice under the next number of the issue year,
+// formatted as INV-2026-000042. The number is taken in the same transaction
+// as the status change, so it is only used up if the invoice is issued. It
+// reports false, and changes nothing, if the invoice is not a draft.
+func (r *invoiceRepository) Finalize(ctx context.Context, id int, issuedAt, dueAt time.Time) (db.Invoice, bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return db.Invoice{}, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+
+	sequence, err := queries.NextInvoiceNumber(ctx, int32(issuedAt.Year()))
+	if err != nil {
+		return db.Invoice{}, false, fmt.Errorf("failed to get invoice number: %w", err)
+	}
+
+	invoice, err := queries.FinalizeInvoice(ctx, db.FinalizeInvoiceParams{
+		ID:       int32(id),
+		Number:   sql.NullString{String: fmt.Sprintf("INV-%d-%06d", issuedAt.Year(), sequence), Valid: true},
+		IssuedAt: sql.NullTime{Time: issuedAt, Valid: true},
+		DueAt:    sql.NullTime{Time: dueAt, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Invoice{}, false, nil
+		}
+		return db.Invoice{}, false, fmt.Errorf("failed to finalize invoice: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return db.Invoice{}, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return invoice, true, nil
+}
+
+// MarkPaid reports false if the invoice is not open.
+func (r *invoiceRepository) MarkPaid(ctx context.Context, id int, paidAt time.Time) (db.Invoice, bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	invoice, err := r.GetQueries().MarkInvoicePaid(ctx, db.MarkInvoicePaidParams{
+		ID:     int32(id),
+		PaidAt: sql.NullTime{Time: paidAt, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Invoice{}, false, nil
+		}
+		return db.Invoice{}, false, fmt.Errorf("failed to mark invoice paid: %w", err)
+	}
+
+	return invoice, true, nil
+}
+
+// Void reports false if the invoice is already paid or void. A voided
+// invoice keeps its number; a voided draft never had one.
+func (r *invoiceRepository) Void(ctx context.Context, id int, voidedAt time.Time) (db.Invoice, bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	invoice, err := r.GetQueries().VoidInvoice(ctx, db.VoidInvoiceParams{
+		ID:       int32(id),
+		VoidedAt: sql.NullTime{Time: voidedAt, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Invoice{}, false, nil
+		}
+		return db.Invoice{}, false, fmt.Errorf("failed to void invoice: %w", err)
+	}
+
+	return invoice, true, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type MagicLinkRepository interface {
+	Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) (db.MagicLinkToken, error)
+	Consume(ctx context.Context, tokenHash string) (db.MagicLinkToken, error)
+	InvalidateAllForUser(ctx context.Context, userID int) error
+}
+
+type magicLinkRepository struct {
+	*BaseRepository
+}
+
+func NewMagicLinkRepository(database *sql.DB) MagicLinkRepository {
+	return &magicLinkRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+func (r *magicLinkRepository) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) (db.MagicLinkToken, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.GetQueries().CreateMagicLinkToken(ctx, db.CreateMagicLinkTokenParams{
+		UserID:    int32(userID),
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return db.MagicLinkToken{}, fmt.Errorf("failed to create magic link token: %w", err)
+	}
+
+	return result, nil
+}
+
+// Consume marks an unused, unexpired token as used in a single statement, so a
+// link cannot be redeemed twice even by concurrent requests.
+func (r *magicLinkRepository) Consume(ctx context.Context, tokenHash string) (db.MagicLinkToken, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	token, err := r.GetQueries().ConsumeMagicLinkToken(ctx, db.ConsumeMagicLinkTokenParams{
+		TokenHash: tokenHash,
+		UsedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.MagicLinkToken{}, fmt.Errorf("magic link token not found")
+		}
+		return db.MagicLinkToken{}, fmt.Errorf("failed to consume magic link token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *magicLinkRepository) InvalidateAllForUser(ctx context.Context, userID int) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := r.GetQueries().InvalidateUserMagicLinkTokens(ctx, db.InvalidateUserMagicLinkTokensParams{
+		UserID: int32(userID),
+		UsedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to invalidate magic link tokens: %w", err)
+	}
+
+	return nil
+}
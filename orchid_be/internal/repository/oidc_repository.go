@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type OIDCRepository interface {
+	CreateLoginState(ctx context.Context, stateHash, provider, codeVerifier, nonce string, expiresAt time.Time) error
+	ConsumeLoginState(ctx context.Context, stateHash string) (db.OidcLoginState, error)
+	DeleteExpiredLoginStates(ctx context.Context) error
+	GetIdentity(ctx context.Context, provider, subject string) (db.UserIdentity, error)
+	CreateIdentity(ctx context.Context, userID int, provider, subject, email string) (db.UserIdentity, error)
+	TouchIdentity(ctx context.Context, id int, email string) error
+}
+
+type oidcRepository struct {
+	*BaseRepository
+}
+
+func NewOIDCRepository(database *sql.DB) OIDCRepository {
+	return &oidcRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+func (r *oidcRepository) CreateLoginState(ctx context.Context, stateHash, provider, codeVerifier, nonce string, expiresAt time.Time) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := r.GetQueries().CreateOidcLoginState(ctx, db.CreateOidcLoginStateParams{
+		StateHash:    stateHash,
+		Provider:     provider,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		ExpiresAt:    expiresAt,
+		CreatedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create oidc login state: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeLoginState deletes and returns the state so every state can be used once.
+func (r *oidcRepository) ConsumeLoginState(ctx context.Context, stateHash string) (db.OidcLoginState, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	state, err := r.GetQueries().ConsumeOidcLoginState(ctx, stateHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.OidcLoginState{}, fmt.Errorf("oidc login state not found")
+		}
+		return db.OidcLoginState{}, fmt.Errorf("failed to consume oidc login state: %w", err)
+	}
+
+	return state, nil
+}
+
+func (r *oidcRepository) DeleteExpiredLoginStates(ctx context.Context) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := r.GetQueries().DeleteExpiredOidcLoginStates(ctx, time.Now()); err != nil {
+		return fmt.Errorf("failed to delete expired oidc login states: %w", err)
+	}
+
+	return nil
+}
+
+func (r *oidcRepository) GetIdentity(ctx context.Context, provider, subject string) (db.UserIdentity, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	identity, err := r.GetQueries().GetUserIdentity(ctx, db.GetUserIdentityParams{
+		Provider: provider,
+		Subject:  subject,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.UserIdentity{}, fmt.Errorf("identity not found")
+		}
+		return db.UserIdentity{}, fmt.Errorf("failed to get identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+func (r *oidcRepository) CreateIdentity(ctx context.Context, userID int, provider, subject, email string) (db.UserIdentity, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+	identity, err := r.GetQueries().CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+		UserID:      int32(userID),
+		Provider:    provider,
+		Subject:     subject,
+		Email:       email,
+		LastLoginAt: now,
+		CreatedAt:   now,
+	})
+	if err != nil {
+		return db.UserIdentity{}, fmt.Errorf("failed to create identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+// TouchIdentity records a login and the email the provider reported for it.
+func (r *oidcRepository) TouchIdentity(ctx context.Context, id int, email string) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := r.GetQueries().TouchUserIdentity(ctx, db.TouchUserIdentityParams{
+		ID:          int32(id),
+		Email:       email,
+		LastLoginAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update identity: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) (db.PasswordResetToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (db.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id int) (bool, error)
+	InvalidateAllForUser(ctx context.Context, userID int) error
+}
+
+type passwordResetRepository struct {
+	*BaseRepository
+}
+
+func NewPasswordResetRepository(database *sql.DB) PasswordResetRepository {
+	return &passwordResetRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) (db.PasswordResetToken, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.GetQueries().CreatePasswordResetToken(ctx, db.CreatePasswordResetTokenParams{
+		UserID:    int32(userID),
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return db.PasswordResetToken{}, fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *passwordResetRepository) GetByHash(ctx context.Context, tokenHash string) (db.PasswordResetToken, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	token, err := r.GetQueries().GetPasswordResetTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.PasswordResetToken{}, fmt.Errorf("password reset token not found")
+		}
+		return db.PasswordResetToken{}, fmt.Errorf("failed to get password reset token: %w", err)
+	}
+
+	return token, nil
+}
+
+// MarkUsed consumes the token and reports false if it had already been used.
+func (r *passwordResetRepository) MarkUsed(ctx context.Context, id int) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.GetQueries().MarkPasswordResetTokenUsed(ctx, db.MarkPasswordResetTokenUsedParams{
+		ID:     int32(id),
+		UsedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to mark password reset token used: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *passwordResetRepository) InvalidateAllForUser(ctx context.Context, userID int) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := r.GetQueries().InvalidateUserPasswordResetTokens(ctx, db.InvalidateUserPasswordResetTokensParams{
+		UserID: int32(userID),
+		UsedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to invalidate password reset tokens: %w", err)
+	}
+
+	return nil
+}
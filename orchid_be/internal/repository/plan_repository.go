@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type PlanRepository interface {
+	GetByID(ctx context.Context, id int) (db.Plan, error)
+	ListActive(ctx context.Context) ([]db.Plan, error)
+	ListAll(ctx context.Context) ([]db.Plan, error)
+	GetLimits(ctx context.Context, planID int) ([]db.PlanLimit, error)
+	ListLimits(ctx context.Context) ([]db.PlanLimit, error)
+}
+
+type planRepository struct {
+	*BaseRepository
+}
+
+func NewPlanRepository(database *sql.DB) PlanRepository {
+	return &planRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+func (r *planRepository) GetByID(ctx context.Context, id int) (db.Plan, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	plan, err := r.GetQueries().GetPlanByID(ctx, int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Plan{}, fmt.Errorf("plan not found")
+		}
+		return db.Plan{}, fmt.Errorf("failed to get plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+// ListActive returns the plans that can be subscribed to, cheapest first.
+func (r *planRepository) ListActive(ctx context.Context) ([]db.Plan, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	plans, err := r.GetQueries().ListActivePlans(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans: %w", err)
+	}
+
+	return plans, nil
+}
+
+// ListAll returns every plan, retired ones included.
+func (r *planRepository) ListAll(ctx context.Context) ([]db.Plan, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	plans, err := r.GetQueries().ListPlans(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans: %w", err)
+	}
+
+	return plans, nil
+}
+
+func (r *planRepository) GetLimits(ctx context.Context, planID int) ([]db.PlanLimit, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	limits, err := r.GetQueries().GetPlanLimits(ctx, int32(planID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan limits: %w", err)
+	}
+
+	return limits, nil
+}
+
+// ListLimits returns the limits of every plan.
+func (r *planRepository) ListLimits(ctx context.Context) ([]db.PlanLimit, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	limits, err := r.GetQueries().ListPlanLimits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plan limits: %w", err)
+	}
+
+	return limits, nil
+}
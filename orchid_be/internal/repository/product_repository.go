@@ -0,0 +1,345 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+// ProductFields holds the editable columns of a product. Price and discount
+// are decimal strings as stored in the NUMERIC columns.
+type ProductFields struct {
+	Name         string
+	CategoryID   int
+	TechnologyID int
+	Description  string
+	Price        string
+	Discount     string
+}
+
+// PriceChange is an entry of a product's price history. An empty Price or
+// Discount leaves that value as it is; a zero EndsAt keeps the change in
+// effect until a later one replaces it.
+type PriceChange struct {
+	Price     string
+	Discount  string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	CreatedBy int
+}
+
+// PricedProduct is a product with the price and discount in effect at the
+// time it was read for.
+type PricedProduct struct {
+	db.Product
+	EffectivePrice    string
+	EffectiveDiscount string
+}
+
+type ProductRepository interface {
+	Create(ctx context.Context, fields ProductFields, createdBy int) (db.Product, error)
+	GetByID(ctx context.Context, id int) (db.Product, error)
+	GetPriced(ctx context.Context, id int, at time.Time) (PricedProduct, error)
+	GetAllPriced(ctx context.Context, at time.Time, limit, offset int) ([]PricedProduct, error)
+	Update(ctx context.Context, id int, fields ProductFields, change *PriceChange) (db.Product, error)
+	Delete(ctx context.Context, id int) (bool, error)
+	Count(ctx context.Context) (int, error)
+	SchedulePriceChange(ctx context.Context, productID int, change PriceChange) (db.ProductPrice, error)
+	GetPriceHistory(ctx context.Context, productID, limit, offset int) ([]db.ProductPrice, error)
+	CountPriceHistory(ctx context.Context, productID int) (int, error)
+	CancelPriceChange(ctx context.Context, productID, id int) (bool, error)
+}
+
+type productRepository struct {
+	*BaseRepository
+}
+
+func NewProductRepository(database *sql.DB) ProductRepository {
+	return &productRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+// Create adds the product and starts its price history with the initial
+// price and discount.
+func (r *productRepository) Create(ctx context.Context, fields ProductFields, createdBy int) (db.Product, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return db.Product{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := time.Now()
+
+	createProductParams := db.CreateProductParams{
+		Name:         fields.Name,
+		CategoryID:   int32(fields.CategoryID),
+		TechnologyID: int32(fields.TechnologyID),
+		Description:  fields.Description,
+		Price:        fields.Price,
+		Discount:     fields.Discount,
+		CreatedAt:    sql.NullTime{Time: now, Valid: true},
+		UpdatedAt:    sql.NullTime{Time: now, Valid: true},
+	}
+
+	product, err := queries.CreateProduct(ctx, createProductParams)
+	if err != nil {
+		return db.Product{}, fmt.Errorf("failed to create product: %w", err)
+	}
+
+	_, err = queries.CreateProductPrice(ctx, createProductPriceParams(int(product.ID), PriceChange{
+		Price:     fields.Price,
+		Discount:  fields.Discount,
+		StartsAt:  now,
+		CreatedBy: createdBy,
+	}))
+	if err != nil {
+		return db.Product{}, fmt.Errorf("failed to create product price: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return db.Product{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return product, nil
+}
+
+func (r *productRepository) GetByID(ctx context.Context, id int) (db.Product, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	product, err := r.GetQueries().GetProductByID(ctx, int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Product{}, fmt.Errorf("product not found")
+		}
+		return db.Product{}, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	return product, nil
+}
+
+func (r *productRepository) GetPriced(ctx context.Context, id int, at time.Time) (PricedProduct, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	row, err := r.GetQueries().GetPricedProduct(ctx, db.GetPricedProductParams{
+		At: at,
+		ID: int32(id),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return PricedProduct{}, fmt.Errorf("product not found")
+		}
+		return PricedProduct{}, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	return PricedProduct{
+		Product: db.Product{
+			ID:           row.ID,
+			Name:         row.Name,
+			Description:  row.Description,
+			Price:        row.Price,
+			Discount:     row.Discount,
+			CreatedAt:    row.CreatedAt,
+			UpdatedAt:    row.UpdatedAt,
+			CategoryID:   row.CategoryID,
+			TechnologyID: row.TechnologyID,
+		},
+		EffectivePrice:    row.EffectivePrice,
+		EffectiveDiscount: row.EffectiveDiscount,
+	}, nil
+}
+
+func (r *productRepository) GetAllPriced(ctx context.Context, at time.Time, limit, offset int) ([]PricedProduct, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.GetQueries().GetAllPricedProducts(ctx, db.GetAllPricedProductsParams{
+		At:     at,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	products := make([]PricedProduct, len(rows))
+	for i, row := range rows {
+		products[i] = PricedProduct{
+			Product: db.Product{
+				ID:           row.ID,
+				Name:         row.Name,
+				Description:  row.Description,
+				Price:        row.Price,
+				Discount:     row.Discount,
+				CreatedAt:    row.CreatedAt,
+				UpdatedAt:    row.UpdatedAt,
+				CategoryID:   row.CategoryID,
+				TechnologyID: row.TechnologyID,
+			},
+			EffectivePrice:    row.EffectivePrice,
+			EffectiveDiscount: row.EffectiveDiscount,
+		}
+	}
+
+	return products, nil
+}
+
+// Update saves the product. A non-nil change is added to the price history in
+// the same transaction.
+func (r *productRepository) Update(ctx context.Context, id int, fields ProductFields, change *PriceChange) (db.Product, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return db.Product{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+
+	updateProductParams := db.UpdateProductParams{
+		ID:           int32(id),
+		Name:         fields.Name,
+		CategoryID:   int32(fields.CategoryID),
+		TechnologyID: int32(fields.TechnologyID),
+		Description:  fields.Description,
+		Price:        fields.Price,
+		Discount:     fields.Discount,
+		UpdatedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	product, err := queries.UpdateProduct(ctx, updateProductParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Product{}, fmt.Errorf("product not found")
+		}
+		return db.Product{}, fmt.Errorf("failed to update product: %w", err)
+	}
+
+	if change != nil {
+		if _, err := queries.CreateProductPrice(ctx, createProductPriceParams(id, *change)); err != nil {
+			return db.Product{}, fmt.Errorf("failed to create product price: %w", err)
+		}
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return db.Product{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return product, nil
+}
+
+// Delete removes the product. It reports false if no product has the id.
+func (r *productRepository) Delete(ctx context.Context, id int) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.GetQueries().DeleteProduct(ctx, int32(id))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete product: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *productRepository) Count(ctx context.Context) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := r.GetQueries().CountProducts(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count products: %w", err)
+	}
+
+	return int(count), nil
+}
+
+func (r *productRepository) SchedulePriceChange(ctx context.Context, productID int, change PriceChange) (db.ProductPrice, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	price, err := r.GetQueries().CreateProductPrice(ctx, createProductPriceParams(productID, change))
+	if err != nil {
+		return db.ProductPrice{}, fmt.Errorf("failed to create product price: %w", err)
+	}
+
+	return price, nil
+}
+
+func (r *productRepository) GetPriceHistory(ctx context.Context, productID, limit, offset int) ([]db.ProductPrice, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	prices, err := r.GetQueries().ListProductPrices(ctx, db.ListProductPricesParams{
+		ProductID: int32(productID),
+		Limit:     int32(limit),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product prices: %w", err)
+	}
+
+	return prices, nil
+}
+
+func (r *productRepository) CountPriceHistory(ctx context.Context, productID int) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := r.GetQueries().CountProductPrices(ctx, int32(productID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count product prices: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// CancelPriceChange removes a scheduled change that has not started yet.
+// Changes that already took effect are history and are kept; it reports false
+// for them and for unknown ids.
+func (r *productRepository) CancelPriceChange(ctx context.Context, productID, id int) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.GetQueries().DeletePendingProductPrice(ctx, db.DeletePendingProductPriceParams{
+		ID:        int32(id),
+		ProductID: int32(productID),
+		StartsAt:  time.Now(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel product price: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func createProductPriceParams(productID int, change PriceChange) db.CreateProductPriceParams {
+	params := db.CreateProductPriceParams{
+		ProductID: int32(productID),
+		StartsAt:  change.StartsAt,
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	if change.Price != "" {
+		params.Price = sql.NullString{String: change.Price, Valid: true}
+	}
+	if change.Discount != "" {
+		params.Discount = sql.NullString{String: change.Discount, Valid: true}
+	}
+	if !change.EndsAt.IsZero() {
+		params.EndsAt = sql.NullTime{Time: change.EndsAt, Valid: true}
+	}
+	if change.CreatedBy != 0 {
+		params.CreatedBy = sql.NullInt32{Int32: int32(change.CreatedBy), Valid: true}
+	}
+	return params
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+var ErrRefreshTokenAlreadyRotated = errors.New("refresh token has already been rotated")
+
+type RefreshTokenRepository interface {
+	GetByHash(ctx context.Context, tokenHash string) (db.RefreshToken, error)
+	Rotate(ctx context.Context, current db.RefreshToken, newTokenHash string, expiresAt time.Time) (db.RefreshToken, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID int) error
+}
+
+type refreshTokenRepository struct {
+	*BaseRepository
+}
+
+func NewRefreshTokenRepository(database *sql.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (db.RefreshToken, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	token, err := r.GetQueries().GetRefreshTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.RefreshToken{}, fmt.Errorf("refresh token not found")
+		}
+		return db.RefreshToken{}, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Rotate marks the current token as used and issues its successor in the same
+// family. It fails with ErrRefreshTokenAlreadyRotated if another request got there first.
+func (r *refreshTokenRepository) Rotate(ctx context.Context, current db.RefreshToken, newTokenHash string, expiresAt time.Time) (db.RefreshToken, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return db.RefreshToken{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := time.Now()
+
+	rows, err := queries.MarkRefreshTokenRotated(ctx, db.MarkRefreshTokenRotatedParams{
+		ID:        current.ID,
+		RotatedAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return db.RefreshToken{}, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if rows == 0 {
+		return db.RefreshToken{}, ErrRefreshTokenAlreadyRotated
+	}
+
+	next, err := queries.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
+		UserID:    current.UserID,
+		TokenHash: newTokenHash,
+		FamilyID:  current.FamilyID,
+		ExpiresAt: expiresAt,
+		CreatedAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return db.RefreshToken{}, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return db.RefreshToken{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return next, nil
+}
+
+// RevokeFamily revokes every token of one login and ends its session.
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+
+	err = queries.RevokeRefreshTokenFamily(ctx, db.RevokeRefreshTokenFamilyParams{
+		FamilyID:  familyID,
+		RevokedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	err = queries.RevokeSessionByFamily(ctx, db.RevokeSessionByFamilyParams{
+		FamilyID:  familyID,
+		RevokedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeAllForUser revokes every token of the user and ends all their sessions.
+func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+
+	err = queries.RevokeUserRefreshTokens(ctx, db.RevokeUserRefreshTokensParams{
+		UserID:    int32(userID),
+		RevokedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	err = queries.RevokeUserSessions(ctx, db.RevokeUserSessionsParams{
+		UserID:    int32(userID),
+		RevokedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type RoleRepository interface {
+	GetByID(ctx context.Context, id int) (db.Role, error)
+	GetByName(ctx context.Context, name string) (db.Role, error)
+	GetAll(ctx context.Context) ([]db.Role, error)
+	Create(ctx context.Context, name, description string, permissionIDs []int) (db.Role, error)
+	Delete(ctx context.Context, id int) error
+	GetPermissionByName(ctx context.Context, name string) (db.Permission, error)
+	GetAllPermissions(ctx context.Context) ([]db.Permission, error)
+	GetRolePermissions(ctx context.Context, roleID int) ([]db.Permission, error)
+	GetUserRoles(ctx context.Context, userID int) ([]db.Role, error)
+	GetUserPermissionNames(ctx context.Context, userID int) ([]string, error)
+	AssignToUser(ctx context.Context, userID, roleID int) error
+	RemoveFromUser(ctx context.Context, userID, roleID int) error
+	RemoveFromUserUnlessLast(ctx context.Context, userID, roleID int) (bool, error)
+}
+
+type roleRepository struct {
+	*BaseRepository
+}
+
+func NewRoleRepository(database *sql.DB) RoleRepository {
+	return &roleRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+func (r *roleRepository) GetByID(ctx context.Context, id int) (db.Role, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	role, err := r.GetQueries().GetRoleByID(ctx, int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Role{}, fmt.Errorf("role with id %d not found", id)
+		}
+		return db.Role{}, fmt.Errorf("failed to get role by id: %w", err)
+	}
+
+	return role, nil
+}
+
+func (r *roleRepository) GetByName(ctx context.Context, name string) (db.Role, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	role, err := r.GetQueries().GetRoleByName(ctx, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Role{}, fmt.Errorf("role %s not found", name)
+		}
+		return db.Role{}, fmt.Errorf("failed to get role by name: %w", err)
+	}
+
+	return role, nil
+}
+
+func (r *roleRepository) GetAll(ctx context.Context) ([]db.Role, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	roles, err := r.GetQueries().ListRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// Create inserts the role and grants its permissions in one transaction.
+func (r *roleRepository) Create(ctx context.Context, name, description string, permissionIDs []int) (db.Role, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return db.Role{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := time.Now()
+
+	role, err := queries.CreateRole(ctx, db.CreateRoleParams{
+		Name:        name,
+		Description: description,
+		CreatedAt:   sql.NullTime{Time: now, Valid: true},
+		UpdatedAt:   sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return db.Role{}, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	for _, permissionID := range permissionIDs {
+		err := queries.AddRolePermission(ctx, db.AddRolePermissionParams{
+			RoleID:       role.ID,
+			PermissionID: int32(permissionID),
+		})
+		if err != nil {
+			return db.Role{}, fmt.Errorf("failed to grant permission to role: %w", err)
+		}
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return db.Role{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return role, nil
+}
+
+func (r *roleRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := r.GetQueries().DeleteRole(ctx, int32(id)); err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleRepository) GetPermissionByName(ctx context.Context, name string) (db.Permission, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	permission, err := r.GetQueries().GetPermissionByName(ctx, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Permission{}, fmt.Errorf("permission %s not found", name)
+		}
+		return db.Permission{}, fmt.Errorf("failed to get permission: %w", err)
+	}
+
+	return permission, nil
+}
+
+func (r *roleRepository) GetAllPermissions(ctx context.Context) ([]db.Permission, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	permissions, err := r.GetQueries().ListPermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+func (r *roleRepository) GetRolePermissions(ctx context.Context, roleID int) ([]db.Permission, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	permissions, err := r.GetQueries().ListRolePermissions(ctx, int32(roleID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+func (r *roleRepository) GetUserRoles(ctx context.Context, userID int) ([]db.Role, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	roles, err := r.GetQueries().ListUserRoles(ctx, int32(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) GetUserPermissionNames(ctx context.Context, userID int) ([]string, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	permissions, err := r.GetQueries().ListUserPermissionNames(ctx, int32(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+func (r *roleRepository) AssignToUser(ctx context.Context, userID, roleID int) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := r.GetQueries().AssignUserRole(ctx, db.AssignUserRoleParams{
+		UserID:    int32(userID),
+		RoleID:    int32(roleID),
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleRepository) RemoveFromUser(ctx context.Context, userID, roleID int) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := r.GetQueries().RemoveUserRole(ctx, db.RemoveUserRoleParams{
+		UserID: int32(userID),
+		RoleID: int32(roleID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove role: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveFromUserUnlessLast removes the role from the user but reports false,
+// and changes nothing, when the user is the only one holding it. The role row
+// stays locked until the removal commits, so concurrent removals are counted
+// one after another and cannot take the role from everyone. A user without
+// the role is left as is.
+func (r *roleRepository) RemoveFromUserUnlessLast(ctx context.Context, userID, roleID int) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+
+	if _, err := queries.LockRole(ctx, int32(roleID)); err != nil {
+		if err == sql.ErrNoRows {
+			return false, fmt.Errorf("role with id %d not found", roleID)
+		}
+		return false, fmt.Errorf("failed to lock role: %w", err)
+	}
+
+	removed, err := queries.RemoveUserRole(ctx, db.RemoveUserRoleParams{
+		UserID: int32(userID),
+		RoleID: int32(roleID),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to remove role: %w", err)
+	}
+	if removed == 0 {
+		return true, nil
+	}
+
+	remaining, err := queries.CountRoleUsers(ctx, int32(roleID))
+	if err != nil {
+		return false, fmt.Errorf("failed to count role users: %w", err)
+	}
+	if remaining == 0 {
+		return false, nil
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
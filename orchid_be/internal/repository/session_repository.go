@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, userID int, familyID, tokenHash, device, userAgent, ipAddress string, expiresAt time.Time) (db.Session, error)
+	GetByID(ctx context.Context, id int) (db.Session, error)
+	GetActiveForUser(ctx context.Context, userID int) ([]db.Session, error)
+	Refresh(ctx context.Context, familyID, userAgent, ipAddress string, expiresAt time.Time) (db.Session, error)
+	Touch(ctx context.Context, id int, staleBefore time.Time) error
+	SetElevation(ctx context.Context, id, userID int, until *time.Time) (bool, error)
+	Revoke(ctx context.Context, id, userID int) (bool, error)
+	RevokeOthers(ctx context.Context, userID int, keepFamilyID string) error
+}
+
+type sessionRepository struct {
+	*BaseRepository
+}
+
+func NewSessionRepository(database *sql.DB) SessionRepository {
+	return &sessionRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+// Create starts a session together with the first refresh token of its family.
+func (r *sessionRepository) Create(ctx context.Context, userID int, familyID, tokenHash, device, userAgent, ipAddress string, expiresAt time.Time) (db.Session, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return db.Session{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+
+	session, err := queries.CreateSession(ctx, db.CreateSessionParams{
+		UserID:     int32(userID),
+		FamilyID:   familyID,
+		Device:     device,
+		UserAgent:  userAgent,
+		IpAddress:  ipAddress,
+		ExpiresAt:  expiresAt,
+		LastSeenAt: now,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return db.Session{}, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	_, err = queries.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
+		UserID:    int32(userID),
+		TokenHash: tokenHash,
+		FamilyID:  familyID,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return db.Session{}, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return db.Session{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return session, nil
+}
+
+func (r *sessionRepository) GetByID(ctx context.Context, id int) (db.Session, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	session, err := r.GetQueries().GetSessionByID(ctx, int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Session{}, fmt.Errorf("session not found")
+		}
+		return db.Session{}, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return session, nil
+}
+
+func (r *sessionRepository) GetActiveForUser(ctx context.Context, userID int) ([]db.Session, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	sessions, err := r.GetQueries().ListActiveUserSessions(ctx, db.ListActiveUserSessionsParams{
+		UserID:    int32(userID),
+		ExpiresAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// Refresh records the client that rotated the refresh token and extends the
+// session to the new token's expiry.
+func (r *sessionRepository) Refresh(ctx context.Context, familyID, userAgent, ipAddress string, expiresAt time.Time) (db.Session, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	session, err := r.GetQueries().RefreshSession(ctx, db.RefreshSessionParams{
+		FamilyID:   familyID,
+		UserAgent:  userAgent,
+		IpAddress:  ipAddress,
+		ExpiresAt:  expiresAt,
+		LastSeenAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Session{}, fmt.Errorf("session not found")
+		}
+		return db.Session{}, fmt.Errorf("failed to refresh session: %w", err)
+	}
+
+	return session, nil
+}
+
+// Touch records activity on the session. The write is skipped when
+// last_seen_at is newer than staleBefore.
+func (r *sessionRepository) Touch(ctx context.Context, id int, staleBefore time.Time) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := r.GetQueries().TouchSession(ctx, db.TouchSessionParams{
+		SeenAt:      sql.NullTime{Time: time.Now(), Valid: true},
+		ID:          int32(id),
+		StaleBefore: sql.NullTime{Time: staleBefore, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update session last seen: %w", err)
+	}
+
+	return nil
+}
+
+// SetElevation marks the session as recently re-authenticated until the given
+// time, or clears the mark when until is nil. It reports false if the session
+// does not belong to the user or is revoked.
+func (r *sessionRepository) SetElevation(ctx context.Context, id, userID int, until *time.Time) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	setSessionElevationParams := db.SetSessionElevationParams{
+		ID:     int32(id),
+		UserID: int32(userID),
+	}
+	if until != nil {
+		setSessionElevationParams.ElevatedUntil = sql.NullTime{Time: *until, Valid: true}
+	}
+
+	rows, err := r.GetQueries().SetSessionElevation(ctx, setSessionElevationParams)
+	if err != nil {
+		return false, fmt.Errorf("failed to update session elevation: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// Revoke ends the session and its refresh tokens. It reports false if the
+// session does not belong to the user or is already revoked.
+func (r *sessionRepository) Revoke(ctx context.Context, id, userID int) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+
+	familyID, err := queries.RevokeSession(ctx, db.RevokeSessionParams{
+		ID:        int32(id),
+		UserID:    int32(userID),
+		RevokedAt: now,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	err = queries.RevokeRefreshTokenFamily(ctx, db.RevokeRefreshTokenFamilyParams{
+		FamilyID:  familyID,
+		RevokedAt: now,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// RevokeOthers ends every session of the user except the one with keepFamilyID.
+func (r *sessionRepository) RevokeOthers(ctx context.Context, userID int, keepFamilyID string) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+
+	err = queries.RevokeOtherUserSessions(ctx, db.RevokeOtherUserSessionsParams{
+		UserID:    int32(userID),
+		FamilyID:  keepFamilyID,
+		RevokedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	err = queries.RevokeOtherUserRefreshTokens(ctx, db.RevokeOtherUserRefreshTokensParams{
+		UserID:    int32(userID),
+		FamilyID:  keepFamilyID,
+		RevokedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+// ErrSubscriptionChanged is returned when the subscription was changed by
+// another request since it was read.
+var ErrSubscriptionChanged = errors.New("subscription has been changed by another request")
+
+// PlanChange moves a subscription to another plan. Credit, Charge and
+// AmountDue are the prorated decimal amounts recorded for billing.
+type PlanChange struct {
+	PlanID      int
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Credit      string
+	Charge      string
+	AmountDue   string
+}
+
+type SubscriptionRepository interface {
+	GetActiveByUserID(ctx context.Context, userID int) (db.Subscription, error)
+	ListActive(ctx context.Context) ([]db.Subscription, error)
+	GetUnbilledChanges(ctx context.Context, subscriptionID int) ([]db.SubscriptionChange, error)
+	Create(ctx context.Context, userID, planID int, periodStart, periodEnd time.Time) (db.Subscription, error)
+	Renew(ctx context.Context, subscription db.Subscription, periodStart, periodEnd time.Time) (db.Subscription, error)
+	ChangePlan(ctx context.Context, subscription db.Subscription, change PlanChange) (db.Subscription, db.SubscriptionChange, error)
+	SetCancelAtPeriodEnd(ctx context.Context, subscription db.Subscription, cancelAtPeriodEnd bool) (db.Subscription, error)
+	Cancel(ctx context.Context, id int, canceledAt time.Time) (db.Subscription, error)
+}
+
+type subscriptionRepository struct {
+	*BaseRepository
+}
+
+func NewSubscriptionRepository(database *sql.DB) SubscriptionRepository {
+	return &subscriptionRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+func (r *subscriptionRepository) GetActiveByUserID(ctx context.Context, userID int) (db.Subscription, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	subscription, err := r.GetQueries().GetActiveSubscriptionByUserID(ctx, int32(userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Subscription{}, fmt.Errorf("subscription not found")
+		}
+		return db.Subscription{}, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (r *subscriptionRepository) ListActive(ctx context.Context) ([]db.Subscription, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	subscriptions, err := r.GetQueries().ListActiveSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// GetUnbilledChanges returns the plan changes of the subscription that no
+// invoice includes yet, oldest first.
+func (r *subscriptionRepository) GetUnbilledChanges(ctx context.Context, subscriptionID int) ([]db.SubscriptionChange, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	changes, err := r.GetQueries().ListUnbilledSubscriptionChanges(ctx, int32(subscriptionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+func (r *subscriptionRepository) Create(ctx context.Context, userID, planID int, periodStart, periodEnd time.Time) (db.Subscription, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	subscription, err := r.GetQueries().CreateSubscription(ctx, db.CreateSubscriptionParams{
+		UserID:             int32(userID),
+		PlanID:             int32(planID),
+		CurrentPeriodStart: periodStart,
+		CurrentPeriodEnd:   periodEnd,
+		CreatedAt:          sql.NullTime{Time: now, Valid: true},
+		UpdatedAt:          sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return db.Subscription{}, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// Renew starts the next period on the same plan.
+func (r *subscriptionRepository) Renew(ctx context.Context, subscription db.Subscription, periodStart, periodEnd time.Time) (db.Subscription, error) {
+	return r.updatePeriod(ctx, r.GetQueries(), subscription, db.UpdateSubscriptionPeriodParams{
+		PlanID:             subscription.PlanID,
+		CurrentPeriodStart: periodStart,
+		CurrentPeriodEnd:   periodEnd,
+		CancelAtPeriodEnd:  subscription.CancelAtPeriodEnd,
+	})
+}
+
+// ChangePlan switches the subscription to another plan and records the
+// change with its proration in one transaction. A pending cancellation is
+// withdrawn, choosing a plan means the subscriber wants to stay. It fails
+// with ErrSubscriptionChanged, recording nothing, if the plan or period
+// changed since subscription was read, so a change is never billed twice.
+func (r *subscriptionRepository) ChangePlan(ctx context.Context, subscription db.Subscription, change PlanChange) (db.Subscription, db.SubscriptionChange, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return db.Subscription{}, db.SubscriptionChange{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+
+	updated, err := r.updatePeriod(ctx, queries, subscription, db.UpdateSubscriptionPeriodParams{
+		PlanID:             int32(change.PlanID),
+		CurrentPeriodStart: change.PeriodStart,
+		CurrentPeriodEnd:   change.PeriodEnd,
+	})
+	if err != nil {
+		return db.Subscription{}, db.SubscriptionChange{}, err
+	}
+
+	subscriptionChange, err := queries.CreateSubscriptionChange(ctx, db.CreateSubscriptionChangeParams{
+		SubscriptionID: subscription.ID,
+		FromPlanID:     subscription.PlanID,
+		ToPlanID:       int32(change.PlanID),
+		Credit:         change.Credit,
+		Charge:         change.Charge,
+		AmountDue:      change.AmountDue,
+		CreatedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return db.Subscription{}, db.SubscriptionChange{}, fmt.Errorf("failed to create subscription change: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return db.Subscription{}, db.SubscriptionChange{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return updated, subscriptionChange, nil
+}
+
+func (r *subscriptionRepository) SetCancelAtPeriodEnd(ctx context.Context, subscription db.Subscription, cancelAtPeriodEnd bool) (db.Subscription, error) {
+	return r.updatePeriod(ctx, r.GetQueries(), subscription, db.UpdateSubscriptionPeriodParams{
+		PlanID:             subscription.PlanID,
+		CurrentPeriodStart: subscription.CurrentPeriodStart,
+		CurrentPeriodEnd:   subscription.CurrentPeriodEnd,
+		CancelAtPeriodEnd:  cancelAtPeriodEnd,
+	})
+}
+
+// Cancel ends the subscription. Canceled subscriptions are kept for the
+// billing history.
+func (r *subscriptionRepository) Cancel(ctx context.Context, id int, canceledAt time.Time) (db.Subscription, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	subscription, err := r.GetQueries().CancelSubscription(ctx, db.CancelSubscriptionParams{
+		ID:         int32(id),
+		CanceledAt: sql.NullTime{Time: canceledAt, Valid: true},
+		UpdatedAt:  sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Subscription{}, fmt.Errorf("subscription not found")
+		}
+		return db.Subscription{}, fmt.Errorf("failed to cancel subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// updatePeriod writes params over subscription as long as its plan and
+// period start are still the ones that were read. Otherwise, or when the
+// subscription is no longer active, it returns ErrSubscriptionChanged.
+func (r *subscriptionRepository) updatePeriod(ctx context.Context, queries *db.Queries, current db.Subscription, params db.UpdateSubscriptionPeriodParams) (db.Subscription, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	params.ID = current.ID
+	params.ExpectedPlanID = current.PlanID
+	params.ExpectedPeriodStart = current.CurrentPeriodStart
+	params.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	subscription, err := queries.UpdateSubscriptionPeriod(ctx, params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Subscription{}, ErrSubscriptionChanged
+		}
+		return db.Subscription{}, fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	return subscription, nil
+}
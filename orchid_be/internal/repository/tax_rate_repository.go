@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type TaxRateRepository interface {
+	Get(ctx context.Context, country string) (db.TaxRate, error)
+	List(ctx context.Context) ([]db.TaxRate, error)
+	Set(ctx context.Context, country, name, rate string) (db.TaxRate, error)
+	Delete(ctx context.Context, country string) (bool, error)
+}
+
+type taxRateRepository struct {
+	*BaseRepository
+}
+
+func NewTaxRateRepository(database *sql.DB) TaxRateRepository {
+	return &taxRateRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+func (r *taxRateRepository) Get(ctx context.Context, country string) (db.TaxRate, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	taxRate, err := r.GetQueries().GetTaxRate(ctx, country)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.TaxRate{}, fmt.Errorf("tax rate not found")
+		}
+		return db.TaxRate{}, fmt.Errorf("failed to get tax rate: %w", err)
+	}
+
+	return taxRate, nil
+}
+
+func (r *taxRateRepository) List(ctx context.Context) ([]db.TaxRate, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	taxRates, err := r.GetQueries().ListTaxRates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tax rates: %w", err)
+	}
+
+	return taxRates, nil
+}
+
+// Set creates or replaces the tax rate of the country.
+func (r *taxRateRepository) Set(ctx context.Context, country, name, rate string) (db.TaxRate, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	taxRate, err := r.GetQueries().UpsertTaxRate(ctx, db.UpsertTaxRateParams{
+		Country:   country,
+		Name:      name,
+		Rate:      rate,
+		CreatedAt: sql.NullTime{Time: now, Valid: true},
+		UpdatedAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return db.TaxRate{}, fmt.Errorf("failed to set tax rate: %w", err)
+	}
+
+	return taxRate, nil
+}
+
+// Delete removes the tax rate. It reports false if the country has none.
+func (r *taxRateRepository) Delete(ctx context.Context, country string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.GetQueries().DeleteTaxRate(ctx, country)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete tax rate: %w", err)
+	}
+
+	return rows > 0, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type TechnologyRepository interface {
+	CrudRepository[db.Technology]
+	List(ctx context.Context) ([]db.Technology, error)
+}
+
+type technologyRepository struct {
+	CrudRepository[db.Technology]
+	*BaseRepository
+}
+
+func NewTechnologyRepository(database *sql.DB) TechnologyRepository {
+	return &technologyRepository{
+		CrudRepository: NewCrudRepository[db.Technology](database, "technologies"),
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+// List returns every technology ordered by name.
+func (r *technologyRepository) List(ctx context.Context) ([]db.Technology, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	technologies, err := r.GetQueries().ListTechnologies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list technologies: %w", err)
+	}
+
+	return technologies, nil
+}
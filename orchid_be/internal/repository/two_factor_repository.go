@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type TwoFactorRepository interface {
+	GetByUserID(ctx context.Context, userID int) (db.TotpSecret, error)
+	SaveSecret(ctx context.Context, userID int, secret string) (db.TotpSecret, error)
+	Confirm(ctx context.Context, userID int, recoveryCodeHashes []string) (bool, error)
+	MarkStepUsed(ctx context.Context, userID int, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID int) (int, error)
+	Delete(ctx context.Context, userID int) error
+}
+
+type twoFactorRepository struct {
+	*BaseRepository
+}
+
+func NewTwoFactorRepository(database *sql.DB) TwoFactorRepository {
+	return &twoFactorRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+func (r *twoFactorRepository) GetByUserID(ctx context.Context, userID int) (db.TotpSecret, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	secret, err := r.GetQueries().GetTotpSecret(ctx, int32(userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.TotpSecret{}, fmt.Errorf("totp secret for user %d not found", userID)
+		}
+		return db.TotpSecret{}, fmt.Errorf("failed to get totp secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// SaveSecret stores a new unconfirmed secret, replacing any pending one.
+func (r *twoFactorRepository) SaveSecret(ctx context.Context, userID int, secret string) (db.TotpSecret, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	result, err := r.GetQueries().UpsertTotpSecret(ctx, db.UpsertTotpSecretParams{
+		UserID:    int32(userID),
+		Secret:    secret,
+		CreatedAt: sql.NullTime{Time: now, Valid: true},
+		UpdatedAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return db.TotpSecret{}, fmt.Errorf("failed to save totp secret: %w", err)
+	}
+
+	return result, nil
+}
+
+// Confirm enables the pending secret and replaces the user's recovery codes in
+// one transaction. It reports false if the secret was already confirmed.
+func (r *twoFactorRepository) Confirm(ctx context.Context, userID int, recoveryCodeHashes []string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+
+	rows, err := queries.ConfirmTotpSecret(ctx, db.ConfirmTotpSecretParams{
+		UserID:      int32(userID),
+		ConfirmedAt: now,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to confirm totp secret: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	if err := queries.DeleteRecoveryCodes(ctx, int32(userID)); err != nil {
+		return false, fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		err := queries.CreateRecoveryCode(ctx, db.CreateRecoveryCodeParams{
+			UserID:    int32(userID),
+			CodeHash:  codeHash,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to create recovery code: %w", err)
+		}
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// MarkStepUsed records the time step of an accepted code. It reports false when
+// that step or a later one was already used, which means the code is replayed.
+func (r *twoFactorRepository) MarkStepUsed(ctx context.Context, userID int, step int64) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.GetQueries().UpdateTotpLastUsedStep(ctx, db.UpdateTotpLastUsedStepParams{
+		UserID:       int32(userID),
+		LastUsedStep: step,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to update totp step: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// UseRecoveryCode consumes the code and reports false if it is unknown or used.
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.GetQueries().UseRecoveryCode(ctx, db.UseRecoveryCodeParams{
+		UserID:   int32(userID),
+		CodeHash: codeHash,
+		UsedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *twoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID int) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	count, err := r.GetQueries().CountUnusedRecoveryCodes(ctx, int32(userID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// Delete removes the secret and every recovery code of the user.
+func (r *twoFactorRepository) Delete(ctx context.Context, userID int) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+
+	if err := queries.DeleteTotpSecret(ctx, int32(userID)); err != nil {
+		return fmt.Errorf("failed to delete totp secret: %w", err)
+	}
+
+	if err := queries.DeleteRecoveryCodes(ctx, int32(userID)); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+// UsageRecord is a metered operation to add to a user's usage in the billing
+// period starting at PeriodStart. A nil MaxQuantity means the metric is not
+// capped.
+type UsageRecord struct {
+	UserID      int
+	Metric      string
+	Quantity    int
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	MaxQuantity *int64
+}
+
+type UsageRepository interface {
+	Record(ctx context.Context, record UsageRecord) (db.UsageCounter, bool, error)
+	GetCounters(ctx context.Context, userID int, periodStart time.Time) ([]db.UsageCounter, error)
+}
+
+type usageRepository struct {
+	*BaseRepository
+}
+
+func NewUsageRepository(database *sql.DB) UsageRepository {
+	return &usageRepository{
+		BaseRepository: NewBaseRepository(database),
+	}
+}
+
+// Record adds the usage to the period's counter and appends the event in one
+// transaction. The conditional update locks the counter row, so concurrent
+// records of a metric run one after another and cannot overshoot the cap
+// together. It reports false, and records nothing, if the counter would go
+// over MaxQuantity.
+func (r *usageRepository) Record(ctx context.Context, record UsageRecord) (db.UsageCounter, bool, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return db.UsageCounter{}, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.RollbackTransaction(tx)
+
+	queries := r.GetQueries().WithTx(tx)
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+
+	err = queries.EnsureUsageCounter(ctx, db.EnsureUsageCounterParams{
+		UserID:      int32(record.UserID),
+		Metric:      record.Metric,
+		PeriodStart: record.PeriodStart,
+		PeriodEnd:   record.PeriodEnd,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return db.UsageCounter{}, false, fmt.Errorf("failed to create usage counter: %w", err)
+	}
+
+	incrementUsageCounterParams := db.IncrementUsageCounterParams{
+		Quantity:    int64(record.Quantity),
+		UpdatedAt:   now,
+		UserID:      int32(record.UserID),
+		Metric:      record.Metric,
+		PeriodStart: record.PeriodStart,
+	}
+	if record.MaxQuantity != nil {
+		incrementUsageCounterParams.MaxQuantity = sql.NullInt64{Int64: *record.MaxQuantity, Valid: true}
+	}
+
+	counter, err := queries.IncrementUsageCounter(ctx, incrementUsageCounterParams)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.UsageCounter{}, false, nil
+		}
+		return db.UsageCounter{}, false, fmt.Errorf("failed to update usage counter: %w", err)
+	}
+
+	err = queries.CreateUsageEvent(ctx, db.CreateUsageEventParams{
+		UserID:      int32(record.UserID),
+		Metric:      record.Metric,
+		Quantity:    int32(record.Quantity),
+		PeriodStart: record.PeriodStart,
+		CreatedAt:   now,
+	})
+	if err != nil {
+		return db.UsageCounter{}, false, fmt.Errorf("failed to create usage event: %w", err)
+	}
+
+	if err := r.CommitTransaction(tx); err != nil {
+		return db.UsageCounter{}, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return counter, true, nil
+}
+
+// GetCounters returns the user's totals for the period starting at periodStart.
+func (r *usageRepository) GetCounters(ctx context.Context, userID int, periodStart time.Time) ([]db.UsageCounter, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	counters, err := r.GetQueries().ListUsageCounters(ctx, db.ListUsageCountersParams{
+		UserID:      int32(userID),
+		PeriodStart: periodStart,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage counters: %w", err)
+	}
+
+	return counters, nil
+}
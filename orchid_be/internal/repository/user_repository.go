@@ -9,27 +9,42 @@ import (
 	"orchid_be/internal/db"
 )
 
+// UserProfile holds the optional profile fields of a user.
+type UserProfile struct {
+	Avatar    string
+	Biography string
+	Position  string
+	Country   string
+}
+
 type UserRepository interface {
-	Create(ctx context.Context, name, email, passwordHash string) (db.User, error)
+	Create(ctx context.Context, name, email, passwordHash, status string, profile UserProfile) (db.User, error)
 	GetByID(ctx context.Context, id int) (db.User, error)
 	GetByEmail(ctx context.Context, email string) (db.User, error)
-	GetAll(ctx context.Context, limit, offset int) ([]db.User, error)
-	Update(ctx context.Context, id int, name, email, passwordHash string) (db.User, error)
+	GetAll(ctx context.Context, query ListQuery, limit, offset int) ([]db.User, error)
+	Update(ctx context.Context, id int, name, email, passwordHash, status string, profile UserProfile) (db.User, error)
+	UpdatePassword(ctx context.Context, id int, passwordHash string) (db.User, error)
+	VerifyEmail(ctx context.Context, id int, status string) (db.User, error)
+	RecordFailedLogin(ctx context.Context, id int, windowStart time.Time) (int, error)
+	Lock(ctx context.Context, id int, until time.Time) error
+	ResetFailedLogins(ctx context.Context, id int) error
 	Delete(ctx context.Context, id int) error
-	Count(ctx context.Context) (int, error)
+	Count(ctx context.Context, query ListQuery) (int, error)
 }
 
 type userRepository struct {
 	*BaseRepository
+	users CrudRepository[db.User]
 }
 
 func NewUserRepository(database *sql.DB) UserRepository {
 	return &userRepository{
 		BaseRepository: NewBaseRepository(database),
+		users:          NewCrudRepository[db.User](database, "users"),
 	}
 }
 
-func (r *userRepository) Create(ctx context.Context, name, email, passwordHash string) (db.User, error) {
+func (r *userRepository) Create(ctx context.Context, name, email, passwordHash, status string, profile UserProfile) (db.User, error) {
 	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -38,6 +53,11 @@ func (r *userRepository) Create(ctx context.Context, name, email, passwordHash s
 		Name:         name,
 		Email:        email,
 		PasswordHash: passwordHash,
+		Status:       status,
+		Avatar:       profile.Avatar,
+		Biography:    profile.Biography,
+		Position:     profile.Position,
+		Country:      profile.Country,
 		CreatedAt:    sql.NullTime{Time: now, Valid: true},
 		UpdatedAt:    sql.NullTime{Time: now, Valid: true},
 	}
@@ -80,67 +100,141 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (db.User,
 	return dbUser, nil
 }
 
-func (r *userRepository) GetAll(ctx context.Context, limit, offset int) ([]db.User, error) {
+// GetAll returns one page of the users matching query, which is built into
+// SQL by the generic CRUD repository.
+func (r *userRepository) GetAll(ctx context.Context, query ListQuery, limit, offset int) ([]db.User, error) {
+	return r.users.GetAll(ctx, query, limit, offset)
+}
+
+// Update clears email_verified_at when the email changes, since the new
+// address has not been verified yet.
+func (r *userRepository) Update(ctx context.Context, id int, name, email, passwordHash, status string, profile UserProfile) (db.User, error) {
 	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	getAllUsersParams := db.GetAllUsersParams{
-		Limit:  int32(limit),
-		Offset: int32(offset),
+	now := time.Now()
+	updateUserParams := db.UpdateUserParams{
+		ID:           int32(id),
+		Name:         name,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Status:       status,
+		Avatar:       profile.Avatar,
+		Biography:    profile.Biography,
+		Position:     profile.Position,
+		Country:      profile.Country,
+		UpdatedAt:    sql.NullTime{Time: now, Valid: true},
 	}
 
-	dbUsers, err := r.GetQueries().GetAllUsers(ctx, getAllUsersParams)
+	result, err := r.GetQueries().UpdateUser(ctx, updateUserParams)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get users: %w", err)
+		if err == sql.ErrNoRows {
+			return db.User{}, fmt.Errorf("user with id %d not found", id)
+		}
+		return db.User{}, fmt.Errorf("failed to update user: %w", err)
 	}
 
-	return dbUsers, nil
+	return result, nil
 }
 
-func (r *userRepository) Update(ctx context.Context, id int, name, email, passwordHash string) (db.User, error) {
+func (r *userRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) (db.User, error) {
 	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	now := time.Now()
-	updateUserParams := db.UpdateUserParams{
+	updateUserPasswordParams := db.UpdateUserPasswordParams{
 		ID:           int32(id),
-		Name:         name,
-		Email:        email,
 		PasswordHash: passwordHash,
-		UpdatedAt:    sql.NullTime{Time: now, Valid: true},
+		UpdatedAt:    sql.NullTime{Time: time.Now(), Valid: true},
 	}
 
-	result, err := r.GetQueries().UpdateUser(ctx, updateUserParams)
+	result, err := r.GetQueries().UpdateUserPassword(ctx, updateUserPasswordParams)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return db.User{}, fmt.Errorf("user with id %d not found", id)
 		}
-		return db.User{}, fmt.Errorf("failed to update user: %w", err)
+		return db.User{}, fmt.Errorf("failed to update user password: %w", err)
 	}
 
 	return result, nil
 }
 
-func (r *userRepository) Delete(ctx context.Context, id int) error {
+func (r *userRepository) VerifyEmail(ctx context.Context, id int, status string) (db.User, error) {
 	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	err := r.GetQueries().DeleteUser(ctx, int32(id))
+	verifyUserEmailParams := db.VerifyUserEmailParams{
+		ID:              int32(id),
+		Status:          status,
+		EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	result, err := r.GetQueries().VerifyUserEmail(ctx, verifyUserEmailParams)
 	if err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
+		if err == sql.ErrNoRows {
+			return db.User{}, fmt.Errorf("user with id %d not found", id)
+		}
+		return db.User{}, fmt.Errorf("failed to verify user email: %w", err)
+	}
+
+	return result, nil
+}
+
+// RecordFailedLogin bumps the failed login counter and returns the new value.
+// Failures older than windowStart are forgotten and the count starts over.
+func (r *userRepository) RecordFailedLogin(ctx context.Context, id int, windowStart time.Time) (int, error) {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	attempts, err := r.GetQueries().RecordFailedLogin(ctx, db.RecordFailedLoginParams{
+		WindowStart: sql.NullTime{Time: windowStart, Valid: true},
+		FailedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+		ID:          int32(id),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to record failed login: %w", err)
+	}
+
+	return int(attempts), nil
+}
+
+func (r *userRepository) Lock(ctx context.Context, id int, until time.Time) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := r.GetQueries().LockUser(ctx, db.LockUserParams{
+		ID:          int32(id),
+		LockedUntil: sql.NullTime{Time: until, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
 	}
 
 	return nil
 }
 
-func (r *userRepository) Count(ctx context.Context) (int, error) {
+func (r *userRepository) ResetFailedLogins(ctx context.Context, id int) error {
 	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	count, err := r.GetQueries().CountUsers(ctx)
+	if err := r.GetQueries().ResetFailedLogins(ctx, int32(id)); err != nil {
+		return fmt.Errorf("failed to reset failed logins: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := r.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := r.GetQueries().DeleteUser(ctx, int32(id))
 	if err != nil {
-		return 0, fmt.Errorf("failed to count users: %w", err)
+		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
-	return int(count), nil
+	return nil
+}
+
+func (r *userRepository) Count(ctx context.Context, query ListQuery) (int, error) {
+	return r.users.Count(ctx, query)
 }
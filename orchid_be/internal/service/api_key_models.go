@@ -0,0 +1,57 @@
+package service
+
+import (
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,unique,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type APIKeyResponse struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse is the only response that contains the full key.
+type CreatedAPIKeyResponse struct {
+	*APIKeyResponse
+	Key string `json:"key"`
+}
+
+func ToAPIKeyResponse(key db.ApiKey, scopes []string) *APIKeyResponse {
+	resp := &APIKeyResponse{
+		ID:     int(key.ID),
+		Name:   key.Name,
+		Prefix: key.Prefix,
+		Scopes: scopes,
+	}
+
+	if key.ExpiresAt.Valid {
+		resp.ExpiresAt = &key.ExpiresAt.Time
+	}
+
+	if key.LastUsedAt.Valid {
+		resp.LastUsedAt = &key.LastUsedAt.Time
+	}
+
+	if key.RevokedAt.Valid {
+		resp.RevokedAt = &key.RevokedAt.Time
+	}
+
+	if key.CreatedAt.Valid {
+		resp.CreatedAt = key.CreatedAt.Time
+	}
+
+	return resp
+}
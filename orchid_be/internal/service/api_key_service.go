@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"orchid_be/internal/repository"
+	"orchid_be/internal/utils"
+)
+
+const (
+	// APIKeyPrefix marks bearer tokens that are API keys rather than JWTs
+	APIKeyPrefix = "orc_"
+
+	apiKeyDisplayLength = len(APIKeyPrefix) + 8
+	apiKeyTouchInterval = time.Minute
+)
+
+var (
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrScopeNotGranted     = errors.New("scope is not granted to the user")
+	ErrInvalidAPIKeyExpiry = errors.New("expiry must be in the future")
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, userID int, req *CreateAPIKeyRequest) (*CreatedAPIKeyResponse, error)
+	GetAPIKeys(ctx context.Context, userID int) ([]*APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, userID, id int) error
+	Authenticate(ctx context.Context, key string) (*Principal, error)
+}
+
+type apiKeyService struct {
+	*BaseService
+	apiKeyRepo   repository.APIKeyRepository
+	userRepo     repository.UserRepository
+	roleRepo     repository.RoleRepository
+	usageService UsageService
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, userRepo repository.UserRepository, roleRepo repository.RoleRepository, usageService UsageService) APIKeyService {
+	return &apiKeyService{
+		BaseService:  NewBaseService(),
+		apiKeyRepo:   apiKeyRepo,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		usageService: usageService,
+	}
+}
+
+// IsAPIKey reports whether a bearer token looks like an API key.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, userID int, req *CreateAPIKeyRequest) (*CreatedAPIKeyResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidAPIKeyExpiry
+	}
+
+	limit, capped, err := s.usageService.GetLimit(ctx, userID, UsageMetricAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+	var maxActive *int
+	if capped {
+		maxActive = &limit
+	}
+
+	granted, err := s.roleRepo.GetUserPermissionNames(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A key can never do more than its owner
+	permissionIDs := make([]int, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if !containsString(granted, scope) {
+			return nil, fmt.Errorf("%w: %s", ErrScopeNotGranted, scope)
+		}
+
+		permission, err := s.roleRepo.GetPermissionByName(ctx, scope)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPermission, scope)
+		}
+		permissionIDs = append(permissionIDs, int(permission.ID))
+	}
+
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	key := APIKeyPrefix + secret
+
+	apiKey, created, err := s.apiKeyRepo.Create(ctx, userID, req.Name, key[:apiKeyDisplayLength], utils.HashToken(key), req.ExpiresAt, permissionIDs, maxActive)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, limitExceeded(UsageMetricAPIKeys, limit)
+	}
+
+	scopes, err := s.apiKeyRepo.GetScopes(ctx, int(apiKey.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreatedAPIKeyResponse{
+		APIKeyResponse: ToAPIKeyResponse(apiKey, scopes),
+		Key:            key,
+	}, nil
+}
+
+func (s *apiKeyService) GetAPIKeys(ctx context.Context, userID int) ([]*APIKeyResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	keys, err := s.apiKeyRepo.GetAllForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*APIKeyResponse, len(keys))
+	for i, key := range keys {
+		scopes, err := s.apiKeyRepo.GetScopes(ctx, int(key.ID))
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = ToAPIKeyResponse(key, scopes)
+	}
+
+	return responses, nil
+}
+
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, userID, id int) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	revoked, err := s.apiKeyRepo.Revoke(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// Authenticate resolves an API key to its owner. The principal only holds the
+// key's scopes that the owner still has through their roles.
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (*Principal, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	apiKey, err := s.apiKeyRepo.GetByHash(ctx, utils.HashToken(key))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if apiKey.RevokedAt.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	if apiKey.ExpiresAt.Valid && time.Now().After(apiKey.ExpiresAt.Time) {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, int(apiKey.UserID))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	scopes, err := s.apiKeyRepo.GetScopes(ctx, int(apiKey.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	granted, err := s.roleRepo.GetUserPermissionNames(ctx, int(user.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permissions: %w", err)
+	}
+
+	permissions := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if containsString(granted, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	if err := s.apiKeyRepo.Touch(ctx, int(apiKey.ID), time.Now().Add(-apiKeyTouchInterval)); err != nil {
+		log.Printf("Failed to record use of api key %d: %v", apiKey.ID, err)
+	}
+
+	return &Principal{
+		User:        ToUserResponse(user),
+		Permissions: permissions,
+		APIKeyID:    int(apiKey.ID),
+	}, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
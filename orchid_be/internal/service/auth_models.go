@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"orchid_be/internal/utils"
+)
+
+const (
+	TokenTypeAccess            = "access"
+	TokenTypeEmailVerification = "email_verification"
+	TokenTypeMFAPending        = "mfa_pending"
+	TokenTypeMagicLink         = "magic_link"
+)
+
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type RegisterRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ConsumeMagicLinkRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// ElevateRequest confirms the password, and the two-factor code when it is
+// enabled, before sensitive actions.
+type ElevateRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code,omitempty"`
+}
+
+type ElevationResponse struct {
+	Elevated      bool       `json:"elevated"`
+	ElevatedUntil *time.Time `json:"elevated_until,omitempty"`
+	ExpiresIn     int        `json:"expires_in"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type TokenResponse struct {
+	AccessToken  string        `json:"access_token"`
+	RefreshToken string        `json:"refresh_token"`
+	TokenType    string        `json:"token_type"`
+	ExpiresIn    int           `json:"expires_in"`
+	User         *UserResponse `json:"user"`
+}
+
+// MFAChallengeResponse is returned by login instead of tokens when the account
+// has two-factor authentication enabled.
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// Principal is the authenticated caller of a request. Claims is nil and
+// APIKeyID is set when the caller authenticated with an API key.
+// ElevatedUntil is when the session's last re-authentication stops counting.
+// During an impersonation User and Permissions are those of the impersonated
+// user and Impersonator is the staff member really making the request.
+type Principal struct {
+	User            *UserResponse
+	Claims          *utils.Claims
+	Permissions     []string
+	APIKeyID        int
+	ElevatedUntil   time.Time
+	Impersonator    *UserResponse
+	ImpersonationID int
+}
+
+func (p *Principal) HasPermission(permission string) bool {
+	return containsString(p.Permissions, permission)
+}
+
+func (p *Principal) IsAPIKey() bool {
+	return p.APIKeyID != 0
+}
+
+func (p *Principal) IsImpersonated() bool {
+	return p.ImpersonationID != 0
+}
+
+// SessionID is the session the access token was issued for, or 0.
+func (p *Principal) SessionID() int {
+	if p.Claims == nil {
+		return 0
+	}
+	return p.Claims.SessionID
+}
+
+// IsElevated reports whether the session re-authenticated recently enough for
+// sensitive actions. API keys are never elevated.
+func (p *Principal) IsElevated() bool {
+	return p.SessionID() != 0 && time.Now().Before(p.ElevatedUntil)
+}
+
+// Elevation describes the elevation state of the principal's session.
+func (p *Principal) Elevation() *ElevationResponse {
+	if !p.IsElevated() {
+		return &ElevationResponse{}
+	}
+	return ToElevationResponse(p.ElevatedUntil)
+}
+
+func ToElevationResponse(until time.Time) *ElevationResponse {
+	return &ElevationResponse{
+		Elevated:      true,
+		ElevatedUntil: &until,
+		ExpiresIn:     int(time.Until(until).Seconds()),
+	}
+}
+
+type principalContextKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
@@ -0,0 +1,550 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"orchid_be/internal/config"
+	"orchid_be/internal/db"
+	"orchid_be/internal/repository"
+	"orchid_be/internal/utils"
+)
+
+const (
+	sessionTouchInterval = time.Minute
+	maxUserAgentLength   = 512
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected, all sessions of this login were revoked")
+	ErrEmailNotVerified   = errors.New("email address has not been verified")
+	ErrAccountDisabled    = errors.New("account is disabled")
+
+	ErrReauthenticationRequired = errors.New("confirm your password to continue")
+	ErrTwoFactorCodeRequired    = errors.New("two-factor authentication code required")
+)
+
+type AuthService interface {
+	Login(ctx context.Context, req *LoginRequest, client ClientInfo) (*TokenResponse, *MFAChallengeResponse, error)
+	VerifyMFA(ctx context.Context, req *VerifyMFARequest, client ClientInfo) (*TokenResponse, error)
+	CompleteLogin(ctx context.Context, userID int, client ClientInfo) (*TokenResponse, *MFAChallengeResponse, error)
+	Refresh(ctx context.Context, req *RefreshTokenRequest, client ClientInfo) (*TokenResponse, error)
+	Logout(ctx context.Context, req *RefreshTokenRequest) error
+	LogoutAll(ctx context.Context, userID int) error
+	Elevate(ctx context.Context, userID, sessionID int, req *ElevateRequest) (*ElevationResponse, error)
+	DropElevation(ctx context.Context, userID, sessionID int) error
+	Authenticate(ctx context.Context, accessToken string) (*Principal, error)
+}
+
+type authService struct {
+	*BaseService
+	userRepo          repository.UserRepository
+	refreshTokenRepo  repository.RefreshTokenRepository
+	sessionRepo       repository.SessionRepository
+	impersonationRepo repository.ImpersonationRepository
+	roleRepo          repository.RoleRepository
+	twoFactorService  TwoFactorService
+	jwtManager        *utils.JWTManager
+	jwtConfig         config.JWTConfig
+	authConfig        config.AuthConfig
+	loginProtection   *loginProtection
+}
+
+func NewAuthService(userRepo repository.UserRepository, refreshTokenRepo repository.RefreshTokenRepository, sessionRepo repository.SessionRepository, impersonationRepo repository.ImpersonationRepository, roleRepo repository.RoleRepository, twoFactorService TwoFactorService, jwtManager *utils.JWTManager, jwtConfig config.JWTConfig, authConfig config.AuthConfig, loginProtectionConfig config.LoginProtectionConfig) AuthService {
+	return &authService{
+		BaseService:       NewBaseService(),
+		userRepo:          userRepo,
+		refreshTokenRepo:  refreshTokenRepo,
+		sessionRepo:       sessionRepo,
+		impersonationRepo: impersonationRepo,
+		roleRepo:          roleRepo,
+		twoFactorService:  twoFactorService,
+		jwtManager:        jwtManager,
+		jwtConfig:         jwtConfig,
+		authConfig:        authConfig,
+		loginProtection:   newLoginProtection(userRepo, loginProtectionConfig),
+	}
+}
+
+// Login checks the password and either issues tokens or, when two-factor
+// authentication is enabled, a short-lived challenge for VerifyMFA.
+func (s *authService) Login(ctx context.Context, req *LoginRequest, client ClientInfo) (*TokenResponse, *MFAChallengeResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		// Do not reveal whether the email exists
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	// Refuse before checking the password so a locked account cannot be probed
+	if err := s.loginProtection.check(user); err != nil {
+		return nil, nil, err
+	}
+
+	if err := utils.CheckPassword(req.Password, user.PasswordHash); err != nil {
+		s.loginProtection.recordFailure(ctx, user)
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	if err := checkUserStatus(user); err != nil {
+		return nil, nil, err
+	}
+
+	return s.completeLogin(ctx, user, client)
+}
+
+// CompleteLogin finishes a login for a user whose identity was already proven
+// without a password, for example by an OpenID Connect provider. Lockout and
+// two-factor authentication apply just like for a password login.
+func (s *authService) CompleteLogin(ctx context.Context, userID int, client ClientInfo) (*TokenResponse, *MFAChallengeResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, ErrUserNotFound
+	}
+
+	if err := s.loginProtection.check(user); err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkUserStatus(user); err != nil {
+		return nil, nil, err
+	}
+
+	return s.completeLogin(ctx, user, client)
+}
+
+func (s *authService) VerifyMFA(ctx context.Context, req *VerifyMFARequest, client ClientInfo) (*TokenResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	claims, err := s.jwtManager.Parse(req.MFAToken)
+	if err != nil || claims.TokenType != TokenTypeMFAPending {
+		return nil, ErrInvalidToken
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.loginProtection.check(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorService.VerifyCode(ctx, userID, req.Code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			s.loginProtection.recordFailure(ctx, user)
+		}
+		return nil, err
+	}
+
+	if err := s.loginProtection.recordSuccess(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, client)
+}
+
+func (s *authService) Refresh(ctx context.Context, req *RefreshTokenRequest, client ClientInfo) (*TokenResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	current, err := s.refreshTokenRepo.GetByHash(ctx, utils.HashToken(req.RefreshToken))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if current.RevokedAt.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	// A rotated token should never be presented again; treat it as stolen.
+	if current.RotatedAt.Valid {
+		return nil, s.revokeReusedFamily(ctx, current.FamilyID)
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, int(current.UserID))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	next, err := s.refreshTokenRepo.Rotate(ctx, current, utils.HashToken(refreshToken), time.Now().Add(s.jwtConfig.RefreshTokenTTL))
+	if err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenAlreadyRotated) {
+			return nil, s.revokeReusedFamily(ctx, current.FamilyID)
+		}
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	session, err := s.sessionRepo.Refresh(ctx, current.FamilyID, truncate(client.UserAgent, maxUserAgentLength), client.IPAddress, next.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh session: %w", err)
+	}
+
+	return s.buildTokenResponse(user, refreshToken, int(session.ID))
+}
+
+func (s *authService) Logout(ctx context.Context, req *RefreshTokenRequest) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	current, err := s.refreshTokenRepo.GetByHash(ctx, utils.HashToken(req.RefreshToken))
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, current.FamilyID); err != nil {
+		return fmt.Errorf("failed to log out: %w", err)
+	}
+
+	return nil
+}
+
+func (s *authService) LogoutAll(ctx context.Context, userID int) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to log out all sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (s *authService) Authenticate(ctx context.Context, accessToken string) (*Principal, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	claims, err := s.jwtManager.Parse(accessToken)
+	if err != nil || claims.TokenType != TokenTypeAccess {
+		return nil, ErrInvalidToken
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	// A revoked session takes its access tokens with it
+	var elevatedUntil time.Time
+	if claims.SessionID != 0 {
+		session, err := s.sessionRepo.GetByID(ctx, claims.SessionID)
+		if err != nil || session.RevokedAt.Valid || int(session.UserID) != userID {
+			return nil, ErrInvalidToken
+		}
+
+		if err := s.sessionRepo.Touch(ctx, claims.SessionID, time.Now().Add(-sessionTouchInterval)); err != nil {
+			log.Printf("Failed to record activity of session %d: %v", claims.SessionID, err)
+		}
+
+		if session.ElevatedUntil.Valid {
+			elevatedUntil = session.ElevatedUntil.Time
+		}
+	}
+
+	// Likewise an ended impersonation takes its token with it
+	var impersonator *UserResponse
+	if claims.ImpersonationID != 0 {
+		impersonator, err = s.checkImpersonation(ctx, claims, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Permissions are looked up on every request so role changes apply at once
+	permissions, err := s.roleRepo.GetUserPermissionNames(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permissions: %w", err)
+	}
+
+	return &Principal{
+		User:            ToUserResponse(user),
+		Claims:          claims,
+		Permissions:     permissions,
+		ElevatedUntil:   elevatedUntil,
+		Impersonator:    impersonator,
+		ImpersonationID: claims.ImpersonationID,
+	}, nil
+}
+
+// checkImpersonation returns the staff member behind an impersonation token.
+// The token stops working when the impersonation ends or expires, or when the
+// staff member loses the users:impersonate permission or their account.
+func (s *authService) checkImpersonation(ctx context.Context, claims *utils.Claims, subjectID int) (*UserResponse, error) {
+	if claims.Actor == nil {
+		return nil, ErrInvalidToken
+	}
+
+	actorID, err := strconv.Atoi(claims.Actor.Subject)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	impersonation, err := s.impersonationRepo.GetByID(ctx, claims.ImpersonationID)
+	if err != nil || impersonation.EndedAt.Valid || time.Now().After(impersonation.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	if int(impersonation.ActorID) != actorID || int(impersonation.SubjectID) != subjectID {
+		return nil, ErrInvalidToken
+	}
+
+	actor, err := s.userRepo.GetByID(ctx, actorID)
+	if err != nil || checkUserStatus(actor) != nil {
+		return nil, ErrInvalidToken
+	}
+
+	permissions, err := s.roleRepo.GetUserPermissionNames(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permissions: %w", err)
+	}
+	if !containsString(permissions, PermissionUsersImpersonate) {
+		return nil, ErrInvalidToken
+	}
+
+	return ToUserResponse(actor), nil
+}
+
+// Elevate re-checks the password, and the two-factor code when enabled, and
+// opens a short window in which the session may perform sensitive actions.
+// Failures count towards the account lockout like failed logins.
+func (s *authService) Elevate(ctx context.Context, userID, sessionID int, req *ElevateRequest) (*ElevationResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if sessionID == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := s.loginProtection.check(user); err != nil {
+		return nil, err
+	}
+
+	if err := utils.CheckPassword(req.Password, user.PasswordHash); err != nil {
+		s.loginProtection.recordFailure(ctx, user)
+		return nil, ErrIncorrectPassword
+	}
+
+	mfaEnabled, err := s.twoFactorService.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfaEnabled {
+		if req.Code == "" {
+			return nil, ErrTwoFactorCodeRequired
+		}
+		if err := s.twoFactorService.VerifyCode(ctx, userID, req.Code); err != nil {
+			if errors.Is(err, ErrInvalidTwoFactorCode) {
+				s.loginProtection.recordFailure(ctx, user)
+			}
+			return nil, err
+		}
+	}
+
+	if err := s.loginProtection.recordSuccess(ctx, user); err != nil {
+		return nil, err
+	}
+
+	until := time.Now().Add(s.authConfig.ElevationTTL)
+	ok, err := s.sessionRepo.SetElevation(ctx, sessionID, userID, &until)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	return ToElevationResponse(until), nil
+}
+
+// DropElevation ends the elevation window of the session early.
+func (s *authService) DropElevation(ctx context.Context, userID, sessionID int) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if sessionID == 0 {
+		return ErrInvalidToken
+	}
+
+	ok, err := s.sessionRepo.SetElevation(ctx, sessionID, userID, nil)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+// checkUserStatus only lets active accounts hold tokens.
+func checkUserStatus(user db.User) error {
+	switch user.Status {
+	case UserStatusActive:
+		return nil
+	case UserStatusPending:
+		return ErrEmailNotVerified
+	default:
+		return ErrAccountDisabled
+	}
+}
+
+func (s *authService) revokeReusedFamily(ctx context.Context, familyID string) error {
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return ErrRefreshTokenReused
+}
+
+// completeLogin runs after the first factor succeeded and either asks for the
+// second factor or issues tokens.
+func (s *authService) completeLogin(ctx context.Context, user db.User, client ClientInfo) (*TokenResponse, *MFAChallengeResponse, error) {
+	mfaEnabled, err := s.twoFactorService.IsEnabled(ctx, int(user.ID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if mfaEnabled {
+		challenge, err := s.issueMFAChallenge(user)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, challenge, nil
+	}
+
+	if err := s.loginProtection.recordSuccess(ctx, user); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.issueTokens(ctx, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tokens, nil, nil
+}
+
+// issueTokens starts a new session and refresh token family for a fresh login.
+func (s *authService) issueTokens(ctx context.Context, user db.User, client ClientInfo) (*TokenResponse, error) {
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	familyID, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token family: %w", err)
+	}
+
+	userAgent := truncate(client.UserAgent, maxUserAgentLength)
+	session, err := s.sessionRepo.Create(ctx, int(user.ID), familyID, utils.HashToken(refreshToken), utils.DescribeUserAgent(userAgent), userAgent, client.IPAddress, time.Now().Add(s.jwtConfig.RefreshTokenTTL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	return s.buildTokenResponse(user, refreshToken, int(session.ID))
+}
+
+// issueMFAChallenge signs a token that only proves the password step passed.
+func (s *authService) issueMFAChallenge(user db.User) (*MFAChallengeResponse, error) {
+	now := time.Now()
+	mfaToken, err := s.jwtManager.Sign(&utils.Claims{
+		Subject:   strconv.Itoa(int(user.ID)),
+		TokenType: TokenTypeMFAPending,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.authConfig.MFATokenTTL).Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue mfa token: %w", err)
+	}
+
+	return &MFAChallengeResponse{
+		MFARequired: true,
+		MFAToken:    mfaToken,
+		ExpiresIn:   int(s.authConfig.MFATokenTTL.Seconds()),
+	}, nil
+}
+
+func (s *authService) buildTokenResponse(user db.User, refreshToken string, sessionID int) (*TokenResponse, error) {
+	now := time.Now()
+	accessToken, err := s.jwtManager.Sign(&utils.Claims{
+		Subject:   strconv.Itoa(int(user.ID)),
+		Email:     user.Email,
+		TokenType: TokenTypeAccess,
+		SessionID: sessionID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.jwtConfig.AccessTokenTTL).Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue access token: %w", err)
+	}
+
+	return &TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.jwtConfig.AccessTokenTTL.Seconds()),
+		User:         ToUserResponse(user),
+	}, nil
+}
+
+// truncate cuts client supplied strings to the column size without splitting
+// a multi-byte character.
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	for max > 0 && !utf8.RuneStart(value[max]) {
+		max--
+	}
+	return value[:max]
+}
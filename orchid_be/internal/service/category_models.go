@@ -0,0 +1,59 @@
+package service
+
+import (
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type CreateCategoryRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type UpdateCategoryRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type CategoryResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CategoryEntity serves /api/categories through the generic CRUD stack. Names
+// are unique regardless of case and a category that products use cannot be
+// deleted; both are enforced by the database.
+var CategoryEntity = Entity[db.Category, *CategoryResponse]{
+	EntityConfig: EntityConfig{
+		Name:        "categories",
+		DisplayName: "Category",
+		Filters:     []string{"name", "created_at", "updated_at"},
+		Sorts:       []string{"id", "name", "created_at", "updated_at"},
+		Search:      []string{"name"},
+		DefaultSort: "name",
+		Permissions: EntityPermissions{
+			Create: PermissionCategoriesManage,
+			Update: PermissionCategoriesManage,
+			Delete: PermissionCategoriesManage,
+		},
+	},
+	ToResponse: ToCategoryResponse,
+}
+
+func ToCategoryResponse(category db.Category) *CategoryResponse {
+	resp := &CategoryResponse{
+		ID:   int(category.ID),
+		Name: category.Name,
+	}
+
+	if category.CreatedAt.Valid {
+		resp.CreatedAt = category.CreatedAt.Time
+	}
+
+	if category.UpdatedAt.Valid {
+		resp.UpdatedAt = category.UpdatedAt.Time
+	}
+
+	return resp
+}
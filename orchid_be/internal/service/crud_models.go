@@ -0,0 +1,55 @@
+package service
+
+// EntityConfig describes a resource served by the generic CRUD stack, like
+// the EntityConfig the frontend renders its CRUD pages from.
+type EntityConfig struct {
+	// Name is the path of the resource under /api, e.g. "categories".
+	Name string
+	// DisplayName names one record in messages, e.g. "Category".
+	DisplayName string
+	// Filters are the columns a list can be filtered on with ?column=value
+	// or ?column[op]=value.
+	Filters []string
+	// Sorts are the columns a list can be ordered by with ?sort=column.
+	Sorts []string
+	// Search are the text columns the ?q= search matches.
+	Search []string
+	// DefaultSort orders lists without ?sort; empty means by id. DefaultDesc
+	// reverses it.
+	DefaultSort string
+	DefaultDesc bool
+	Permissions EntityPermissions
+}
+
+// EntityPermissions guards the routes of an entity. An empty permission lets
+// every signed-in user through.
+type EntityPermissions struct {
+	Read   string
+	Create string
+	Update string
+	Delete string
+}
+
+// Entity declares a resource backed by the table whose sqlc model is T and
+// returned to clients as R.
+type Entity[T, R any] struct {
+	EntityConfig
+	ToResponse func(T) R
+}
+
+// ListRequest asks for one page of an entity.
+//
+// Sort is a comma separated list of columns, each descending when prefixed
+// with "-", e.g. "-name,email". Order "desc" reverses the columns without a
+// prefix. Filters may hold any query parameter: "status" matches a column
+// exactly and "created_at[gte]" compares with an operator. Only the
+// entity's filter columns are applied. Search is matched against its search
+// columns.
+type ListRequest struct {
+	Page    int
+	Limit   int
+	Sort    string
+	Order   string
+	Search  string
+	Filters map[string]string
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"orchid_be/internal/repository"
+	"orchid_be/internal/utils"
+)
+
+var (
+	ErrEntityNotFound   = errors.New("not found")
+	ErrEntityExists     = errors.New("already exists")
+	ErrEntityInUse      = errors.New("is still in use")
+	ErrInvalidEntity    = errors.New("invalid request")
+	ErrInvalidListQuery = errors.New("invalid list query")
+)
+
+// CrudService lists, reads and writes the records of one entity. C and U
+// are the create and update request bodies: each field is written to the
+// column named by its json tag, and nil pointer fields are left unchanged.
+type CrudService[C, U, R any] interface {
+	Config() EntityConfig
+	Meta() EntityMeta
+	List(ctx context.Context, req ListRequest) ([]R, int, error)
+	Get(ctx context.Context, id int) (R, error)
+	Create(ctx context.Context, req *C) (R, error)
+	Update(ctx context.Context, id int, req *U) (R, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type crudService[C, U, T, R any] struct {
+	*BaseService
+	entity   Entity[T, R]
+	crudRepo repository.CrudRepository[T]
+}
+
+// NewCrudService serves the entity from crudRepo. The request types have to
+// be given explicitly, e.g. NewCrudService[CreateCategoryRequest,
+// UpdateCategoryRequest](CategoryEntity, repo).
+func NewCrudService[C, U, T, R any](entity Entity[T, R], crudRepo repository.CrudRepository[T]) CrudService[C, U, R] {
+	return &crudService[C, U, T, R]{
+		BaseService: NewBaseService(),
+		entity:      entity,
+		crudRepo:    crudRepo,
+	}
+}
+
+func (s *crudService[C, U, T, R]) Config() EntityConfig {
+	return s.entity.EntityConfig
+}
+
+func (s *crudService[C, U, T, R]) Meta() EntityMeta {
+	return DescribeEntity[C, U, R](s.entity.EntityConfig)
+}
+
+func (s *crudService[C, U, T, R]) List(ctx context.Context, req ListRequest) ([]R, int, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	page, limit := req.Page, req.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	query, err := buildListQuery(s.entity.EntityConfig, req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, err := s.crudRepo.GetAll(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, s.entityError(err)
+	}
+
+	total, err := s.crudRepo.Count(ctx, query)
+	if err != nil {
+		return nil, 0, s.entityError(err)
+	}
+
+	responses := make([]R, len(items))
+	for i, item := range items {
+		responses[i] = s.entity.ToResponse(item)
+	}
+
+	return responses, total, nil
+}
+
+func (s *crudService[C, U, T, R]) Get(ctx context.Context, id int) (R, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	item, err := s.crudRepo.GetByID(ctx, id)
+	if err != nil {
+		var zero R
+		return zero, s.entityError(err)
+	}
+
+	return s.entity.ToResponse(item), nil
+}
+
+func (s *crudService[C, U, T, R]) Create(ctx context.Context, req *C) (R, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	item, err := s.crudRepo.Create(ctx, s.values(req))
+	if err != nil {
+		var zero R
+		return zero, s.entityError(err)
+	}
+
+	return s.entity.ToResponse(item), nil
+}
+
+func (s *crudService[C, U, T, R]) Update(ctx context.Context, id int, req *U) (R, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	item, err := s.crudRepo.Update(ctx, id, s.values(req))
+	if err != nil {
+		var zero R
+		return zero, s.entityError(err)
+	}
+
+	return s.entity.ToResponse(item), nil
+}
+
+// Delete refuses to remove a record that other records still reference.
+func (s *crudService[C, U, T, R]) Delete(ctx context.Context, id int) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	deleted, err := s.crudRepo.Delete(ctx, id)
+	if err != nil {
+		return s.entityError(err)
+	}
+	if !deleted {
+		return s.entityError(repository.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// buildListQuery keeps the filters, search and sort columns the entity
+// allows, so clients can never name other columns. Query parameters that are
+// not filter columns, such as page, are ignored; a bracketed filter on any
+// other column is an error.
+func buildListQuery(config EntityConfig, req ListRequest) (repository.ListQuery, error) {
+	query := repository.ListQuery{
+		Filters: []repository.Filter{},
+		Sorts:   []repository.Sort{},
+	}
+
+	for key, value := range req.Filters {
+		column, operator := key, repository.FilterEqual
+		if open := strings.IndexByte(key, '['); open > 0 && strings.HasSuffix(key, "]") {
+			column, operator = key[:open], key[open+1:len(key)-1]
+			if !containsString(config.Filters, column) {
+				return repository.ListQuery{}, fmt.Errorf("%w: cannot filter by %q", ErrInvalidListQuery, column)
+			}
+			if !repository.IsFilterOperator(operator) {
+				return repository.ListQuery{}, fmt.Errorf("%w: unknown filter operator %q", ErrInvalidListQuery, operator)
+			}
+		} else if !containsString(config.Filters, column) {
+			continue
+		}
+
+		query.Filters = append(query.Filters, repository.Filter{Column: column, Operator: operator, Value: value})
+	}
+
+	// Map order is random; sorting keeps the statement the same for the
+	// same filters.
+	sort.Slice(query.Filters, func(i, j int) bool {
+		if query.Filters[i].Column != query.Filters[j].Column {
+			return query.Filters[i].Column < query.Filters[j].Column
+		}
+		return query.Filters[i].Operator < query.Filters[j].Operator
+	})
+
+	if search := strings.TrimSpace(req.Search); search != "" {
+		if len(config.Search) == 0 {
+			return repository.ListQuery{}, fmt.Errorf("%w: %s cannot be searched", ErrInvalidListQuery, config.Name)
+		}
+		query.Search = search
+		query.SearchColumns = config.Search
+	}
+
+	var desc bool
+	switch strings.ToLower(req.Order) {
+	case "", "asc":
+	case "desc":
+		desc = true
+	default:
+		return repository.ListQuery{}, fmt.Errorf("%w: order must be asc or desc", ErrInvalidListQuery)
+	}
+
+	if req.Sort == "" {
+		if config.DefaultSort != "" {
+			if req.Order == "" {
+				desc = config.DefaultDesc
+			}
+			query.Sorts = append(query.Sorts, repository.Sort{Column: config.DefaultSort, Desc: desc})
+		}
+		return query, nil
+	}
+
+	for _, column := range strings.Split(req.Sort, ",") {
+		column = strings.TrimSpace(column)
+		columnDesc := desc
+		if strings.HasPrefix(column, "-") {
+			column, columnDesc = column[1:], true
+		}
+
+		if !containsString(config.Sorts, column) {
+			return repository.ListQuery{}, fmt.Errorf("%w: cannot sort by %q", ErrInvalidListQuery, column)
+		}
+		query.Sorts = append(query.Sorts, repository.Sort{Column: column, Desc: columnDesc})
+	}
+
+	return query, nil
+}
+
+// values maps the fields of a request to the columns named by their json
+// tags. Fields without a column and nil pointers are left out; strings are
+// trimmed.
+func (s *crudService[C, U, T, R]) values(req any) map[string]any {
+	columns := s.crudRepo.Columns()
+	request := reflect.Indirect(reflect.ValueOf(req))
+
+	values := make(map[string]any)
+	for i := 0; i < request.NumField(); i++ {
+		name := utils.JSONName(request.Type().Field(i))
+		if name == "" || name == "id" || !containsString(columns, name) {
+			continue
+		}
+
+		field := request.Field(i)
+		if field.Kind() == reflect.Pointer {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+
+		if field.Kind() == reflect.String {
+			values[name] = strings.TrimSpace(field.String())
+		} else {
+			values[name] = field.Interface()
+		}
+	}
+
+	return values
+}
+
+// entityError names the entity in the repository's errors, e.g. "category
+// not found".
+func (s *crudService[C, U, T, R]) entityError(err error) error {
+	name := strings.ToLower(s.entity.DisplayName)
+
+	switch {
+	case errors.Is(err, repository.ErrRecordNotFound):
+		return fmt.Errorf("%s %w", name, ErrEntityNotFound)
+	case errors.Is(err, repository.ErrDuplicateRecord):
+		return fmt.Errorf("%s %w", name, ErrEntityExists)
+	case errors.Is(err, repository.ErrRecordInUse):
+		return fmt.Errorf("%s %w", name, ErrEntityInUse)
+	case errors.Is(err, repository.ErrInvalidValue):
+		return fmt.Errorf("%w: %v", ErrInvalidEntity, err)
+	}
+	return err
+}
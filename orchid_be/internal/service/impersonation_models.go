@@ -0,0 +1,85 @@
+package service
+
+import (
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type StartImpersonationRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// ImpersonationTokenResponse holds the access token to act as the subject.
+// There is no refresh token; a new impersonation must be started once it
+// expires.
+type ImpersonationTokenResponse struct {
+	AccessToken   string                 `json:"access_token"`
+	TokenType     string                 `json:"token_type"`
+	ExpiresIn     int                    `json:"expires_in"`
+	User          *UserResponse          `json:"user"`
+	Impersonation *ImpersonationResponse `json:"impersonation"`
+}
+
+type ImpersonationResponse struct {
+	ID        int        `json:"id"`
+	ActorID   int        `json:"actor_id"`
+	SubjectID int        `json:"subject_id"`
+	Reason    string     `json:"reason"`
+	IPAddress string     `json:"ip_address"`
+	Active    bool       `json:"active"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ImpersonationEventResponse is one entry of the audit trail. Method, path and
+// status code are only set for write requests.
+type ImpersonationEventResponse struct {
+	ID         int       `json:"id"`
+	Action     string    `json:"action"`
+	Method     string    `json:"method,omitempty"`
+	Path       string    `json:"path,omitempty"`
+	StatusCode int       `json:"status_code,omitempty"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func ToImpersonationResponse(impersonation db.Impersonation) *ImpersonationResponse {
+	resp := &ImpersonationResponse{
+		ID:        int(impersonation.ID),
+		ActorID:   int(impersonation.ActorID),
+		SubjectID: int(impersonation.SubjectID),
+		Reason:    impersonation.Reason,
+		IPAddress: impersonation.IpAddress,
+		Active:    !impersonation.EndedAt.Valid && time.Now().Before(impersonation.ExpiresAt),
+		ExpiresAt: impersonation.ExpiresAt,
+	}
+
+	if impersonation.EndedAt.Valid {
+		resp.EndedAt = &impersonation.EndedAt.Time
+	}
+
+	if impersonation.CreatedAt.Valid {
+		resp.CreatedAt = impersonation.CreatedAt.Time
+	}
+
+	return resp
+}
+
+func ToImpersonationEventResponse(event db.ImpersonationEvent) *ImpersonationEventResponse {
+	resp := &ImpersonationEventResponse{
+		ID:         int(event.ID),
+		Action:     event.Action,
+		Method:     event.Method,
+		Path:       event.Path,
+		StatusCode: int(event.StatusCode),
+		IPAddress:  event.IpAddress,
+	}
+
+	if event.CreatedAt.Valid {
+		resp.CreatedAt = event.CreatedAt.Time
+	}
+
+	return resp
+}
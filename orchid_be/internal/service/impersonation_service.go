@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"orchid_be/internal/config"
+	"orchid_be/internal/repository"
+	"orchid_be/internal/utils"
+)
+
+const (
+	maxImpersonationReasonLength = 255
+	maxAuditPathLength           = 255
+)
+
+var (
+	ErrImpersonationNotFound       = errors.New("impersonation not found")
+	ErrImpersonationReasonRequired = errors.New("a reason is required to impersonate a user")
+	ErrCannotImpersonateSelf       = errors.New("you cannot impersonate yourself")
+	ErrImpersonationNotAllowed     = errors.New("the user holds permissions you do not have")
+	ErrNotImpersonating            = errors.New("not impersonating a user")
+)
+
+type ImpersonationService interface {
+	Start(ctx context.Context, actor *Principal, subjectID int, req *StartImpersonationRequest, client ClientInfo) (*ImpersonationTokenResponse, error)
+	Stop(ctx context.Context, principal *Principal, client ClientInfo) error
+	GetCurrent(ctx context.Context, principal *Principal) (*ImpersonationResponse, error)
+	GetImpersonations(ctx context.Context, page, limit int) ([]*ImpersonationResponse, int, error)
+	GetEvents(ctx context.Context, impersonationID int) ([]*ImpersonationEventResponse, error)
+	RecordWrite(ctx context.Context, principal *Principal, method, path string, statusCode int, client ClientInfo) error
+}
+
+type impersonationService struct {
+	*BaseService
+	impersonationRepo repository.ImpersonationRepository
+	userRepo          repository.UserRepository
+	roleRepo          repository.RoleRepository
+	jwtManager        *utils.JWTManager
+	authConfig        config.AuthConfig
+}
+
+func NewImpersonationService(impersonationRepo repository.ImpersonationRepository, userRepo repository.UserRepository, roleRepo repository.RoleRepository, jwtManager *utils.JWTManager, authConfig config.AuthConfig) ImpersonationService {
+	return &impersonationService{
+		BaseService:       NewBaseService(),
+		impersonationRepo: impersonationRepo,
+		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		jwtManager:        jwtManager,
+		authConfig:        authConfig,
+	}
+}
+
+// Start lets the actor act as the subject for auth.impersonation_ttl. The
+// subject may not hold any permission the actor lacks, so impersonation can
+// never widen what the actor is able to do.
+func (s *impersonationService) Start(ctx context.Context, actor *Principal, subjectID int, req *StartImpersonationRequest, client ClientInfo) (*ImpersonationTokenResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if actor.IsImpersonated() {
+		return nil, ErrImpersonationNotAllowed
+	}
+
+	if subjectID == actor.User.ID {
+		return nil, ErrCannotImpersonateSelf
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrImpersonationReasonRequired
+	}
+
+	subject, err := s.userRepo.GetByID(ctx, subjectID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := checkUserStatus(subject); err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.roleRepo.GetUserPermissionNames(ctx, subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permissions: %w", err)
+	}
+	for _, permission := range permissions {
+		if !actor.HasPermission(permission) {
+			return nil, ErrImpersonationNotAllowed
+		}
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.authConfig.ImpersonationTTL)
+
+	impersonation, err := s.impersonationRepo.Start(ctx, actor.User.ID, subjectID, truncate(reason, maxImpersonationReasonLength), client.IPAddress, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.jwtManager.Sign(&utils.Claims{
+		Subject:         strconv.Itoa(subjectID),
+		Email:           subject.Email,
+		TokenType:       TokenTypeAccess,
+		Actor:           &utils.ActorClaim{Subject: strconv.Itoa(actor.User.ID)},
+		ImpersonationID: int(impersonation.ID),
+		IssuedAt:        now.Unix(),
+		ExpiresAt:       expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue access token: %w", err)
+	}
+
+	return &ImpersonationTokenResponse{
+		AccessToken:   accessToken,
+		TokenType:     "Bearer",
+		ExpiresIn:     int(s.authConfig.ImpersonationTTL.Seconds()),
+		User:          ToUserResponse(subject),
+		Impersonation: ToImpersonationResponse(impersonation),
+	}, nil
+}
+
+// Stop ends the impersonation the principal's token was issued for. The token
+// is rejected from the next request on.
+func (s *impersonationService) Stop(ctx context.Context, principal *Principal, client ClientInfo) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if !principal.IsImpersonated() {
+		return ErrNotImpersonating
+	}
+
+	ended, err := s.impersonationRepo.End(ctx, principal.ImpersonationID, client.IPAddress)
+	if err != nil {
+		return err
+	}
+	if !ended {
+		return ErrNotImpersonating
+	}
+
+	return nil
+}
+
+func (s *impersonationService) GetCurrent(ctx context.Context, principal *Principal) (*ImpersonationResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if !principal.IsImpersonated() {
+		return nil, ErrNotImpersonating
+	}
+
+	impersonation, err := s.impersonationRepo.GetByID(ctx, principal.ImpersonationID)
+	if err != nil {
+		return nil, ErrNotImpersonating
+	}
+
+	return ToImpersonationResponse(impersonation), nil
+}
+
+func (s *impersonationService) GetImpersonations(ctx context.Context, page, limit int) ([]*ImpersonationResponse, int, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	impersonations, err := s.impersonationRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.impersonationRepo.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*ImpersonationResponse, len(impersonations))
+	for i, impersonation := range impersonations {
+		responses[i] = ToImpersonationResponse(impersonation)
+	}
+
+	return responses, total, nil
+}
+
+func (s *impersonationService) GetEvents(ctx context.Context, impersonationID int) ([]*ImpersonationEventResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.impersonationRepo.GetByID(ctx, impersonationID); err != nil {
+		return nil, ErrImpersonationNotFound
+	}
+
+	events, err := s.impersonationRepo.GetEvents(ctx, impersonationID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*ImpersonationEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = ToImpersonationEventResponse(event)
+	}
+
+	return responses, nil
+}
+
+// RecordWrite adds a request made with an impersonation token to the audit
+// trail, whether it succeeded or not.
+func (s *impersonationService) RecordWrite(ctx context.Context, principal *Principal, method, path string, statusCode int, client ClientInfo) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if !principal.IsImpersonated() {
+		return ErrNotImpersonating
+	}
+
+	return s.impersonationRepo.RecordEvent(ctx, principal.ImpersonationID, repository.ImpersonationActionWrite, method, truncate(path, maxAuditPathLength), statusCode, client.IPAddress)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+const (
+	StockMovementReceive = "receive"
+	StockMovementSell    = "sell"
+	StockMovementAdjust  = "adjust"
+	StockMovementReturn  = "return"
+)
+
+// Quantity is a positive number of units for receive, sell and return; sell
+// takes the units out of stock. For adjust it is the signed correction, e.g.
+// -2 after a stock count found two units missing. Quantities are stored as
+// 32-bit integers.
+type CreateStockMovementRequest struct {
+	Type     string `json:"type" validate:"required,oneof=receive sell adjust return"`
+	Quantity int    `json:"quantity" validate:"required,min=-2147483647,max=2147483647"`
+	Note     string `json:"note,omitempty" validate:"max=500"`
+}
+
+// A threshold of 0 turns the low-stock warning off.
+type SetLowStockThresholdRequest struct {
+	LowStockThreshold *int `json:"low_stock_threshold" validate:"required,min=0,max=2147483647"`
+}
+
+type StockLevelResponse struct {
+	ProductID         int        `json:"product_id"`
+	ProductName       string     `json:"product_name,omitempty"`
+	OnHand            int        `json:"on_hand"`
+	LowStockThreshold int        `json:"low_stock_threshold"`
+	LowStock          bool       `json:"low_stock"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+type StockMovementResponse struct {
+	ID           int       `json:"id"`
+	ProductID    int       `json:"product_id"`
+	Type         string    `json:"type"`
+	Quantity     int       `json:"quantity"`
+	BalanceAfter int       `json:"balance_after"`
+	Note         string    `json:"note"`
+	CreatedBy    *int      `json:"created_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func ToStockLevelResponse(level db.InventoryLevel) *StockLevelResponse {
+	resp := &StockLevelResponse{
+		ProductID:         int(level.ProductID),
+		OnHand:            int(level.OnHand),
+		LowStockThreshold: int(level.LowStockThreshold),
+		LowStock:          isLowStock(level.OnHand, level.LowStockThreshold),
+	}
+
+	if level.UpdatedAt.Valid {
+		resp.UpdatedAt = &level.UpdatedAt.Time
+	}
+
+	return resp
+}
+
+func ToLowStockResponse(row db.ListLowStockProductsRow) *StockLevelResponse {
+	resp := &StockLevelResponse{
+		ProductID:         int(row.ProductID),
+		ProductName:       row.ProductName,
+		OnHand:            int(row.OnHand),
+		LowStockThreshold: int(row.LowStockThreshold),
+		LowStock:          true,
+	}
+
+	if row.UpdatedAt.Valid {
+		resp.UpdatedAt = &row.UpdatedAt.Time
+	}
+
+	return resp
+}
+
+func ToStockMovementResponse(movement db.StockMovement) *StockMovementResponse {
+	resp := &StockMovementResponse{
+		ID:           int(movement.ID),
+		ProductID:    int(movement.ProductID),
+		Type:         movement.Type,
+		Quantity:     int(movement.Quantity),
+		BalanceAfter: int(movement.BalanceAfter),
+		Note:         movement.Note,
+	}
+
+	if movement.CreatedBy.Valid {
+		createdBy := int(movement.CreatedBy.Int32)
+		resp.CreatedBy = &createdBy
+	}
+
+	if movement.CreatedAt.Valid {
+		resp.CreatedAt = movement.CreatedAt.Time
+	}
+
+	return resp
+}
+
+func isLowStock(onHand, threshold int32) bool {
+	return threshold > 0 && onHand <= threshold
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"orchid_be/internal/db"
+	"orchid_be/internal/repository"
+)
+
+var (
+	ErrInsufficientStock    = errors.New("not enough stock on hand")
+	ErrInvalidStockQuantity = errors.New("quantity must be positive, only adjust movements may be negative")
+)
+
+// maxStockQuantity is the largest quantity or threshold the INTEGER columns
+// of the inventory tables hold.
+const maxStockQuantity = math.MaxInt32
+
+type InventoryService interface {
+	GetStock(ctx context.Context, productID int) (*StockLevelResponse, error)
+	RecordMovement(ctx context.Context, productID int, req *CreateStockMovementRequest) (*StockMovementResponse, error)
+	GetMovements(ctx context.Context, productID, page, limit int) ([]*StockMovementResponse, int, error)
+	SetLowStockThreshold(ctx context.Context, productID int, req *SetLowStockThresholdRequest) (*StockLevelResponse, error)
+	GetLowStock(ctx context.Context, page, limit int) ([]*StockLevelResponse, int, error)
+}
+
+type inventoryService struct {
+	*BaseService
+	inventoryRepo repository.InventoryRepository
+	productRepo   repository.ProductRepository
+}
+
+func NewInventoryService(inventoryRepo repository.InventoryRepository, productRepo repository.ProductRepository) InventoryService {
+	return &inventoryService{
+		BaseService:   NewBaseService(),
+		inventoryRepo: inventoryRepo,
+		productRepo:   productRepo,
+	}
+}
+
+// GetStock returns the on-hand quantity. Products that never had a movement
+// have no inventory row yet and are reported as empty.
+func (s *inventoryService) GetStock(ctx context.Context, productID int) (*StockLevelResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	level, err := s.inventoryRepo.GetLevel(ctx, productID)
+	if err != nil {
+		level = db.InventoryLevel{ProductID: product.ID}
+	}
+
+	stock := ToStockLevelResponse(level)
+	stock.ProductName = product.Name
+	return stock, nil
+}
+
+func (s *inventoryService) RecordMovement(ctx context.Context, productID int, req *CreateStockMovementRequest) (*StockMovementResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if req.Quantity > maxStockQuantity || req.Quantity < -maxStockQuantity {
+		return nil, ErrInvalidStockQuantity
+	}
+
+	delta := req.Quantity
+	switch req.Type {
+	case StockMovementReceive, StockMovementReturn:
+		if req.Quantity < 1 {
+			return nil, ErrInvalidStockQuantity
+		}
+	case StockMovementSell:
+		if req.Quantity < 1 {
+			return nil, ErrInvalidStockQuantity
+		}
+		delta = -req.Quantity
+	case StockMovementAdjust:
+		if req.Quantity == 0 {
+			return nil, ErrInvalidStockQuantity
+		}
+	default:
+		return nil, ErrInvalidStockQuantity
+	}
+
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	movement, recorded, err := s.inventoryRepo.RecordMovement(ctx, productID, req.Type, delta, req.Note, actorID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		return nil, ErrInsufficientStock
+	}
+
+	return ToStockMovementResponse(movement), nil
+}
+
+func (s *inventoryService) GetMovements(ctx context.Context, productID, page, limit int) ([]*StockMovementResponse, int, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, 0, ErrProductNotFound
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	movements, err := s.inventoryRepo.GetMovements(ctx, productID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.inventoryRepo.CountMovements(ctx, productID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	movementResponses := make([]*StockMovementResponse, len(movements))
+	for i, movement := range movements {
+		movementResponses[i] = ToStockMovementResponse(movement)
+	}
+
+	return movementResponses, total, nil
+}
+
+func (s *inventoryService) SetLowStockThreshold(ctx context.Context, productID int, req *SetLowStockThresholdRequest) (*StockLevelResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	level, err := s.inventoryRepo.SetLowStockThreshold(ctx, productID, *req.LowStockThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	stock := ToStockLevelResponse(level)
+	stock.ProductName = product.Name
+	return stock, nil
+}
+
+func (s *inventoryService) GetLowStock(ctx context.Context, page, limit int) ([]*StockLevelResponse, int, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	rows, err := s.inventoryRepo.GetLowStock(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.inventoryRepo.CountLowStock(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stockResponses := make([]*StockLevelResponse, len(rows))
+	for i, row := range rows {
+		stockResponses[i] = ToLowStockResponse(row)
+	}
+
+	return stockResponses, total, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+// Invoice statuses. A draft has no number yet; finalizing it makes it open.
+const (
+	InvoiceStatusDraft = "draft"
+	InvoiceStatusOpen  = "open"
+	InvoiceStatusPaid  = "paid"
+	InvoiceStatusVoid  = "void"
+)
+
+type InvoiceLineItemResponse struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   string `json:"unit_price" example:"99.00"`
+	Amount      string `json:"amount" example:"99.00"`
+}
+
+// InvoiceResponse is an invoice with the customer details and tax rate it
+// was issued with. Amounts are decimal strings and may be negative when a
+// plan change leaves the customer a credit.
+type InvoiceResponse struct {
+	ID             int                        `json:"id"`
+	Number         string                     `json:"number,omitempty" example:"INV-2026-000042"`
+	UserID         *int                       `json:"user_id,omitempty"`
+	SubscriptionID *int                       `json:"subscription_id,omitempty"`
+	Status         string                     `json:"status" example:"open"`
+	CustomerName   string                     `json:"customer_name"`
+	CustomerEmail  string                     `json:"customer_email"`
+	Country        string                     `json:"country"`
+	PeriodStart    time.Time                  `json:"period_start"`
+	PeriodEnd      time.Time                  `json:"period_end"`
+	Subtotal       string                     `json:"subtotal" example:"99.00"`
+	TaxName        string                     `json:"tax_name" example:"VAT"`
+	TaxRate        string                     `json:"tax_rate" example:"19.00"`
+	TaxAmount      string                     `json:"tax_amount" example:"18.81"`
+	Total          string                     `json:"total" example:"117.81"`
+	IssuedAt       *time.Time                 `json:"issued_at,omitempty"`
+	DueAt          *time.Time                 `json:"due_at,omitempty"`
+	PaidAt         *time.Time                 `json:"paid_at,omitempty"`
+	VoidedAt       *time.Time                 `json:"voided_at,omitempty"`
+	CreatedAt      time.Time                  `json:"created_at"`
+	LineItems      []*InvoiceLineItemResponse `json:"line_items,omitempty"`
+}
+
+// GenerateInvoicesResponse counts the invoices created and issued by a run.
+type GenerateInvoicesResponse struct {
+	Created   int `json:"created"`
+	Finalized int `json:"finalized"`
+}
+
+// SetTaxRateRequest sets the tax charged to customers of a country. Rate is
+// a percentage as a decimal string; name defaults to VAT.
+type SetTaxRateRequest struct {
+	Name string `json:"name,omitempty" validate:"omitempty,max=50" example:"VAT"`
+	Rate string `json:"rate" validate:"required" example:"19"`
+}
+
+type TaxRateResponse struct {
+	Country   string    `json:"country" example:"DE"`
+	Name      string    `json:"name" example:"VAT"`
+	Rate      string    `json:"rate" example:"19.00"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ToInvoiceResponse leaves out the line items when items is nil.
+func ToInvoiceResponse(invoice db.Invoice, items []db.InvoiceLineItem) *InvoiceResponse {
+	resp := &InvoiceResponse{
+		ID:            int(invoice.ID),
+		Number:        invoice.Number.String,
+		Status:        invoice.Status,
+		CustomerName:  invoice.CustomerName,
+		CustomerEmail: invoice.CustomerEmail,
+		Country:       invoice.Country,
+		PeriodStart:   invoice.PeriodStart,
+		PeriodEnd:     invoice.PeriodEnd,
+		Subtotal:      invoice.Subtotal,
+		TaxName:       invoice.TaxName,
+		TaxRate:       invoice.TaxRate,
+		TaxAmount:     invoice.TaxAmount,
+		Total:         invoice.Total,
+	}
+
+	if invoice.UserID.Valid {
+		userID := int(invoice.UserID.Int32)
+		resp.UserID = &userID
+	}
+
+	if invoice.SubscriptionID.Valid {
+		subscriptionID := int(invoice.SubscriptionID.Int32)
+		resp.SubscriptionID = &subscriptionID
+	}
+
+	if invoice.IssuedAt.Valid {
+		resp.IssuedAt = &invoice.IssuedAt.Time
+	}
+
+	if invoice.DueAt.Valid {
+		resp.DueAt = &invoice.DueAt.Time
+	}
+
+	if invoice.PaidAt.Valid {
+		resp.PaidAt = &invoice.PaidAt.Time
+	}
+
+	if invoice.VoidedAt.Valid {
+		resp.VoidedAt = &invoice.VoidedAt.Time
+	}
+
+	if invoice.CreatedAt.Valid {
+		resp.CreatedAt = invoice.CreatedAt.Time
+	}
+
+	if items != nil {
+		resp.LineItems = make([]*InvoiceLineItemResponse, len(items))
+		for i, item := range items {
+			resp.LineItems[i] = &InvoiceLineItemResponse{
+				ID:          int(item.ID),
+				Description: item.Description,
+				Quantity:    int(item.Quantity),
+				UnitPrice:   item.UnitPrice,
+				Amount:      item.Amount,
+			}
+		}
+	}
+
+	return resp
+}
+
+func ToTaxRateResponse(taxRate db.TaxRate) *TaxRateResponse {
+	resp := &TaxRateResponse{
+		Country: taxRate.Country,
+		Name:    taxRate.Name,
+		Rate:    taxRate.Rate,
+	}
+
+	if taxRate.UpdatedAt.Valid {
+		resp.UpdatedAt = taxRate.UpdatedAt.Time
+	}
+
+	return resp
+}
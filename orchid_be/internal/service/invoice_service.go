@@ -0,0 +1,486 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"orchid_be/internal/config"
+	"orchid_be/internal/db"
+	"orchid_be/internal/repository"
+	"orchid_be/internal/utils"
+)
+
+var (
+	ErrInvoiceNotFound      = errors.New("invoice not found")
+	ErrInvalidInvoiceStatus = errors.New("invoice status does not allow this action")
+	ErrTaxRateNotFound      = errors.New("tax rate not found")
+	ErrInvalidTaxRate       = errors.New("rate must be a percentage between 0 and 100 with at most 2 decimal places")
+	ErrInvalidCountry       = errors.New("country must be an upper-case ISO 3166-1 alpha-2 code")
+)
+
+const (
+	taxRateDigits  = 3
+	maxTaxRate     = "100"
+	defaultTaxName = "VAT"
+)
+
+type InvoiceService interface {
+	GenerateInvoices(ctx context.Context, now time.Time) (*GenerateInvoicesResponse, error)
+	GetInvoices(ctx context.Context, page, limit int) ([]*InvoiceResponse, int, error)
+	GetInvoiceByID(ctx context.Context, id int) (*InvoiceResponse, error)
+	GetUserInvoices(ctx context.Context, userID, page, limit int) ([]*InvoiceResponse, int, error)
+	GetUserInvoice(ctx context.Context, userID, id int) (*InvoiceResponse, error)
+	FinalizeInvoice(ctx context.Context, id int) (*InvoiceResponse, error)
+	MarkInvoicePaid(ctx context.Context, id int) (*InvoiceResponse, error)
+	VoidInvoice(ctx context.Context, id int) (*InvoiceResponse, error)
+	RenderInvoice(invoice *InvoiceResponse) ([]byte, error)
+	GetTaxRates(ctx context.Context) ([]*TaxRateResponse, error)
+	SetTaxRate(ctx context.Context, country string, req *SetTaxRateRequest) (*TaxRateResponse, error)
+	DeleteTaxRate(ctx context.Context, country string) error
+}
+
+type invoiceService struct {
+	*BaseService
+	invoiceRepo      repository.InvoiceRepository
+	taxRateRepo      repository.TaxRateRepository
+	subscriptionRepo repository.SubscriptionRepository
+	planRepo         repository.PlanRepository
+	userRepo         repository.UserRepository
+	config           config.BillingConfig
+}
+
+func NewInvoiceService(invoiceRepo repository.InvoiceRepository, taxRateRepo repository.TaxRateRepository, subscriptionRepo repository.SubscriptionRepository, planRepo repository.PlanRepository, userRepo repository.UserRepository, cfg config.BillingConfig) InvoiceService {
+	return &invoiceService{
+		BaseService:      NewBaseService(),
+		invoiceRepo:      invoiceRepo,
+		taxRateRepo:      taxRateRepo,
+		subscriptionRepo: subscriptionRepo,
+		planRepo:         planRepo,
+		userRepo:         userRepo,
+		config:           cfg,
+	}
+}
+
+// GenerateInvoices bills the current period of every active subscription
+// that has no invoice for it yet, together with the plan changes not billed
+// so far. A subscription whose period is already billed but that changed
+// plan since gets an invoice for the changes alone. The drafts are then
+// finalized, including drafts left over by an earlier run. Running it again
+// creates nothing new, so a failed run can simply be repeated.
+func (s *invoiceService) GenerateInvoices(ctx context.Context, now time.Time) (*GenerateInvoicesResponse, error) {
+	subscriptions, err := s.subscriptionRepo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GenerateInvoicesResponse{}
+	for _, subscription := range subscriptions {
+		created, err := s.invoiceSubscription(ctx, subscription, now)
+		if err != nil {
+			log.Printf("Failed to invoice subscription %d: %v", subscription.ID, err)
+			continue
+		}
+		if created {
+			result.Created++
+		}
+	}
+
+	drafts, err := s.invoiceRepo.GetDrafts(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	for _, draft := range drafts {
+		if _, err := s.finalize(ctx, int(draft.ID), now); err != nil {
+			log.Printf("Failed to finalize invoice %d: %v", draft.ID, err)
+			continue
+		}
+		result.Finalized++
+	}
+
+	return result, nil
+}
+
+func (s *invoiceService) GetInvoices(ctx context.Context, page, limit int) ([]*InvoiceResponse, int, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	invoices, err := s.invoiceRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.invoiceRepo.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	invoiceResponses := make([]*InvoiceResponse, len(invoices))
+	for i, invoice := range invoices {
+		invoiceResponses[i] = ToInvoiceResponse(invoice, nil)
+	}
+
+	return invoiceResponses, total, nil
+}
+
+func (s *invoiceService) GetInvoiceByID(ctx context.Context, id int) (*InvoiceResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	invoice, err := s.invoiceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+
+	return s.withLineItems(ctx, invoice)
+}
+
+// GetUserInvoices lists the invoices issued to the user, drafts are not
+// shown until they are finalized.
+func (s *invoiceService) GetUserInvoices(ctx context.Context, userID, page, limit int) ([]*InvoiceResponse, int, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	invoices, err := s.invoiceRepo.GetIssuedByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.invoiceRepo.CountIssuedByUserID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	invoiceResponses := make([]*InvoiceResponse, len(invoices))
+	for i, invoice := range invoices {
+		invoiceResponses[i] = ToInvoiceResponse(invoice, nil)
+	}
+
+	return invoiceResponses, total, nil
+}
+
+// GetUserInvoice answers ErrInvoiceNotFound for drafts and for invoices of
+// other users alike.
+func (s *invoiceService) GetUserInvoice(ctx context.Context, userID, id int) (*InvoiceResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	invoice, err := s.invoiceRepo.GetByID(ctx, id)
+	if err != nil || int(invoice.UserID.Int32) != userID || invoice.Status == InvoiceStatusDraft {
+		return nil, ErrInvoiceNotFound
+	}
+
+	return s.withLineItems(ctx, invoice)
+}
+
+func (s *invoiceService) FinalizeInvoice(ctx context.Context, id int) (*InvoiceResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	invoice, err := s.finalize(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withLineItems(ctx, invoice)
+}
+
+func (s *invoiceService) MarkInvoicePaid(ctx context.Context, id int) (*InvoiceResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.invoiceRepo.GetByID(ctx, id); err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+
+	invoice, updated, err := s.invoiceRepo.MarkPaid(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrInvalidInvoiceStatus
+	}
+
+	return s.withLineItems(ctx, invoice)
+}
+
+// VoidInvoice cancels a draft or open invoice. An open invoice keeps its
+// number so the sequence has no gaps; a draft was never given one.
+func (s *invoiceService) VoidInvoice(ctx context.Context, id int) (*InvoiceResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.invoiceRepo.GetByID(ctx, id); err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+
+	invoice, updated, err := s.invoiceRepo.Void(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrInvalidInvoiceStatus
+	}
+
+	return s.withLineItems(ctx, invoice)
+}
+
+// RenderInvoice returns the invoice as a standalone HTML page that prints
+// well, for download.
+func (s *invoiceService) RenderInvoice(invoice *InvoiceResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	err := invoiceTemplate.Execute(&buf, invoicePage{
+		Invoice:       invoice,
+		SellerName:    s.config.SellerName,
+		SellerAddress: s.config.SellerAddress,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render invoice: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s *invoiceService) GetTaxRates(ctx context.Context) ([]*TaxRateResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	taxRates, err := s.taxRateRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	taxRateResponses := make([]*TaxRateResponse, len(taxRates))
+	for i, taxRate := range taxRates {
+		taxRateResponses[i] = ToTaxRateResponse(taxRate)
+	}
+
+	return taxRateResponses, nil
+}
+
+// SetTaxRate applies to invoices created from now on, issued invoices keep
+// the rate they were created with.
+func (s *invoiceService) SetTaxRate(ctx context.Context, country string, req *SetTaxRateRequest) (*TaxRateResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if err := utils.ValidateVar(country, "iso3166_1_alpha2"); err != nil {
+		return nil, ErrInvalidCountry
+	}
+
+	rate, err := utils.NormalizeDecimal(req.Rate, taxRateDigits, planScale)
+	if err != nil || utils.CompareDecimal(rate, maxTaxRate) > 0 {
+		return nil, ErrInvalidTaxRate
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = defaultTaxName
+	}
+
+	taxRate, err := s.taxRateRepo.Set(ctx, country, name, rate)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToTaxRateResponse(taxRate), nil
+}
+
+func (s *invoiceService) DeleteTaxRate(ctx context.Context, country string) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	deleted, err := s.taxRateRepo.Delete(ctx, country)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTaxRateNotFound
+	}
+
+	return nil
+}
+
+// invoiceSubscription creates the draft invoice of one subscription. It
+// reports false if there is nothing to bill.
+func (s *invoiceService) invoiceSubscription(ctx context.Context, subscription db.Subscription, now time.Time) (bool, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	subscription, err := settleSubscription(ctx, s.subscriptionRepo, s.planRepo, subscription, now)
+	if err != nil {
+		if errors.Is(err, ErrSubscriptionNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	_, err = s.invoiceRepo.GetBySubscriptionPeriod(ctx, int(subscription.ID), subscription.CurrentPeriodStart)
+	billPeriod := errors.Is(err, repository.ErrRecordNotFound)
+	if err != nil && !billPeriod {
+		return false, err
+	}
+
+	changes, err := s.subscriptionRepo.GetUnbilledChanges(ctx, int(subscription.ID))
+	if err != nil {
+		return false, err
+	}
+
+	if !billPeriod && len(changes) == 0 {
+		return false, nil
+	}
+
+	plans, err := s.planRepo.ListAll(ctx)
+	if err != nil {
+		return false, err
+	}
+	plansByID := make(map[int32]db.Plan, len(plans))
+	for _, plan := range plans {
+		plansByID[plan.ID] = plan
+	}
+
+	user, err := s.userRepo.GetByID(ctx, int(subscription.UserID))
+	if err != nil {
+		return false, err
+	}
+
+	draft := repository.InvoiceDraft{
+		UserID:         int(subscription.UserID),
+		SubscriptionID: int(subscription.ID),
+		CustomerName:   user.Name,
+		CustomerEmail:  user.Email,
+		Country:        user.Country,
+		PeriodStart:    subscription.CurrentPeriodStart,
+		PeriodEnd:      subscription.CurrentPeriodEnd,
+	}
+
+	if billPeriod {
+		// Bill the period at the plan it started with, later changes within
+		// the period are billed as prorations below.
+		planID := subscription.PlanID
+		for i := len(changes) - 1; i >= 0; i-- {
+			change := changes[i]
+			if change.CreatedAt.Time.After(subscription.CurrentPeriodStart) && sameInterval(plansByID, change) {
+				planID = change.FromPlanID
+			}
+		}
+
+		plan := plansByID[planID]
+		draft.Items = append(draft.Items, repository.InvoiceItem{
+			Description: fmt.Sprintf("%s plan, %s to %s", plan.Name, formatInvoiceDate(subscription.CurrentPeriodStart), formatInvoiceDate(subscription.CurrentPeriodEnd)),
+			Quantity:    1,
+			UnitPrice:   plan.Price,
+			Amount:      plan.Price,
+		})
+	} else {
+		// The period is billed already, this invoice only covers the changes
+		draft.PeriodStart = changes[0].CreatedAt.Time
+		draft.PeriodEnd = now
+	}
+
+	for _, change := range changes {
+		from, to := plansByID[change.FromPlanID], plansByID[change.ToPlanID]
+		changedAt := formatInvoiceDate(change.CreatedAt.Time)
+
+		credit := utils.SubtractDecimal("0", change.Credit, planScale)
+		draft.Items = append(draft.Items, repository.InvoiceItem{
+			Description: fmt.Sprintf("Unused time on %s plan after %s", from.Name, changedAt),
+			Quantity:    1,
+			UnitPrice:   credit,
+			Amount:      credit,
+		})
+
+		// A change to another interval started a new period, which is
+		// billed in full by the period line of its invoice.
+		if sameInterval(plansByID, change) {
+			draft.Items = append(draft.Items, repository.InvoiceItem{
+				Description: fmt.Sprintf("Remaining time on %s plan after %s", to.Name, changedAt),
+				Quantity:    1,
+				UnitPrice:   change.Charge,
+				Amount:      change.Charge,
+			})
+		}
+
+		draft.ChangeIDs = append(draft.ChangeIDs, int(change.ID))
+	}
+
+	draft.Subtotal = "0"
+	for _, item := range draft.Items {
+		draft.Subtotal = utils.AddDecimal(draft.Subtotal, item.Amount, planScale)
+	}
+
+	draft.TaxRate = "0.00"
+	if taxRate, err := s.taxRateRepo.Get(ctx, user.Country); err == nil {
+		draft.TaxName = taxRate.Name
+		draft.TaxRate = taxRate.Rate
+	}
+	draft.TaxAmount = utils.PercentOfDecimal(draft.Subtotal, draft.TaxRate, planScale)
+	draft.Total = utils.AddDecimal(draft.Subtotal, draft.TaxAmount, planScale)
+
+	if _, err := s.invoiceRepo.CreateDraft(ctx, draft); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// finalize issues a draft with its number and due date.
+func (s *invoiceService) finalize(ctx context.Context, id int, issuedAt time.Time) (db.Invoice, error) {
+	if _, err := s.invoiceRepo.GetByID(ctx, id); err != nil {
+		return db.Invoice{}, ErrInvoiceNotFound
+	}
+
+	dueAt := issuedAt.AddDate(0, 0, s.config.PaymentDueDays)
+	invoice, finalized, err := s.invoiceRepo.Finalize(ctx, id, issuedAt, dueAt)
+	if err != nil {
+		return db.Invoice{}, err
+	}
+	if !finalized {
+		return db.Invoice{}, ErrInvalidInvoiceStatus
+	}
+
+	return invoice, nil
+}
+
+func (s *invoiceService) withLineItems(ctx context.Context, invoice db.Invoice) (*InvoiceResponse, error) {
+	items, err := s.invoiceRepo.GetLineItems(ctx, int(invoice.ID))
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []db.InvoiceLineItem{}
+	}
+
+	return ToInvoiceResponse(invoice, items), nil
+}
+
+func sameInterval(plansByID map[int32]db.Plan, change db.SubscriptionChange) bool {
+	return plansByID[change.FromPlanID].BillingInterval == plansByID[change.ToPlanID].BillingInterval
+}
+
+func formatInvoiceDate(t time.Time) string {
+	return t.UTC().Format("Jan 2, 2006")
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"html/template"
+	"strings"
+	"time"
+)
+
+type invoicePage struct {
+	Invoice       *InvoiceResponse
+	SellerName    string
+	SellerAddress string
+}
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money": func(amount string) string {
+		if strings.HasPrefix(amount, "-") {
+			return "-$" + strings.TrimPrefix(amount, "-")
+		}
+		return "$" + amount
+	},
+	"date": func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return formatInvoiceDate(*t)
+	},
+	"lines": func(s string) []string {
+		return strings.Split(s, "\n")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{with .Invoice.Number}}{{.}}{{else}}draft{{end}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #111827; margin: 40px; font-size: 14px; }
+h1 { font-size: 28px; margin: 0 0 4px; }
+.muted { color: #6b7280; }
+.header, .parties { display: flex; justify-content: space-between; margin-bottom: 32px; }
+.status { text-transform: uppercase; font-weight: 600; letter-spacing: .05em; }
+table { width: 100%; border-collapse: collapse; }
+th, td { padding: 8px; text-align: left; border-bottom: 1px solid #e5e7eb; }
+th.amount, td.amount { text-align: right; white-space: nowrap; }
+tfoot td { border-bottom: none; }
+tfoot tr.total td { font-weight: 700; font-size: 16px; border-top: 2px solid #111827; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<div class="header">
+  <div>
+    <h1>Invoice</h1>
+    <div>{{with .Invoice.Number}}{{.}}{{else}}<span class="muted">Draft</span>{{end}}</div>
+  </div>
+  <div>
+    <div class="status">{{.Invoice.Status}}</div>
+    {{with .Invoice.IssuedAt}}<div>Issued {{date .}}</div>{{end}}
+    {{with .Invoice.DueAt}}<div>Due {{date .}}</div>{{end}}
+    {{with .Invoice.PaidAt}}<div>Paid {{date .}}</div>{{end}}
+  </div>
+</div>
+<div class="parties">
+  <div>
+    <div class="muted">From</div>
+    <div>{{.SellerName}}</div>
+    {{range lines .SellerAddress}}<div>{{.}}</div>{{end}}
+  </div>
+  <div>
+    <div class="muted">Bill to</div>
+    <div>{{.Invoice.CustomerName}}</div>
+    <div>{{.Invoice.CustomerEmail}}</div>
+    {{with .Invoice.Country}}<div>{{.}}</div>{{end}}
+  </div>
+</div>
+<table>
+  <thead>
+    <tr><th>Description</th><th class="amount">Qty</th><th class="amount">Unit price</th><th class="amount">Amount</th></tr>
+  </thead>
+  <tbody>
+    {{range .Invoice.LineItems}}<tr><td>{{.Description}}</td><td class="amount">{{.Quantity}}</td><td class="amount">{{money .UnitPrice}}</td><td class="amount">{{money .Amount}}</td></tr>
+    {{end}}
+  </tbody>
+  <tfoot>
+    <tr><td colspan="3" class="amount">Subtotal</td><td class="amount">{{money .Invoice.Subtotal}}</td></tr>
+    <tr><td colspan="3" class="amount">{{with .Invoice.TaxName}}{{.}}{{else}}Tax{{end}} ({{.Invoice.TaxRate}}%)</td><td class="amount">{{money .Invoice.TaxAmount}}</td></tr>
+    <tr class="total"><td colspan="3" class="amount">Total</td><td class="amount">{{money .Invoice.Total}}</td></tr>
+  </tfoot>
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"orchid_be/internal/config"
+	"orchid_be/internal/db"
+	"orchid_be/internal/repository"
+)
+
+var ErrAccountLocked = errors.New("too many failed login attempts, account is temporarily locked")
+
+// loginProtection slows down password guessing on a single account. After
+// DelayAfter failures every further failure locks the account for a delay that
+// doubles each time, and after MaxFailedAttempts it is locked for
+// LockoutDuration.
+type loginProtection struct {
+	userRepo repository.UserRepository
+	config   config.LoginProtectionConfig
+}
+
+func newLoginProtection(userRepo repository.UserRepository, cfg config.LoginProtectionConfig) *loginProtection {
+	return &loginProtection{
+		userRepo: userRepo,
+		config:   cfg,
+	}
+}
+
+// check refuses the attempt while the account is locked.
+func (p *loginProtection) check(user db.User) error {
+	if user.LockedUntil.Valid && time.Now().Before(user.LockedUntil.Time) {
+		wait := time.Until(user.LockedUntil.Time).Round(time.Second)
+		if wait < time.Second {
+			wait = time.Second
+		}
+		return fmt.Errorf("%w, try again in %s", ErrAccountLocked, wait)
+	}
+	return nil
+}
+
+// recordFailure counts a failed password or second-factor check. Errors are
+// only logged so the caller still answers with the original failure.
+func (p *loginProtection) recordFailure(ctx context.Context, user db.User) {
+	attempts, err := p.userRepo.RecordFailedLogin(ctx, int(user.ID), time.Now().Add(-p.config.FailureWindow))
+	if err != nil {
+		log.Printf("Failed to record failed login for user %d: %v", user.ID, err)
+		return
+	}
+
+	if delay := p.lockDuration(attempts); delay > 0 {
+		if err := p.userRepo.Lock(ctx, int(user.ID), time.Now().Add(delay)); err != nil {
+			log.Printf("Failed to lock user %d: %v", user.ID, err)
+		}
+	}
+}
+
+// recordSuccess clears the counter after a completed login.
+func (p *loginProtection) recordSuccess(ctx context.Context, user db.User) error {
+	if user.FailedLoginAttempts == 0 && !user.LockedUntil.Valid {
+		return nil
+	}
+	return p.userRepo.ResetFailedLogins(ctx, int(user.ID))
+}
+
+func (p *loginProtection) lockDuration(attempts int) time.Duration {
+	if p.config.MaxFailedAttempts > 0 && attempts >= p.config.MaxFailedAttempts {
+		return p.config.LockoutDuration
+	}
+
+	if p.config.DelayAfter <= 0 || attempts < p.config.DelayAfter {
+		return 0
+	}
+
+	// Cap the exponent so the shift cannot overflow
+	exponent := attempts - p.config.DelayAfter
+	if exponent > 30 {
+		exponent = 30
+	}
+
+	delay := p.config.BaseDelay << exponent
+	if delay <= 0 || delay > p.config.MaxDelay {
+		delay = p.config.MaxDelay
+	}
+
+	return delay
+}
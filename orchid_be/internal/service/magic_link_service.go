@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"orchid_be/internal/config"
+	"orchid_be/internal/mailer"
+	"orchid_be/internal/repository"
+	"orchid_be/internal/utils"
+)
+
+var ErrMagicLinkDisabled = errors.New("magic link login is disabled")
+
+type MagicLinkService interface {
+	RequestLink(ctx context.Context, req *MagicLinkRequest) error
+	ConsumeLink(ctx context.Context, req *ConsumeMagicLinkRequest, client ClientInfo) (*TokenResponse, *MFAChallengeResponse, error)
+}
+
+type magicLinkService struct {
+	*BaseService
+	magicLinkRepo   repository.MagicLinkRepository
+	userRepo        repository.UserRepository
+	authService     AuthService
+	jwtManager      *utils.JWTManager
+	mailer          mailer.Mailer
+	authConfig      config.AuthConfig
+	magicLinkConfig config.MagicLinkConfig
+}
+
+func NewMagicLinkService(magicLinkRepo repository.MagicLinkRepository, userRepo repository.UserRepository, authService AuthService, jwtManager *utils.JWTManager, mail mailer.Mailer, authConfig config.AuthConfig, magicLinkConfig config.MagicLinkConfig) MagicLinkService {
+	return &magicLinkService{
+		BaseService:     NewBaseService(),
+		magicLinkRepo:   magicLinkRepo,
+		userRepo:        userRepo,
+		authService:     authService,
+		jwtManager:      jwtManager,
+		mailer:          mail,
+		authConfig:      authConfig,
+		magicLinkConfig: magicLinkConfig,
+	}
+}
+
+// RequestLink emails a login link to active accounts. Like a password reset
+// request it succeeds silently for unknown addresses so callers cannot probe
+// for accounts.
+func (s *magicLinkService) RequestLink(ctx context.Context, req *MagicLinkRequest) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if !s.magicLinkConfig.Enabled {
+		return ErrMagicLinkDisabled
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil || user.Status != UserStatusActive {
+		return nil
+	}
+
+	tokenID, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate magic link token: %w", err)
+	}
+
+	// Only the most recent link stays valid
+	if err := s.magicLinkRepo.InvalidateAllForUser(ctx, int(user.ID)); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.magicLinkConfig.TTL)
+
+	if _, err := s.magicLinkRepo.Create(ctx, int(user.ID), utils.HashToken(tokenID), expiresAt); err != nil {
+		return err
+	}
+
+	// The link is signed so it cannot be forged; the stored ID makes it
+	// single-use
+	token, err := s.jwtManager.Sign(&utils.Claims{
+		Subject:   strconv.Itoa(int(user.ID)),
+		Email:     user.Email,
+		TokenType: TokenTypeMagicLink,
+		ID:        tokenID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sign magic link token: %w", err)
+	}
+
+	link := s.authConfig.FrontendURL + "/magic-link?token=" + url.QueryEscape(token)
+
+	// Sending takes long enough to tell known addresses from unknown ones
+	mailer.SendInBackground(ctx, s.mailer, mailer.Message{
+		To:      user.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen the link below to log in:\n\n%s\n\nThe link expires in %s and can only be used once. If you did not ask to log in, you can ignore this email.\n",
+			user.Name, link, s.magicLinkConfig.TTL),
+	})
+
+	return nil
+}
+
+// ConsumeLink redeems a login link for the usual token pair. Lockout and
+// two-factor authentication apply as for a password login.
+func (s *magicLinkService) ConsumeLink(ctx context.Context, req *ConsumeMagicLinkRequest, client ClientInfo) (*TokenResponse, *MFAChallengeResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if !s.magicLinkConfig.Enabled {
+		return nil, nil, ErrMagicLinkDisabled
+	}
+
+	claims, err := s.jwtManager.Parse(req.Token)
+	if err != nil || claims.TokenType != TokenTypeMagicLink || claims.ID == "" {
+		return nil, nil, ErrInvalidToken
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, nil, ErrInvalidToken
+	}
+
+	token, err := s.magicLinkRepo.Consume(ctx, utils.HashToken(claims.ID))
+	if err != nil || int(token.UserID) != userID {
+		return nil, nil, ErrInvalidToken
+	}
+
+	return s.authService.CompleteLogin(ctx, userID, client)
+}
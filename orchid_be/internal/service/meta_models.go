@@ -0,0 +1,114 @@
+package service
+
+import "reflect"
+
+// Field and column types, named like the frontend's FieldConfig and
+// ColumnConfig types.
+const (
+	FieldTypeText     = "text"
+	FieldTypeTextarea = "textarea"
+	FieldTypeEmail    = "email"
+	FieldTypePassword = "password"
+	FieldTypeNumber   = "number"
+	FieldTypeSelect   = "select"
+	FieldTypeCheckbox = "checkbox"
+	FieldTypeDate     = "date"
+
+	ColumnTypeText    = "text"
+	ColumnTypeNumber  = "number"
+	ColumnTypeDate    = "date"
+	ColumnTypeBoolean = "boolean"
+	ColumnTypeBadge   = "badge"
+)
+
+// EntityMeta is an entity's config together with the request and response
+// types of its routes, which the metadata endpoint describes.
+type EntityMeta struct {
+	EntityConfig
+	Create   reflect.Type
+	Update   reflect.Type
+	Response reflect.Type
+}
+
+// DescribeEntity declares the metadata of an entity whose routes take C and U
+// as create and update bodies and return R.
+func DescribeEntity[C, U, R any](config EntityConfig) EntityMeta {
+	return EntityMeta{
+		EntityConfig: config,
+		Create:       structType[C](),
+		Update:       structType[U](),
+		Response:     structType[R](),
+	}
+}
+
+// EntityMetaResponse describes an entity so the dashboard can build its table
+// and forms. Permissions left empty only need a signed-in user.
+type EntityMetaResponse struct {
+	Type        string                     `json:"type" example:"users"`
+	DisplayName string                     `json:"display_name" example:"User"`
+	Route       string                     `json:"route" example:"/api/users"`
+	Columns     []*ColumnMetaResponse      `json:"columns"`
+	Fields      []*FieldMetaResponse       `json:"fields"`
+	DefaultSort *SortMetaResponse          `json:"default_sort,omitempty"`
+	Permissions *EntityPermissionsResponse `json:"permissions"`
+}
+
+// ColumnMetaResponse is one column of an entity's table, taken from its
+// response type.
+type ColumnMetaResponse struct {
+	Key        string                 `json:"key" example:"status"`
+	Label      string                 `json:"label" example:"Status"`
+	Type       string                 `json:"type" example:"badge"`
+	Sortable   bool                   `json:"sortable"`
+	Filterable bool                   `json:"filterable"`
+	Searchable bool                   `json:"searchable"`
+	Options    []*FieldOptionResponse `json:"options,omitempty"`
+}
+
+// FieldMetaResponse is one input of an entity's forms, taken from its create
+// and update requests. Required applies to the create form; the update form
+// only sends what changed. OptionsFrom names the entity whose records are the
+// choices of a select, e.g. "categories".
+type FieldMetaResponse struct {
+	Name        string                   `json:"name" example:"status"`
+	Label       string                   `json:"label" example:"Status"`
+	Type        string                   `json:"type" example:"select"`
+	Required    bool                     `json:"required"`
+	Create      bool                     `json:"create"`
+	Update      bool                     `json:"update"`
+	Validation  *FieldValidationResponse `json:"validation,omitempty"`
+	Options     []*FieldOptionResponse   `json:"options,omitempty"`
+	OptionsFrom string                   `json:"options_from,omitempty" example:"categories"`
+	Example     string                   `json:"example,omitempty"`
+}
+
+// FieldValidationResponse holds the rules the server checks. Min and max are
+// lengths for text and values for numbers. Rules is the raw validate tag.
+type FieldValidationResponse struct {
+	Min     *float64 `json:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+	Pattern string   `json:"pattern,omitempty" example:"^[A-Z]{2}$"`
+	Rules   string   `json:"rules,omitempty" example:"omitempty,oneof=active inactive pending"`
+}
+
+type FieldOptionResponse struct {
+	Value string `json:"value" example:"active"`
+	Label string `json:"label" example:"Active"`
+}
+
+type SortMetaResponse struct {
+	Field     string `json:"field" example:"created_at"`
+	Direction string `json:"direction" example:"desc"`
+}
+
+type EntityPermissionsResponse struct {
+	Read   string `json:"read,omitempty"`
+	Create string `json:"create,omitempty"`
+	Update string `json:"update,omitempty"`
+	Delete string `json:"delete,omitempty"`
+}
+
+// structType returns the struct type behind T, which may be a pointer.
+func structType[T any]() reflect.Type {
+	return indirectType(reflect.TypeOf((*T)(nil)).Elem())
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"orchid_be/internal/utils"
+)
+
+var ErrEntityTypeNotFound = errors.New("entity type not found")
+
+// patterns are the regular expressions of the validate rules that have a
+// simple client-side equivalent.
+var patterns = map[string]string{
+	"iso3166_1_alpha2": "^[A-Z]{2}$",
+	"numeric":          "^[-+]?[0-9]+(\\.[0-9]+)?$",
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// MetaService describes the entities the dashboard renders, generated from the
+// tags of their request and response structs.
+type MetaService interface {
+	GetEntities() []*EntityMetaResponse
+	GetEntity(entityType string) (*EntityMetaResponse, error)
+}
+
+type metaService struct {
+	*BaseService
+	entities []*EntityMetaResponse
+}
+
+// NewMetaService describes entities once, in the given order. The structs do
+// not change at runtime, so neither does the metadata.
+func NewMetaService(entities ...EntityMeta) MetaService {
+	s := &metaService{
+		BaseService: NewBaseService(),
+		entities:    make([]*EntityMetaResponse, len(entities)),
+	}
+
+	for i, entity := range entities {
+		s.entities[i] = describeEntity(entity)
+	}
+
+	return s
+}
+
+func (s *metaService) GetEntities() []*EntityMetaResponse {
+	return s.entities
+}
+
+func (s *metaService) GetEntity(entityType string) (*EntityMetaResponse, error) {
+	for _, entity := range s.entities {
+		if entity.Type == entityType {
+			return entity, nil
+		}
+	}
+	return nil, ErrEntityTypeNotFound
+}
+
+func describeEntity(entity EntityMeta) *EntityMetaResponse {
+	fields := describeFields(entity.Create, entity.Update)
+
+	resp := &EntityMetaResponse{
+		Type:        entity.Name,
+		DisplayName: entity.DisplayName,
+		Route:       "/api/" + entity.Name,
+		Columns:     describeColumns(entity, fields),
+		Fields:      fields,
+		Permissions: &EntityPermissionsResponse{
+			Read:   entity.Permissions.Read,
+			Create: entity.Permissions.Create,
+			Update: entity.Permissions.Update,
+			Delete: entity.Permissions.Delete,
+		},
+	}
+
+	if entity.DefaultSort != "" {
+		resp.DefaultSort = &SortMetaResponse{Field: entity.DefaultSort, Direction: "asc"}
+		if entity.DefaultDesc {
+			resp.DefaultSort.Direction = "desc"
+		}
+	}
+
+	return resp
+}
+
+// describeFields lists the fields of the create request followed by those
+// only the update request has.
+func describeFields(create, update reflect.Type) []*FieldMetaResponse {
+	fields := []*FieldMetaResponse{}
+	byName := make(map[string]*FieldMetaResponse)
+
+	for i := 0; i < create.NumField(); i++ {
+		field := describeField(create.Field(i))
+		if field == nil {
+			continue
+		}
+		field.Create = true
+		fields = append(fields, field)
+		byName[field.Name] = field
+	}
+
+	for i := 0; i < update.NumField(); i++ {
+		structField := update.Field(i)
+		if field, ok := byName[utils.JSONName(structField)]; ok {
+			field.Update = true
+			continue
+		}
+
+		field := describeField(structField)
+		if field == nil {
+			continue
+		}
+		field.Required = false
+		field.Update = true
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// describeField reads a form input from a request field. The type follows the
+// Go type, refined by the validate tag (email, oneof), a format tag
+// ("password", "decimal") and an options tag naming the entity a select
+// picks from.
+func describeField(structField reflect.StructField) *FieldMetaResponse {
+	name := utils.JSONName(structField)
+	if name == "" {
+		return nil
+	}
+
+	field := &FieldMetaResponse{
+		Name:    name,
+		Label:   humanize(name),
+		Type:    inputType(indirectType(structField.Type), structField.Tag.Get("format")),
+		Example: structField.Tag.Get("example"),
+	}
+
+	rules := structField.Tag.Get("validate")
+	validation := &FieldValidationResponse{Rules: rules}
+
+	for _, rule := range strings.Split(rules, ",") {
+		if rule == "dive" {
+			break
+		}
+
+		for _, alternative := range strings.Split(rule, "|") {
+			key, param, _ := strings.Cut(alternative, "=")
+
+			switch key {
+			case "required":
+				field.Required = true
+			case "min":
+				validation.Min = parseBound(param)
+			case "max":
+				validation.Max = parseBound(param)
+			case "len":
+				validation.Min = parseBound(param)
+				validation.Max = parseBound(param)
+			case "email":
+				field.Type = FieldTypeEmail
+			case "oneof":
+				field.Type = FieldTypeSelect
+				for _, value := range strings.Fields(param) {
+					value = strings.Trim(value, "'")
+					field.Options = append(field.Options, &FieldOptionResponse{Value: value, Label: humanize(value)})
+				}
+			default:
+				if pattern, ok := patterns[key]; ok {
+					validation.Pattern = pattern
+				}
+			}
+		}
+	}
+
+	if from := structField.Tag.Get("options"); from != "" {
+		field.Type = FieldTypeSelect
+		field.OptionsFrom = from
+		field.Label = humanize(strings.TrimSuffix(name, "_id"))
+	}
+
+	if field.Type == FieldTypeText && validation.Max != nil && *validation.Max > 255 {
+		field.Type = FieldTypeTextarea
+	}
+
+	if rules != "" {
+		field.Validation = validation
+	}
+
+	return field
+}
+
+// describeColumns lists the scalar fields of the response type. Fields with
+// fixed options become badges carrying the same options.
+func describeColumns(entity EntityMeta, fields []*FieldMetaResponse) []*ColumnMetaResponse {
+	options := make(map[string][]*FieldOptionResponse)
+	for _, field := range fields {
+		if len(field.Options) > 0 {
+			options[field.Name] = field.Options
+		}
+	}
+
+	columns := []*ColumnMetaResponse{}
+	for i := 0; i < entity.Response.NumField(); i++ {
+		structField := entity.Response.Field(i)
+
+		key := utils.JSONName(structField)
+		columnType := outputType(indirectType(structField.Type), structField.Tag.Get("format"))
+		if key == "" || columnType == "" {
+			continue
+		}
+
+		column := &ColumnMetaResponse{
+			Key:        key,
+			Label:      humanize(key),
+			Type:       columnType,
+			Sortable:   containsString(entity.Sorts, key),
+			Filterable: containsString(entity.Filters, key),
+			Searchable: containsString(entity.Search, key),
+			Options:    options[key],
+		}
+		if column.Options != nil {
+			column.Type = ColumnTypeBadge
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns
+}
+
+func inputType(t reflect.Type, format string) string {
+	switch format {
+	case "password":
+		return FieldTypePassword
+	case "decimal":
+		return FieldTypeNumber
+	}
+
+	if t == timeType {
+		return FieldTypeDate
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return FieldTypeCheckbox
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return FieldTypeNumber
+	}
+	return FieldTypeText
+}
+
+// outputType returns "" for values a table cannot show, such as lists and
+// nested objects.
+func outputType(t reflect.Type, format string) string {
+	if format == "decimal" {
+		return ColumnTypeNumber
+	}
+
+	if t == timeType {
+		return ColumnTypeDate
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return ColumnTypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return ColumnTypeNumber
+	case reflect.String:
+		return ColumnTypeText
+	}
+	return ""
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func parseBound(param string) *float64 {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
+
+// humanize turns a json name into a label, e.g. "email_verified_at" into
+// "Email verified at".
+func humanize(name string) string {
+	if name == "" {
+		return name
+	}
+
+	words := strings.Split(name, "_")
+	for i, word := range words {
+		if word == "id" {
+			words[i] = "ID"
+		}
+	}
+
+	label := strings.Join(words, " ")
+	return strings.ToUpper(label[:1]) + label[1:]
+}
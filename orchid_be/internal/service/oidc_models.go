@@ -0,0 +1,19 @@
+package service
+
+type OIDCProviderResponse struct {
+	Name string `json:"name"`
+}
+
+// OIDCAuthorizationResponse starts a login at an identity provider. The client
+// keeps State and compares it with the state the provider redirects back with
+// before calling the callback endpoint.
+type OIDCAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+	ExpiresIn        int    `json:"expires_in"`
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"orchid_be/internal/config"
+	"orchid_be/internal/db"
+	"orchid_be/internal/oidc"
+	"orchid_be/internal/repository"
+	"orchid_be/internal/utils"
+)
+
+var (
+	ErrOIDCProviderNotFound   = errors.New("identity provider not found")
+	ErrInvalidOIDCState       = errors.New("login state is invalid or expired")
+	ErrOIDCLoginFailed        = errors.New("identity provider login failed")
+	ErrOIDCEmailNotVerified   = errors.New("identity provider did not confirm the email address")
+	ErrOIDCSignUpDisabled     = errors.New("no account exists for this email address")
+	ErrOIDCAccountNotVerified = errors.New("the account with this email address has not verified it; log in with its password and verify the email first")
+)
+
+type OIDCService interface {
+	GetProviders() []*OIDCProviderResponse
+	StartLogin(ctx context.Context, provider string) (*OIDCAuthorizationResponse, error)
+	FinishLogin(ctx context.Context, provider string, req *OIDCCallbackRequest, client ClientInfo) (*TokenResponse, *MFAChallengeResponse, error)
+}
+
+type oidcService struct {
+	*BaseService
+	oidcRepo    repository.OIDCRepository
+	userRepo    repository.UserRepository
+	authService AuthService
+	providers   map[string]*oidc.Provider
+	oidcConfig  config.OIDCConfig
+}
+
+func NewOIDCService(oidcRepo repository.OIDCRepository, userRepo repository.UserRepository, authService AuthService, providers map[string]*oidc.Provider, oidcConfig config.OIDCConfig) OIDCService {
+	return &oidcService{
+		BaseService: NewBaseService(),
+		oidcRepo:    oidcRepo,
+		userRepo:    userRepo,
+		authService: authService,
+		providers:   providers,
+		oidcConfig:  oidcConfig,
+	}
+}
+
+func (s *oidcService) GetProviders() []*OIDCProviderResponse {
+	names := oidc.Names(s.providers)
+	responses := make([]*OIDCProviderResponse, len(names))
+	for i, name := range names {
+		responses[i] = &OIDCProviderResponse{Name: name}
+	}
+	return responses
+}
+
+// StartLogin stores a one-time state with the PKCE verifier and nonce and
+// returns the provider URL to send the browser to.
+func (s *oidcService) StartLogin(ctx context.Context, provider string) (*OIDCAuthorizationResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrOIDCProviderNotFound
+	}
+
+	if err := s.oidcRepo.DeleteExpiredLoginStates(ctx); err != nil {
+		log.Printf("Failed to clean up oidc login states: %v", err)
+	}
+
+	state, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate state: %w", err)
+	}
+
+	nonce, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	codeVerifier, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+
+	authorizationURL, err := p.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+
+	err = s.oidcRepo.CreateLoginState(ctx, utils.HashToken(state), provider, codeVerifier, nonce, time.Now().Add(s.oidcConfig.StateTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCAuthorizationResponse{
+		AuthorizationURL: authorizationURL,
+		State:            state,
+		ExpiresIn:        int(s.oidcConfig.StateTTL.Seconds()),
+	}, nil
+}
+
+// FinishLogin exchanges the authorization code, verifies the ID token and logs
+// in the linked user, linking or creating one by verified email on first use.
+func (s *oidcService) FinishLogin(ctx context.Context, provider string, req *OIDCCallbackRequest, client ClientInfo) (*TokenResponse, *MFAChallengeResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, nil, ErrOIDCProviderNotFound
+	}
+
+	state, err := s.oidcRepo.ConsumeLoginState(ctx, utils.HashToken(req.State))
+	if err != nil {
+		return nil, nil, ErrInvalidOIDCState
+	}
+
+	if state.Provider != provider || time.Now().After(state.ExpiresAt) {
+		return nil, nil, ErrInvalidOIDCState
+	}
+
+	token, err := p.Exchange(ctx, req.Code, state.CodeVerifier)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+
+	claims, err := p.VerifyIDToken(ctx, token.IDToken, state.Nonce)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+
+	userID, err := s.resolveUser(ctx, provider, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.authService.CompleteLogin(ctx, userID, client)
+}
+
+// resolveUser finds the user behind a provider account. Accounts are matched by
+// the provider's subject first; email is only trusted when the provider says
+// it is verified, and only links to a user who verified it too. An active
+// account with an unverified address may belong to someone else (created by an
+// admin or changed since), so it is refused rather than linked.
+func (s *oidcService) resolveUser(ctx context.Context, provider string, claims *oidc.IDToken) (int, error) {
+	identity, err := s.oidcRepo.GetIdentity(ctx, provider, claims.Subject)
+	if err == nil {
+		if err := s.oidcRepo.TouchIdentity(ctx, int(identity.ID), claims.Email); err != nil {
+			log.Printf("Failed to record login of identity %d: %v", identity.ID, err)
+		}
+		return int(identity.UserID), nil
+	}
+
+	if claims.Email == "" || !bool(claims.EmailVerified) {
+		return 0, ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, claims.Email)
+	if err != nil {
+		if !s.oidcConfig.AllowSignUp {
+			return 0, ErrOIDCSignUpDisabled
+		}
+		user, err = s.createUser(ctx, claims)
+		if err != nil {
+			return 0, err
+		}
+	} else if user.Status == UserStatusPending {
+		user, err = s.claimPendingUser(ctx, user)
+		if err != nil {
+			return 0, err
+		}
+	} else if !user.EmailVerifiedAt.Valid {
+		return 0, ErrOIDCAccountNotVerified
+	}
+
+	if _, err := s.oidcRepo.CreateIdentity(ctx, int(user.ID), provider, claims.Subject, claims.Email); err != nil {
+		return 0, err
+	}
+
+	return int(user.ID), nil
+}
+
+// createUser signs up a new user whose email the provider already verified.
+// The password is random, a password login needs a reset first.
+func (s *oidcService) createUser(ctx context.Context, claims *oidc.IDToken) (db.User, error) {
+	name := claims.Name
+	if name == "" {
+		name = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+
+	passwordHash, err := randomPasswordHash()
+	if err != nil {
+		return db.User{}, err
+	}
+
+	user, err := s.userRepo.Create(ctx, name, claims.Email, passwordHash, UserStatusPending, repository.UserProfile{})
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	user, err = s.userRepo.VerifyEmail(ctx, int(user.ID), UserStatusActive)
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	return user, nil
+}
+
+// claimPendingUser activates an unverified account for the verified owner of
+// its address. Anyone could have registered it, so its password is replaced
+// before the account is activated.
+func (s *oidcService) claimPendingUser(ctx context.Context, user db.User) (db.User, error) {
+	passwordHash, err := randomPasswordHash()
+	if err != nil {
+		return db.User{}, err
+	}
+
+	if _, err := s.userRepo.UpdatePassword(ctx, int(user.ID), passwordHash); err != nil {
+		return db.User{}, fmt.Errorf("failed to update password: %w", err)
+	}
+
+	user, err = s.userRepo.VerifyEmail(ctx, int(user.ID), UserStatusActive)
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	return user, nil
+}
+
+func randomPasswordHash() (string, error) {
+	password, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return passwordHash, nil
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"orchid_be/internal/config"
+	"orchid_be/internal/db"
+	"orchid_be/internal/oidc"
+	"orchid_be/internal/oidc/oidctest"
+	"orchid_be/internal/repository"
+)
+
+const testProvider = "test"
+
+type fakeOIDCRepository struct {
+	states     map[string]db.OidcLoginState
+	identities []db.UserIdentity
+}
+
+func (r *fakeOIDCRepository) CreateLoginState(ctx context.Context, stateHash, provider, codeVerifier, nonce string, expiresAt time.Time) error {
+	r.states[stateHash] = db.OidcLoginState{StateHash: stateHash, Provider: provider, CodeVerifier: codeVerifier, Nonce: nonce, ExpiresAt: expiresAt}
+	return nil
+}
+
+func (r *fakeOIDCRepository) ConsumeLoginState(ctx context.Context, stateHash string) (db.OidcLoginState, error) {
+	state, ok := r.states[stateHash]
+	if !ok {
+		return db.OidcLoginState{}, sql.ErrNoRows
+	}
+	delete(r.states, stateHash)
+	return state, nil
+}
+
+func (r *fakeOIDCRepository) DeleteExpiredLoginStates(ctx context.Context) error {
+	return nil
+}
+
+func (r *fakeOIDCRepository) GetIdentity(ctx context.Context, provider, subject string) (db.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return db.UserIdentity{}, sql.ErrNoRows
+}
+
+func (r *fakeOIDCRepository) CreateIdentity(ctx context.Context, userID int, provider, subject, email string) (db.UserIdentity, error) {
+	identity := db.UserIdentity{ID: int32(len(r.identities) + 1), UserID: int32(userID), Provider: provider, Subject: subject, Email: email}
+	r.identities = append(r.identities, identity)
+	return identity, nil
+}
+
+func (r *fakeOIDCRepository) TouchIdentity(ctx context.Context, id int, email string) error {
+	return nil
+}
+
+// fakeUserRepository keeps users in memory. Methods the OIDC login does not
+// use are left to the nil embedded interface and panic.
+type fakeUserRepository struct {
+	repository.UserRepository
+	users map[int]db.User
+}
+
+func (r *fakeUserRepository) add(user db.User) db.User {
+	user.ID = int32(len(r.users) + 1)
+	r.users[int(user.ID)] = user
+	return user
+}
+
+func (r *fakeUserRepository) GetByID(ctx context.Context, id int) (db.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return db.User{}, fmt.Errorf("user with id %d not found", id)
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepository) GetByEmail(ctx context.Context, email string) (db.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return db.User{}, fmt.Errorf("user with email %s not found", email)
+}
+
+func (r *fakeUserRepository) Create(ctx context.Context, name, email, passwordHash, status string, profile repository.UserProfile) (db.User, error) {
+	return r.add(db.User{Name: name, Email: email, PasswordHash: passwordHash, Status: status}), nil
+}
+
+func (r *fakeUserRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) (db.User, error) {
+	user := r.users[id]
+	user.PasswordHash = passwordHash
+	r.users[id] = user
+	return user, nil
+}
+
+func (r *fakeUserRepository) VerifyEmail(ctx context.Context, id int, status string) (db.User, error) {
+	user := r.users[id]
+	user.Status = status
+	user.EmailVerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.users[id] = user
+	return user, nil
+}
+
+// fakeAuthService logs in whoever the OIDC service resolved.
+type fakeAuthService struct {
+	AuthService
+}
+
+func (s *fakeAuthService) CompleteLogin(ctx context.Context, userID int, client ClientInfo) (*TokenResponse, *MFAChallengeResponse, error) {
+	return &TokenResponse{User: &UserResponse{ID: userID}}, nil, nil
+}
+
+type oidcFixture struct {
+	service  OIDCService
+	server   *oidctest.Server
+	oidcRepo *fakeOIDCRepository
+	userRepo *fakeUserRepository
+}
+
+func newOIDCFixture(t *testing.T, allowSignUp bool) *oidcFixture {
+	t.Helper()
+
+	server := oidctest.NewServer(t, "orchid")
+	provider := oidc.NewProvider(testProvider, config.OIDCProviderConfig{
+		Issuer:       server.Issuer(),
+		ClientID:     server.ClientID,
+		ClientSecret: server.ClientSecret,
+		RedirectURL:  "http://localhost:4321/auth/oidc/test/callback",
+	}, server.Client())
+
+	f := &oidcFixture{
+		server:   server,
+		oidcRepo: &fakeOIDCRepository{states: make(map[string]db.OidcLoginState)},
+		userRepo: &fakeUserRepository{users: make(map[int]db.User)},
+	}
+	f.service = NewOIDCService(f.oidcRepo, f.userRepo, &fakeAuthService{}, map[string]*oidc.Provider{testProvider: provider}, config.OIDCConfig{
+		AllowSignUp: allowSignUp,
+		StateTTL:    10 * time.Minute,
+	})
+
+	return f
+}
+
+// login runs the whole flow against the stand-in provider: start, let the
+// provider issue a code for the given claims, and finish with it.
+func (f *oidcFixture) login(t *testing.T, subject, email string, emailVerified bool) (*TokenResponse, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	start, err := f.service.StartLogin(ctx, testProvider)
+	if err != nil {
+		t.Fatalf("StartLogin() error = %v", err)
+	}
+
+	authURL, err := url.Parse(start.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("invalid authorization URL: %v", err)
+	}
+	query := authURL.Query()
+
+	claims := f.server.Claims(subject, query.Get("nonce"))
+	claims["email"] = email
+	claims["email_verified"] = emailVerified
+	code := f.server.IssueCode(claims, query.Get("code_challenge"))
+
+	tokens, _, err := f.service.FinishLogin(ctx, testProvider, &OIDCCallbackRequest{Code: code, State: start.State}, ClientInfo{})
+	return tokens, err
+}
+
+func verifiedUser(email, status string) db.User {
+	return db.User{Name: "Ann", Email: email, PasswordHash: "hash", Status: status, EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+}
+
+func TestOIDCLoginUsesLinkedIdentity(t *testing.T) {
+	f := newOIDCFixture(t, false)
+	user := f.userRepo.add(verifiedUser("ann@example.com", UserStatusActive))
+	f.oidcRepo.identities = append(f.oidcRepo.identities, db.UserIdentity{ID: 1, UserID: user.ID, Provider: testProvider, Subject: "sub-1"})
+
+	// The subject decides, even when the provider reports another address
+	tokens, err := f.login(t, "sub-1", "ann@elsewhere.example", false)
+	if err != nil {
+		t.Fatalf("login error = %v", err)
+	}
+	if tokens.User.ID != int(user.ID) {
+		t.Errorf("logged in user %d, want %d", tokens.User.ID, user.ID)
+	}
+}
+
+func TestOIDCLoginLinksVerifiedUser(t *testing.T) {
+	f := newOIDCFixture(t, false)
+	user := f.userRepo.add(verifiedUser("ann@example.com", UserStatusActive))
+
+	tokens, err := f.login(t, "sub-1", "ann@example.com", true)
+	if err != nil {
+		t.Fatalf("login error = %v", err)
+	}
+	if tokens.User.ID != int(user.ID) {
+		t.Errorf("logged in user %d, want %d", tokens.User.ID, user.ID)
+	}
+	if f.userRepo.users[int(user.ID)].PasswordHash != "hash" {
+		t.Error("linking a verified account must keep its password")
+	}
+
+	identity, err := f.oidcRepo.GetIdentity(context.Background(), testProvider, "sub-1")
+	if err != nil || identity.UserID != user.ID {
+		t.Errorf("identity = %+v, %v, want one linked to user %d", identity, err, user.ID)
+	}
+}
+
+func TestOIDCLoginRefusesUnverifiedEmail(t *testing.T) {
+	f := newOIDCFixture(t, true)
+	f.userRepo.add(verifiedUser("ann@example.com", UserStatusActive))
+
+	if _, err := f.login(t, "sub-1", "ann@example.com", false); !errors.Is(err, ErrOIDCEmailNotVerified) {
+		t.Fatalf("login error = %v, want ErrOIDCEmailNotVerified", err)
+	}
+	if len(f.oidcRepo.identities) != 0 {
+		t.Error("an unverified provider email must not be linked")
+	}
+}
+
+func TestOIDCLoginRefusesUserWithUnverifiedEmail(t *testing.T) {
+	f := newOIDCFixture(t, true)
+	// e.g. created by an admin, or whose email was changed by an admin
+	f.userRepo.add(db.User{Name: "Ann", Email: "ann@example.com", PasswordHash: "hash", Status: UserStatusActive})
+
+	if _, err := f.login(t, "sub-1", "ann@example.com", true); !errors.Is(err, ErrOIDCAccountNotVerified) {
+		t.Fatalf("login error = %v, want ErrOIDCAccountNotVerified", err)
+	}
+	if len(f.oidcRepo.identities) != 0 {
+		t.Error("an account with an unverified email must not be linked")
+	}
+}
+
+func TestOIDCLoginClaimsPendingUser(t *testing.T) {
+	f := newOIDCFixture(t, false)
+	user := f.userRepo.add(db.User{Name: "Ann", Email: "ann@example.com", PasswordHash: "squatter", Status: UserStatusPending})
+
+	tokens, err := f.login(t, "sub-1", "ann@example.com", true)
+	if err != nil {
+		t.Fatalf("login error = %v", err)
+	}
+	if tokens.User.ID != int(user.ID) {
+		t.Errorf("logged in user %d, want %d", tokens.User.ID, user.ID)
+	}
+
+	claimed := f.userRepo.users[int(user.ID)]
+	if claimed.Status != UserStatusActive || !claimed.EmailVerifiedAt.Valid {
+		t.Errorf("claimed user has status %q and verified %v, want active and verified", claimed.Status, claimed.EmailVerifiedAt.Valid)
+	}
+	if claimed.PasswordHash == "squatter" {
+		t.Error("the password of a claimed pending account must be replaced")
+	}
+}
+
+func TestOIDCLoginSignUp(t *testing.T) {
+	f := newOIDCFixture(t, false)
+	if _, err := f.login(t, "sub-1", "new@example.com", true); !errors.Is(err, ErrOIDCSignUpDisabled) {
+		t.Fatalf("login error = %v, want ErrOIDCSignUpDisabled", err)
+	}
+	if len(f.userRepo.users) != 0 {
+		t.Error("no user may be created while sign up is disabled")
+	}
+
+	f = newOIDCFixture(t, true)
+	tokens, err := f.login(t, "sub-1", "new@example.com", true)
+	if err != nil {
+		t.Fatalf("login error = %v", err)
+	}
+
+	created := f.userRepo.users[tokens.User.ID]
+	if created.Email != "new@example.com" || created.Status != UserStatusActive || !created.EmailVerifiedAt.Valid {
+		t.Errorf("created user = %+v, want an active, verified new@example.com", created)
+	}
+}
+
+func TestOIDCLoginStateWorksOnce(t *testing.T) {
+	f := newOIDCFixture(t, true)
+	ctx := context.Background()
+
+	start, err := f.service.StartLogin(ctx, testProvider)
+	if err != nil {
+		t.Fatalf("StartLogin() error = %v", err)
+	}
+
+	// The first attempt consumes the state even though the code is bad
+	_, _, err = f.service.FinishLogin(ctx, testProvider, &OIDCCallbackRequest{Code: "bogus", State: start.State}, ClientInfo{})
+	if !errors.Is(err, ErrOIDCLoginFailed) {
+		t.Fatalf("FinishLogin() error = %v, want ErrOIDCLoginFailed", err)
+	}
+
+	_, _, err = f.service.FinishLogin(ctx, testProvider, &OIDCCallbackRequest{Code: "bogus", State: start.State}, ClientInfo{})
+	if !errors.Is(err, ErrInvalidOIDCState) {
+		t.Fatalf("FinishLogin() with a used state error = %v, want ErrInvalidOIDCState", err)
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"orchid_be/internal/config"
+	"orchid_be/internal/mailer"
+	"orchid_be/internal/repository"
+	"orchid_be/internal/utils"
+)
+
+var (
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	ErrPasswordUnchanged = errors.New("new password must be different from the current password")
+)
+
+type PasswordService interface {
+	RequestPasswordReset(ctx context.Context, req *ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req *ResetPasswordRequest) error
+	ChangePassword(ctx context.Context, userID, currentSessionID int, req *ChangePasswordRequest) error
+}
+
+type passwordService struct {
+	*BaseService
+	userRepo          repository.UserRepository
+	passwordResetRepo repository.PasswordResetRepository
+	refreshTokenRepo  repository.RefreshTokenRepository
+	sessionRepo       repository.SessionRepository
+	passwordPolicy    *utils.PasswordPolicy
+	mailer            mailer.Mailer
+	authConfig        config.AuthConfig
+}
+
+func NewPasswordService(userRepo repository.UserRepository, passwordResetRepo repository.PasswordResetRepository, refreshTokenRepo repository.RefreshTokenRepository, sessionRepo repository.SessionRepository, passwordPolicy *utils.PasswordPolicy, mail mailer.Mailer, authConfig config.AuthConfig) PasswordService {
+	return &passwordService{
+		BaseService:       NewBaseService(),
+		userRepo:          userRepo,
+		passwordResetRepo: passwordResetRepo,
+		refreshTokenRepo:  refreshTokenRepo,
+		sessionRepo:       sessionRepo,
+		passwordPolicy:    passwordPolicy,
+		mailer:            mail,
+		authConfig:        authConfig,
+	}
+}
+
+// RequestPasswordReset emails a reset link when the account exists. It never
+// returns an error for unknown addresses so callers cannot probe for accounts.
+func (s *passwordService) RequestPasswordReset(ctx context.Context, req *ForgotPasswordRequest) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		return nil
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	// Only the most recent link stays valid
+	if err := s.passwordResetRepo.InvalidateAllForUser(ctx, int(user.ID)); err != nil {
+		return err
+	}
+
+	if _, err := s.passwordResetRepo.Create(ctx, int(user.ID), utils.HashToken(token), time.Now().Add(s.authConfig.PasswordResetTTL)); err != nil {
+		return err
+	}
+
+	link := s.authConfig.FrontendURL + "/reset-password?token=" + url.QueryEscape(token)
+
+	// Sending takes long enough to tell known addresses from unknown ones
+	mailer.SendInBackground(ctx, s.mailer, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n\n%s\n\nThe link expires in %s and can only be used once. If you did not ask for a reset, you can ignore this email.\n",
+			user.Name, link, s.authConfig.PasswordResetTTL),
+	})
+
+	return nil
+}
+
+func (s *passwordService) ResetPassword(ctx context.Context, req *ResetPasswordRequest) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	resetToken, err := s.passwordResetRepo.GetByHash(ctx, utils.HashToken(req.Token))
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	if resetToken.UsedAt.Valid || time.Now().After(resetToken.ExpiresAt) {
+		return ErrInvalidToken
+	}
+
+	userID := int(resetToken.UserID)
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	// Check the policy before consuming the token so the user can retry
+	if err := s.passwordPolicy.Validate(req.Password, user.Email); err != nil {
+		return err
+	}
+
+	used, err := s.passwordResetRepo.MarkUsed(ctx, int(resetToken.ID))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidToken
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if _, err := s.userRepo.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	// Whoever knew the old password must not keep a session
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	// The reset proves ownership of the account, so lift any lockout
+	if err := s.userRepo.ResetFailedLogins(ctx, userID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ChangePassword keeps the caller's session, given by currentSessionID, and
+// signs out all others.
+func (s *passwordService) ChangePassword(ctx context.Context, userID, currentSessionID int, req *ChangePasswordRequest) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := utils.CheckPassword(req.CurrentPassword, user.PasswordHash); err != nil {
+		return ErrIncorrectPassword
+	}
+
+	if req.NewPassword == req.CurrentPassword {
+		return ErrPasswordUnchanged
+	}
+
+	if err := s.passwordPolicy.Validate(req.NewPassword, user.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if _, err := s.userRepo.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		return fmt.Errorf("failed to change password: %w", err)
+	}
+
+	// Sign out other devices; they have to log in with the new password
+	keepFamilyID := ""
+	if currentSessionID != 0 {
+		session, err := s.sessionRepo.GetByID(ctx, currentSessionID)
+		if err == nil && int(session.UserID) == userID {
+			keepFamilyID = session.FamilyID
+		}
+	}
+
+	if err := s.sessionRepo.RevokeOthers(ctx, userID, keepFamilyID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	// A pending reset link must not undo the change
+	if err := s.passwordResetRepo.InvalidateAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+// Billing intervals of a plan.
+const (
+	PlanIntervalMonth = "month"
+	PlanIntervalYear  = "year"
+)
+
+// Subscription statuses.
+const (
+	SubscriptionStatusActive   = "active"
+	SubscriptionStatusCanceled = "canceled"
+)
+
+// PlanResponse describes a plan. Limits maps a limit name such as
+// "api_calls" to its maximum; a name that is missing is unlimited.
+type PlanResponse struct {
+	ID            int            `json:"id"`
+	Name          string         `json:"name"`
+	Description   string         `json:"description"`
+	Price         string         `json:"price" example:"29.00"`
+	Interval      string         `json:"interval" example:"month"`
+	SupportMonths int            `json:"support_months"`
+	UpdateMonths  int            `json:"update_months"`
+	Limits        map[string]int `json:"limits"`
+}
+
+type SubscriptionPlanRequest struct {
+	PlanID int `json:"plan_id" validate:"required,min=1"`
+}
+
+// CancelSubscriptionRequest ends the subscription at the end of the current
+// period, or right away when Immediately is set.
+type CancelSubscriptionRequest struct {
+	Immediately bool `json:"immediately"`
+}
+
+type SubscriptionResponse struct {
+	ID                 int           `json:"id"`
+	Plan               *PlanResponse `json:"plan"`
+	Status             string        `json:"status"`
+	CurrentPeriodStart time.Time     `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time     `json:"current_period_end"`
+	CancelAtPeriodEnd  bool          `json:"cancel_at_period_end"`
+	CanceledAt         *time.Time    `json:"canceled_at,omitempty"`
+	CreatedAt          time.Time     `json:"created_at"`
+}
+
+// ProrationResponse breaks down a plan change. Credit is the unused part of
+// the current plan, Charge what the new plan costs until the end of the
+// period and AmountDue the difference, negative when the subscriber is owed
+// money. A change to a plan with another interval starts a new period.
+type ProrationResponse struct {
+	FromPlanID  int       `json:"from_plan_id"`
+	ToPlanID    int       `json:"to_plan_id"`
+	Credit      string    `json:"credit" example:"19.33"`
+	Charge      string    `json:"charge" example:"66.00"`
+	AmountDue   string    `json:"amount_due" example:"46.67"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+}
+
+type PlanChangeResponse struct {
+	Subscription *SubscriptionResponse `json:"subscription"`
+	Proration    *ProrationResponse    `json:"proration"`
+}
+
+// ToPlanResponse takes the limits of the plan, rows of other plans are
+// ignored.
+func ToPlanResponse(plan db.Plan, limits []db.PlanLimit) *PlanResponse {
+	resp := &PlanResponse{
+		ID:            int(plan.ID),
+		Name:          plan.Name,
+		Description:   plan.Description,
+		Price:         plan.Price,
+		Interval:      plan.BillingInterval,
+		SupportMonths: int(plan.SupportMonths),
+		UpdateMonths:  int(plan.UpdateMonths),
+		Limits:        make(map[string]int),
+	}
+
+	for _, limit := range limits {
+		if limit.PlanID == plan.ID {
+			resp.Limits[limit.Name] = int(limit.MaxValue)
+		}
+	}
+
+	return resp
+}
+
+func ToSubscriptionResponse(subscription db.Subscription, plan *PlanResponse) *SubscriptionResponse {
+	resp := &SubscriptionResponse{
+		ID:                 int(subscription.ID),
+		Plan:               plan,
+		Status:             subscription.Status,
+		CurrentPeriodStart: subscription.CurrentPeriodStart,
+		CurrentPeriodEnd:   subscription.CurrentPeriodEnd,
+		CancelAtPeriodEnd:  subscription.CancelAtPeriodEnd,
+	}
+
+	if subscription.CanceledAt.Valid {
+		resp.CanceledAt = &subscription.CanceledAt.Time
+	}
+
+	if subscription.CreatedAt.Valid {
+		resp.CreatedAt = subscription.CreatedAt.Time
+	}
+
+	return resp
+}
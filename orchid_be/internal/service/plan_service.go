@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"orchid_be/internal/repository"
+)
+
+var ErrPlanNotFound = errors.New("plan not found")
+
+type PlanService interface {
+	GetPlans(ctx context.Context) ([]*PlanResponse, error)
+	GetPlanByID(ctx context.Context, id int) (*PlanResponse, error)
+}
+
+type planService struct {
+	*BaseService
+	planRepo repository.PlanRepository
+}
+
+func NewPlanService(planRepo repository.PlanRepository) PlanService {
+	return &planService{
+		BaseService: NewBaseService(),
+		planRepo:    planRepo,
+	}
+}
+
+// GetPlans lists the plans open for subscription.
+func (s *planService) GetPlans(ctx context.Context) ([]*PlanResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	plans, err := s.planRepo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := s.planRepo.ListLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	planResponses := make([]*PlanResponse, len(plans))
+	for i, plan := range plans {
+		planResponses[i] = ToPlanResponse(plan, limits)
+	}
+
+	return planResponses, nil
+}
+
+// GetPlanByID also returns retired plans, existing subscriptions may still
+// be on one.
+func (s *planService) GetPlanByID(ctx context.Context, id int) (*PlanResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	plan, err := s.planRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrPlanNotFound
+	}
+
+	limits, err := s.planRepo.GetLimits(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToPlanResponse(plan, limits), nil
+}
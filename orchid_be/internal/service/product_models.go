@@ -0,0 +1,150 @@
+package service
+
+import (
+	"time"
+
+	"orchid_be/internal/db"
+	"orchid_be/internal/utils"
+)
+
+// Price and discount are decimal strings such as "149.90" so amounts are never
+// rounded through a float. Discount is a percentage from 0 to 100 and defaults
+// to 0. Category and technology are ids from /api/products/options.
+type CreateProductRequest struct {
+	Name         string `json:"name" validate:"required,max=255"`
+	CategoryID   int    `json:"category_id" validate:"required,min=1" options:"categories"`
+	TechnologyID int    `json:"technology_id" validate:"required,min=1" options:"technologies"`
+	Description  string `json:"description,omitempty"`
+	Price        string `json:"price" validate:"required" format:"decimal" example:"149.90"`
+	Discount     string `json:"discount,omitempty" format:"decimal" example:"10"`
+}
+
+// UpdateProductRequest changes only the fields that are present.
+type UpdateProductRequest struct {
+	Name         *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	CategoryID   *int    `json:"category_id,omitempty" validate:"omitempty,min=1" options:"categories"`
+	TechnologyID *int    `json:"technology_id,omitempty" validate:"omitempty,min=1" options:"technologies"`
+	Description  *string `json:"description,omitempty"`
+	Price        *string `json:"price,omitempty" format:"decimal" example:"149.90"`
+	Discount     *string `json:"discount,omitempty" format:"decimal" example:"10"`
+}
+
+// SchedulePriceChangeRequest sets a new price, discount or both from
+// starts_at on. With ends_at the change is a time-boxed promotion and the
+// previous value applies again afterwards.
+type SchedulePriceChangeRequest struct {
+	Price    *string    `json:"price,omitempty" example:"129.90"`
+	Discount *string    `json:"discount,omitempty" example:"20"`
+	StartsAt time.Time  `json:"starts_at" validate:"required"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
+
+type ProductResponse struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	CategoryID   int       `json:"category_id"`
+	Category     string    `json:"category"`
+	TechnologyID int       `json:"technology_id"`
+	Technology   string    `json:"technology"`
+	Description  string    `json:"description"`
+	Price        string    `json:"price" format:"decimal" example:"149.90"`
+	Discount     string    `json:"discount" format:"decimal" example:"10.00"`
+	SalePrice    string    `json:"sale_price" format:"decimal" example:"134.91"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ProductEntityMeta describes /api/products for the metadata endpoint.
+var ProductEntityMeta = DescribeEntity[CreateProductRequest, UpdateProductRequest, ProductResponse](EntityConfig{
+	Name:        "products",
+	DisplayName: "Product",
+	DefaultSort: "created_at",
+	DefaultDesc: true,
+	Permissions: EntityPermissions{
+		Create: PermissionProductsCreate,
+		Update: PermissionProductsUpdate,
+		Delete: PermissionProductsDelete,
+	},
+})
+
+// OptionResponse is one entry of a select box.
+type OptionResponse struct {
+	Value int    `json:"value"`
+	Label string `json:"label"`
+}
+
+type ProductOptionsResponse struct {
+	Categories   []*OptionResponse `json:"categories"`
+	Technologies []*OptionResponse `json:"technologies"`
+}
+
+// ProductPriceResponse is one entry of a product's price history. Price or
+// discount is omitted when the change left it as it was.
+type ProductPriceResponse struct {
+	ID        int        `json:"id"`
+	ProductID int        `json:"product_id"`
+	Price     *string    `json:"price,omitempty" example:"129.90"`
+	Discount  *string    `json:"discount,omitempty" example:"20.00"`
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	CreatedBy *int       `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ToProductResponse includes the category and technology names; pass the
+// rows the product references.
+func ToProductResponse(product db.Product, category db.Category, technology db.Technology) *ProductResponse {
+	resp := &ProductResponse{
+		ID:           int(product.ID),
+		Name:         product.Name,
+		CategoryID:   int(product.CategoryID),
+		Category:     category.Name,
+		TechnologyID: int(product.TechnologyID),
+		Technology:   technology.Name,
+		Description:  product.Description,
+		Price:        product.Price,
+		Discount:     product.Discount,
+		SalePrice:    utils.ApplyDiscount(product.Price, product.Discount, 2),
+	}
+
+	if product.CreatedAt.Valid {
+		resp.CreatedAt = product.CreatedAt.Time
+	}
+
+	if product.UpdatedAt.Valid {
+		resp.UpdatedAt = product.UpdatedAt.Time
+	}
+
+	return resp
+}
+
+func ToProductPriceResponse(price db.ProductPrice) *ProductPriceResponse {
+	resp := &ProductPriceResponse{
+		ID:        int(price.ID),
+		ProductID: int(price.ProductID),
+		StartsAt:  price.StartsAt,
+	}
+
+	if price.Price.Valid {
+		resp.Price = &price.Price.String
+	}
+
+	if price.Discount.Valid {
+		resp.Discount = &price.Discount.String
+	}
+
+	if price.EndsAt.Valid {
+		resp.EndsAt = &price.EndsAt.Time
+	}
+
+	if price.CreatedBy.Valid {
+		createdBy := int(price.CreatedBy.Int32)
+		resp.CreatedBy = &createdBy
+	}
+
+	if price.CreatedAt.Valid {
+		resp.CreatedAt = price.CreatedAt.Time
+	}
+
+	return resp
+}
@@ -0,0 +1,459 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+	"orchid_be/internal/repository"
+	"orchid_be/internal/utils"
+)
+
+var (
+	ErrProductNotFound      = errors.New("product not found")
+	ErrInvalidPrice         = errors.New("price must be a non-negative amount with at most 10 digits before and 2 after the decimal point")
+	ErrInvalidDiscount      = errors.New("discount must be a percentage between 0 and 100 with at most 2 decimal places")
+	ErrEmptyPriceChange     = errors.New("a price change needs a price, a discount or both")
+	ErrInvalidPriceSchedule = errors.New("starts_at must not be in the past and ends_at must be after starts_at")
+	ErrPriceChangeNotFound  = errors.New("no pending price change with this id")
+	ErrCategoryNotFound     = errors.New("category not found")
+	ErrTechnologyNotFound   = errors.New("technology not found")
+)
+
+// Limits of the NUMERIC(12, 2) price and NUMERIC(5, 2) discount columns.
+const (
+	productPriceDigits    = 10
+	productDiscountDigits = 3
+	productScale          = 2
+	productMaxDiscount    = "100"
+)
+
+// A scheduled change may start this much before the server's clock to allow
+// for clients whose clock runs slightly behind.
+const priceScheduleClockSkew = time.Minute
+
+type ProductService interface {
+	CreateProduct(ctx context.Context, req *CreateProductRequest) (*ProductResponse, error)
+	GetProductByID(ctx context.Context, id int, at time.Time) (*ProductResponse, error)
+	GetAllProducts(ctx context.Context, page, limit int, at time.Time) ([]*ProductResponse, int, error)
+	UpdateProduct(ctx context.Context, id int, req *UpdateProductRequest) (*ProductResponse, error)
+	DeleteProduct(ctx context.Context, id int) error
+	GetOptions(ctx context.Context) (*ProductOptionsResponse, error)
+	SchedulePriceChange(ctx context.Context, id int, req *SchedulePriceChangeRequest) (*ProductPriceResponse, error)
+	GetPriceHistory(ctx context.Context, id, page, limit int) ([]*ProductPriceResponse, int, error)
+	CancelPriceChange(ctx context.Context, id, priceID int) error
+}
+
+type productService struct {
+	*BaseService
+	productRepo    repository.ProductRepository
+	categoryRepo   repository.CategoryRepository
+	technologyRepo repository.TechnologyRepository
+}
+
+func NewProductService(productRepo repository.ProductRepository, categoryRepo repository.CategoryRepository, technologyRepo repository.TechnologyRepository) ProductService {
+	return &productService{
+		BaseService:    NewBaseService(),
+		productRepo:    productRepo,
+		categoryRepo:   categoryRepo,
+		technologyRepo: technologyRepo,
+	}
+}
+
+func (s *productService) CreateProduct(ctx context.Context, req *CreateProductRequest) (*ProductResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	discount := req.Discount
+	if discount == "" {
+		discount = "0"
+	}
+
+	fields := repository.ProductFields{
+		Name:         req.Name,
+		CategoryID:   req.CategoryID,
+		TechnologyID: req.TechnologyID,
+		Description:  req.Description,
+		Price:        req.Price,
+		Discount:     discount,
+	}
+	if err := normalizeProductAmounts(&fields); err != nil {
+		return nil, err
+	}
+
+	category, technology, err := s.getLookups(ctx, fields.CategoryID, fields.TechnologyID)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.Create(ctx, fields, actorID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create product: %w", err)
+	}
+
+	return ToProductResponse(product, category, technology), nil
+}
+
+// GetProductByID returns the product with the price and discount in effect at
+// the given time, or now when at is zero.
+func (s *productService) GetProductByID(ctx context.Context, id int, at time.Time) (*ProductResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	product, err := s.productRepo.GetPriced(ctx, id, at)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	category, technology, err := s.getLookups(ctx, int(product.CategoryID), int(product.TechnologyID))
+	if err != nil {
+		return nil, err
+	}
+
+	return ToProductResponse(effectiveProduct(product), category, technology), nil
+}
+
+func (s *productService) GetAllProducts(ctx context.Context, page, limit int, at time.Time) ([]*ProductResponse, int, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	offset := (page - 1) * limit
+
+	products, err := s.productRepo.GetAllPriced(ctx, at, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	total, err := s.productRepo.Count(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count products: %w", err)
+	}
+
+	// Both lookup tables are short, reading them whole is cheaper than a query
+	// per product.
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	categoriesByID := make(map[int32]db.Category, len(categories))
+	for _, category := range categories {
+		categoriesByID[category.ID] = category
+	}
+
+	technologies, err := s.technologyRepo.List(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	technologiesByID := make(map[int32]db.Technology, len(technologies))
+	for _, technology := range technologies {
+		technologiesByID[technology.ID] = technology
+	}
+
+	productResponses := make([]*ProductResponse, len(products))
+	for i, product := range products {
+		productResponses[i] = ToProductResponse(effectiveProduct(product), categoriesByID[product.CategoryID], technologiesByID[product.TechnologyID])
+	}
+
+	return productResponses, total, nil
+}
+
+// UpdateProduct saves the given fields. A price or discount that differs from
+// the one in effect now is recorded in the price history as a change starting
+// immediately.
+func (s *productService) UpdateProduct(ctx context.Context, id int, req *UpdateProductRequest) (*ProductResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	now := time.Now()
+	product, err := s.productRepo.GetPriced(ctx, id, now)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	fields := repository.ProductFields{
+		Name:         product.Name,
+		CategoryID:   int(product.CategoryID),
+		TechnologyID: int(product.TechnologyID),
+		Description:  product.Description,
+		Price:        product.Price,
+		Discount:     product.Discount,
+	}
+
+	if req.Name != nil {
+		fields.Name = *req.Name
+	}
+	if req.CategoryID != nil {
+		fields.CategoryID = *req.CategoryID
+	}
+	if req.TechnologyID != nil {
+		fields.TechnologyID = *req.TechnologyID
+	}
+	if req.Description != nil {
+		fields.Description = *req.Description
+	}
+	if req.Price != nil {
+		fields.Price = *req.Price
+	}
+	if req.Discount != nil {
+		fields.Discount = *req.Discount
+	}
+
+	if err := normalizeProductAmounts(&fields); err != nil {
+		return nil, err
+	}
+
+	change := repository.PriceChange{StartsAt: now, CreatedBy: actorID(ctx)}
+	if req.Price != nil && fields.Price != product.EffectivePrice {
+		change.Price = fields.Price
+	}
+	if req.Discount != nil && fields.Discount != product.EffectiveDiscount {
+		change.Discount = fields.Discount
+	}
+
+	var priceChange *repository.PriceChange
+	if change.Price != "" || change.Discount != "" {
+		priceChange = &change
+	}
+
+	category, technology, err := s.getLookups(ctx, fields.CategoryID, fields.TechnologyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.productRepo.Update(ctx, id, fields, priceChange); err != nil {
+		return nil, fmt.Errorf("failed to update product: %w", err)
+	}
+
+	updatedProduct, err := s.productRepo.GetPriced(ctx, id, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	return ToProductResponse(effectiveProduct(updatedProduct), category, technology), nil
+}
+
+func (s *productService) DeleteProduct(ctx context.Context, id int) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	deleted, err := s.productRepo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrProductNotFound
+	}
+
+	return nil
+}
+
+// GetOptions lists every category and technology as select options for the
+// product form.
+func (s *productService) GetOptions(ctx context.Context) (*ProductOptionsResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	technologies, err := s.technologyRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &ProductOptionsResponse{
+		Categories:   make([]*OptionResponse, len(categories)),
+		Technologies: make([]*OptionResponse, len(technologies)),
+	}
+	for i, category := range categories {
+		options.Categories[i] = &OptionResponse{Value: int(category.ID), Label: category.Name}
+	}
+	for i, technology := range technologies {
+		options.Technologies[i] = &OptionResponse{Value: int(technology.ID), Label: technology.Name}
+	}
+
+	return options, nil
+}
+
+// SchedulePriceChange plans a new price, discount or both. Without ends_at
+// the change stays until a later one replaces it; with ends_at it is a
+// time-boxed promotion after which the previous value applies again.
+func (s *productService) SchedulePriceChange(ctx context.Context, id int, req *SchedulePriceChangeRequest) (*ProductPriceResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if req.Price == nil && req.Discount == nil {
+		return nil, ErrEmptyPriceChange
+	}
+
+	change := repository.PriceChange{
+		StartsAt:  req.StartsAt,
+		CreatedBy: actorID(ctx),
+	}
+	if change.StartsAt.Before(time.Now().Add(-priceScheduleClockSkew)) {
+		return nil, ErrInvalidPriceSchedule
+	}
+	if req.EndsAt != nil {
+		if !req.EndsAt.After(req.StartsAt) {
+			return nil, ErrInvalidPriceSchedule
+		}
+		change.EndsAt = *req.EndsAt
+	}
+
+	if req.Price != nil {
+		price, err := utils.NormalizeDecimal(*req.Price, productPriceDigits, productScale)
+		if err != nil {
+			return nil, ErrInvalidPrice
+		}
+		change.Price = price
+	}
+	if req.Discount != nil {
+		discount, err := normalizeDiscount(*req.Discount)
+		if err != nil {
+			return nil, err
+		}
+		change.Discount = discount
+	}
+
+	if _, err := s.productRepo.GetByID(ctx, id); err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	price, err := s.productRepo.SchedulePriceChange(ctx, id, change)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToProductPriceResponse(price), nil
+}
+
+// GetPriceHistory lists every price change of the product, scheduled ones
+// included, latest start first.
+func (s *productService) GetPriceHistory(ctx context.Context, id, page, limit int) ([]*ProductPriceResponse, int, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.productRepo.GetByID(ctx, id); err != nil {
+		return nil, 0, ErrProductNotFound
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	prices, err := s.productRepo.GetPriceHistory(ctx, id, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.productRepo.CountPriceHistory(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	priceResponses := make([]*ProductPriceResponse, len(prices))
+	for i, price := range prices {
+		priceResponses[i] = ToProductPriceResponse(price)
+	}
+
+	return priceResponses, total, nil
+}
+
+func (s *productService) CancelPriceChange(ctx context.Context, id, priceID int) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	cancelled, err := s.productRepo.CancelPriceChange(ctx, id, priceID)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return ErrPriceChangeNotFound
+	}
+
+	return nil
+}
+
+// getLookups loads the category and technology a product points at, which
+// also checks that both exist before a write.
+func (s *productService) getLookups(ctx context.Context, categoryID, technologyID int) (db.Category, db.Technology, error) {
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return db.Category{}, db.Technology{}, ErrCategoryNotFound
+	}
+
+	technology, err := s.technologyRepo.GetByID(ctx, technologyID)
+	if err != nil {
+		return db.Category{}, db.Technology{}, ErrTechnologyNotFound
+	}
+
+	return category, technology, nil
+}
+
+// effectiveProduct returns the product with the price and discount it was
+// read for in place of the stored ones.
+func effectiveProduct(product repository.PricedProduct) db.Product {
+	effective := product.Product
+	effective.Price = product.EffectivePrice
+	effective.Discount = product.EffectiveDiscount
+	return effective
+}
+
+// actorID returns the id of the user making the request, or 0 outside of a
+// request.
+func actorID(ctx context.Context) int {
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		return principal.User.ID
+	}
+	return 0
+}
+
+// normalizeProductAmounts checks price and discount against the column limits
+// and rewrites them with two decimal places, so bad input is a 400 rather than
+// a database error.
+func normalizeProductAmounts(fields *repository.ProductFields) error {
+	price, err := utils.NormalizeDecimal(fields.Price, productPriceDigits, productScale)
+	if err != nil {
+		return ErrInvalidPrice
+	}
+
+	discount, err := normalizeDiscount(fields.Discount)
+	if err != nil {
+		return err
+	}
+
+	fields.Price = price
+	fields.Discount = discount
+	return nil
+}
+
+func normalizeDiscount(value string) (string, error) {
+	discount, err := utils.NormalizeDecimal(value, productDiscountDigits, productScale)
+	if err != nil || utils.CompareDecimal(discount, productMaxDiscount) > 0 {
+		return "", ErrInvalidDiscount
+	}
+	return discount, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"orchid_be/internal/config"
+	"orchid_be/internal/db"
+	"orchid_be/internal/mailer"
+	"orchid_be/internal/repository"
+	"orchid_be/internal/utils"
+)
+
+type RegistrationService interface {
+	Register(ctx context.Context, req *RegisterRequest) (*UserResponse, error)
+	VerifyEmail(ctx context.Context, req *VerifyEmailRequest) (*UserResponse, error)
+	ResendVerification(ctx context.Context, req *ResendVerificationRequest) error
+}
+
+type registrationService struct {
+	*BaseService
+	userRepo       repository.UserRepository
+	jwtManager     *utils.JWTManager
+	passwordPolicy *utils.PasswordPolicy
+	verification   *verificationMailer
+	authConfig     config.AuthConfig
+}
+
+// verificationMailer sends the link that verifies a user's current email
+// address, after sign up and after the address was changed. The email goes
+// out in the background, so a slow mail server does not hold up the request
+// and the response time does not reveal whether a link was sent.
+type verificationMailer struct {
+	jwtManager *utils.JWTManager
+	mailer     mailer.Mailer
+	authConfig config.AuthConfig
+}
+
+func newVerificationMailer(jwtManager *utils.JWTManager, mail mailer.Mailer, authConfig config.AuthConfig) *verificationMailer {
+	return &verificationMailer{
+		jwtManager: jwtManager,
+		mailer:     mail,
+		authConfig: authConfig,
+	}
+}
+
+func NewRegistrationService(userRepo repository.UserRepository, jwtManager *utils.JWTManager, passwordPolicy *utils.PasswordPolicy, mail mailer.Mailer, authConfig config.AuthConfig) RegistrationService {
+	return &registrationService{
+		BaseService:    NewBaseService(),
+		userRepo:       userRepo,
+		jwtManager:     jwtManager,
+		passwordPolicy: passwordPolicy,
+		verification:   newVerificationMailer(jwtManager, mail, authConfig),
+		authConfig:     authConfig,
+	}
+}
+
+func (s *registrationService) Register(ctx context.Context, req *RegisterRequest) (*UserResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.userRepo.GetByEmail(ctx, req.Email); err == nil {
+		return nil, ErrEmailAlreadyExists
+	}
+
+	if err := s.passwordPolicy.Validate(req.Password, req.Email); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user, err := s.userRepo.Create(ctx, req.Name, req.Email, hashedPassword, UserStatusPending, repository.UserProfile{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if err := s.verification.send(ctx, user); err != nil {
+		// The account exists now; the user can ask for a new link.
+		log.Printf("Failed to send verification email to %s: %v", user.Email, err)
+	}
+
+	return ToUserResponse(user), nil
+}
+
+func (s *registrationService) VerifyEmail(ctx context.Context, req *VerifyEmailRequest) (*UserResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	claims, err := s.jwtManager.Parse(req.Token)
+	if err != nil || claims.TokenType != TokenTypeEmailVerification {
+		return nil, ErrInvalidToken
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	// The link is only valid for the address it was sent to.
+	if user.Email != claims.Email {
+		return nil, ErrInvalidToken
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		return ToUserResponse(user), nil
+	}
+
+	status := user.Status
+	if status == UserStatusPending {
+		status = UserStatusActive
+	}
+
+	verifiedUser, err := s.userRepo.VerifyEmail(ctx, userID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	return ToUserResponse(verifiedUser), nil
+}
+
+func (s *registrationService) ResendVerification(ctx context.Context, req *ResendVerificationRequest) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	// Active accounts can be unverified too, e.g. when an admin created them,
+	// and need a link before their email can be linked to a provider.
+	if err != nil || user.EmailVerifiedAt.Valid {
+		// Do not reveal whether the email exists
+		return nil
+	}
+
+	if err := s.verification.send(ctx, user); err != nil {
+		log.Printf("Failed to send verification email to %s: %v", user.Email, err)
+	}
+
+	return nil
+}
+
+func (m *verificationMailer) send(ctx context.Context, user db.User) error {
+	now := time.Now()
+	token, err := m.jwtManager.Sign(&utils.Claims{
+		Subject:   strconv.Itoa(int(user.ID)),
+		Email:     user.Email,
+		TokenType: TokenTypeEmailVerification,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(m.authConfig.EmailVerificationTTL).Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sign verification token: %w", err)
+	}
+
+	link := m.authConfig.FrontendURL + "/verify-email?token=" + url.QueryEscape(token)
+
+	mailer.SendInBackground(ctx, m.mailer, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %s. If you did not expect this email, you can ignore it.\n",
+			user.Name, link, m.authConfig.EmailVerificationTTL),
+	})
+
+	return nil
+}
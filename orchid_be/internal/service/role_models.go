@@ -0,0 +1,99 @@
+package service
+
+import (
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+const RoleAdmin = "admin"
+
+const (
+	PermissionUsersList   = "users:list"
+	PermissionUsersRead   = "users:read"
+	PermissionUsersCreate = "users:create"
+	PermissionUsersUpdate = "users:update"
+	PermissionUsersDelete = "users:delete"
+	PermissionRolesRead   = "roles:read"
+	PermissionRolesManage = "roles:manage"
+
+	PermissionUsersResetTwoFactor = "users:reset_2fa"
+	PermissionUsersUnlock         = "users:unlock"
+
+	PermissionSessionsRead   = "sessions:read"
+	PermissionSessionsRevoke = "sessions:revoke"
+
+	PermissionUsersImpersonate   = "users:impersonate"
+	PermissionImpersonationsRead = "impersonations:read"
+
+	PermissionProductsCreate = "products:create"
+	PermissionProductsUpdate = "products:update"
+	PermissionProductsDelete = "products:delete"
+
+	PermissionCategoriesManage   = "categories:manage"
+	PermissionTechnologiesManage = "technologies:manage"
+
+	PermissionInventoryRead   = "inventory:read"
+	PermissionInventoryManage = "inventory:manage"
+
+	PermissionInvoicesRead   = "invoices:read"
+	PermissionInvoicesManage = "invoices:manage"
+
+	PermissionUsageRead = "usage:read"
+)
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name" validate:"required,max=100"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type AssignRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+type PermissionResponse struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type RoleResponse struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func ToPermissionResponse(permission db.Permission) *PermissionResponse {
+	return &PermissionResponse{
+		ID:          int(permission.ID),
+		Name:        permission.Name,
+		Description: permission.Description,
+	}
+}
+
+func ToRoleResponse(role db.Role, permissions []db.Permission) *RoleResponse {
+	resp := &RoleResponse{
+		ID:          int(role.ID),
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: make([]string, len(permissions)),
+	}
+
+	for i, permission := range permissions {
+		resp.Permissions[i] = permission.Name
+	}
+
+	if role.CreatedAt.Valid {
+		resp.CreatedAt = role.CreatedAt.Time
+	}
+
+	if role.UpdatedAt.Valid {
+		resp.UpdatedAt = role.UpdatedAt.Time
+	}
+
+	return resp
+}
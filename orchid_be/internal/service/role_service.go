@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"orchid_be/internal/db"
+	"orchid_be/internal/repository"
+)
+
+var (
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrRoleAlreadyExists = errors.New("role already exists")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrRoleProtected     = errors.New("the admin role cannot be deleted")
+	ErrLastAdmin         = errors.New("cannot remove the last admin")
+	ErrUserNotFound      = errors.New("user not found")
+)
+
+type RoleService interface {
+	GetAllRoles(ctx context.Context) ([]*RoleResponse, error)
+	CreateRole(ctx context.Context, req *CreateRoleRequest) (*RoleResponse, error)
+	DeleteRole(ctx context.Context, id int) error
+	GetAllPermissions(ctx context.Context) ([]*PermissionResponse, error)
+	GetUserRoles(ctx context.Context, userID int) ([]*RoleResponse, error)
+	AssignRole(ctx context.Context, userID int, req *AssignRoleRequest) ([]*RoleResponse, error)
+	RemoveRole(ctx context.Context, userID, roleID int) error
+	BootstrapAdmin(ctx context.Context, email string) error
+}
+
+type roleService struct {
+	*BaseService
+	roleRepo repository.RoleRepository
+	userRepo repository.UserRepository
+}
+
+func NewRoleService(roleRepo repository.RoleRepository, userRepo repository.UserRepository) RoleService {
+	return &roleService{
+		BaseService: NewBaseService(),
+		roleRepo:    roleRepo,
+		userRepo:    userRepo,
+	}
+}
+
+func (s *roleService) GetAllRoles(ctx context.Context) ([]*RoleResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	roles, err := s.roleRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+
+	return s.toRoleResponses(ctx, roles)
+}
+
+func (s *roleService) CreateRole(ctx context.Context, req *CreateRoleRequest) (*RoleResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.roleRepo.GetByName(ctx, req.Name); err == nil {
+		return nil, ErrRoleAlreadyExists
+	}
+
+	permissionIDs := make([]int, len(req.Permissions))
+	for i, name := range req.Permissions {
+		permission, err := s.roleRepo.GetPermissionByName(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPermission, name)
+		}
+		permissionIDs[i] = int(permission.ID)
+	}
+
+	role, err := s.roleRepo.Create(ctx, req.Name, req.Description, permissionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	permissions, err := s.roleRepo.GetRolePermissions(ctx, int(role.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	return ToRoleResponse(role, permissions), nil
+}
+
+func (s *roleService) DeleteRole(ctx context.Context, id int) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	role, err := s.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+
+	if role.Name == RoleAdmin {
+		return ErrRoleProtected
+	}
+
+	if err := s.roleRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	return nil
+}
+
+func (s *roleService) GetAllPermissions(ctx context.Context) ([]*PermissionResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	permissions, err := s.roleRepo.GetAllPermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+
+	responses := make([]*PermissionResponse, len(permissions))
+	for i, permission := range permissions {
+		responses[i] = ToPermissionResponse(permission)
+	}
+
+	return responses, nil
+}
+
+func (s *roleService) GetUserRoles(ctx context.Context, userID int) ([]*RoleResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	roles, err := s.roleRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toRoleResponses(ctx, roles)
+}
+
+func (s *roleService) AssignRole(ctx context.Context, userID int, req *AssignRoleRequest) ([]*RoleResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	role, err := s.roleRepo.GetByName(ctx, req.Role)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+
+	if err := s.roleRepo.AssignToUser(ctx, userID, int(role.ID)); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.roleRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toRoleResponses(ctx, roles)
+}
+
+func (s *roleService) RemoveRole(ctx context.Context, userID, roleID int) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+
+	// Keep at least one account able to manage roles
+	if role.Name == RoleAdmin {
+		removed, err := s.roleRepo.RemoveFromUserUnlessLast(ctx, userID, roleID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return ErrLastAdmin
+		}
+		return nil
+	}
+
+	if err := s.roleRepo.RemoveFromUser(ctx, userID, roleID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// BootstrapAdmin grants the admin role to an existing account so a fresh
+// install has someone who can assign roles.
+func (s *roleService) BootstrapAdmin(ctx context.Context, email string) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	role, err := s.roleRepo.GetByName(ctx, RoleAdmin)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+
+	return s.roleRepo.AssignToUser(ctx, int(user.ID), int(role.ID))
+}
+
+func (s *roleService) toRoleResponses(ctx context.Context, roles []db.Role) ([]*RoleResponse, error) {
+	responses := make([]*RoleResponse, len(roles))
+	for i, role := range roles {
+		permissions, err := s.roleRepo.GetRolePermissions(ctx, int(role.ID))
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = ToRoleResponse(role, permissions)
+	}
+
+	return responses, nil
+}
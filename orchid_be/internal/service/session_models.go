@@ -0,0 +1,45 @@
+package service
+
+import (
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+// ClientInfo describes the client a login or token refresh came from.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type SessionResponse struct {
+	ID         int        `json:"id"`
+	Device     string     `json:"device"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	Current    bool       `json:"current"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func ToSessionResponse(session db.Session, currentSessionID int) *SessionResponse {
+	resp := &SessionResponse{
+		ID:        int(session.ID),
+		Device:    session.Device,
+		UserAgent: session.UserAgent,
+		IPAddress: session.IpAddress,
+		Current:   currentSessionID != 0 && int(session.ID) == currentSessionID,
+		ExpiresAt: session.ExpiresAt,
+	}
+
+	if session.LastSeenAt.Valid {
+		resp.LastSeenAt = &session.LastSeenAt.Time
+	}
+
+	if session.CreatedAt.Valid {
+		resp.CreatedAt = session.CreatedAt.Time
+	}
+
+	return resp
+}
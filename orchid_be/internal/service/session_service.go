@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"orchid_be/internal/repository"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type SessionService interface {
+	GetSessions(ctx context.Context, userID, currentSessionID int) ([]*SessionResponse, error)
+	RevokeSession(ctx context.Context, userID, sessionID int) error
+}
+
+type sessionService struct {
+	*BaseService
+	sessionRepo repository.SessionRepository
+	userRepo    repository.UserRepository
+}
+
+func NewSessionService(sessionRepo repository.SessionRepository, userRepo repository.UserRepository) SessionService {
+	return &sessionService{
+		BaseService: NewBaseService(),
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// GetSessions lists the user's active sessions. currentSessionID marks the
+// session of the caller, if it is one of them.
+func (s *sessionService) GetSessions(ctx context.Context, userID, currentSessionID int) ([]*SessionResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	sessions, err := s.sessionRepo.GetActiveForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = ToSessionResponse(session, currentSessionID)
+	}
+
+	return responses, nil
+}
+
+// RevokeSession logs the session out. Its refresh token stops working and
+// access tokens issued for it are rejected from the next request on.
+func (s *sessionService) RevokeSession(ctx context.Context, userID, sessionID int) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	revoked, err := s.sessionRepo.Revoke(ctx, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/db"
+	"orchid_be/internal/repository"
+	"orchid_be/internal/utils"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("no active subscription")
+	ErrAlreadySubscribed    = errors.New("user already has an active subscription")
+	ErrSamePlan             = errors.New("subscription is already on this plan")
+	ErrSubscriptionChanged  = errors.New("subscription was changed by another request, try again")
+)
+
+// Scale of the NUMERIC(12, 2) amounts.
+const planScale = 2
+
+type SubscriptionService interface {
+	GetSubscription(ctx context.Context, userID int) (*SubscriptionResponse, error)
+	Subscribe(ctx context.Context, userID int, req *SubscriptionPlanRequest) (*SubscriptionResponse, error)
+	PreviewPlanChange(ctx context.Context, userID, planID int) (*ProrationResponse, error)
+	ChangePlan(ctx context.Context, userID int, req *SubscriptionPlanRequest) (*PlanChangeResponse, error)
+	Cancel(ctx context.Context, userID int, req *CancelSubscriptionRequest) (*SubscriptionResponse, error)
+}
+
+type subscriptionService struct {
+	*BaseService
+	subscriptionRepo repository.SubscriptionRepository
+	planRepo         repository.PlanRepository
+}
+
+func NewSubscriptionService(subscriptionRepo repository.SubscriptionRepository, planRepo repository.PlanRepository) SubscriptionService {
+	return &subscriptionService{
+		BaseService:      NewBaseService(),
+		subscriptionRepo: subscriptionRepo,
+		planRepo:         planRepo,
+	}
+}
+
+func (s *subscriptionService) GetSubscription(ctx context.Context, userID int) (*SubscriptionResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	subscription, err := s.getCurrent(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(ctx, subscription)
+}
+
+// Subscribe starts a subscription to the plan with a first period from now.
+func (s *subscriptionService) Subscribe(ctx context.Context, userID int, req *SubscriptionPlanRequest) (*SubscriptionResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	now := time.Now()
+	if _, err := s.getCurrent(ctx, userID, now); err == nil {
+		return nil, ErrAlreadySubscribed
+	} else if !errors.Is(err, ErrSubscriptionNotFound) {
+		return nil, err
+	}
+
+	plan, err := s.getActivePlan(ctx, req.PlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.subscriptionRepo.Create(ctx, userID, req.PlanID, now, addInterval(now, plan.BillingInterval))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	return s.toResponse(ctx, subscription)
+}
+
+// PreviewPlanChange returns what ChangePlan would bill without changing
+// anything.
+func (s *subscriptionService) PreviewPlanChange(ctx context.Context, userID, planID int) (*ProrationResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	now := time.Now()
+	subscription, err := s.getCurrent(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := s.preparePlanChange(ctx, subscription, planID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProrationResponse(subscription, change), nil
+}
+
+// ChangePlan upgrades or downgrades right away. The unused part of the
+// current plan is credited against the prorated price of the new one and the
+// change is recorded for billing.
+func (s *subscriptionService) ChangePlan(ctx context.Context, userID int, req *SubscriptionPlanRequest) (*PlanChangeResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	now := time.Now()
+	subscription, err := s.getCurrent(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := s.preparePlanChange(ctx, subscription, req.PlanID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, _, err := s.subscriptionRepo.ChangePlan(ctx, subscription, change)
+	if err != nil {
+		if errors.Is(err, repository.ErrSubscriptionChanged) {
+			return nil, ErrSubscriptionChanged
+		}
+		return nil, fmt.Errorf("failed to change plan: %w", err)
+	}
+
+	subscriptionResponse, err := s.toResponse(ctx, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PlanChangeResponse{
+		Subscription: subscriptionResponse,
+		Proration:    toProrationResponse(subscription, change),
+	}, nil
+}
+
+// Cancel stops the renewal so the subscription ends with the current
+// period, or ends it now when asked to. Nothing is refunded either way.
+func (s *subscriptionService) Cancel(ctx context.Context, userID int, req *CancelSubscriptionRequest) (*SubscriptionResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	now := time.Now()
+	subscription, err := s.getCurrent(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Immediately {
+		subscription, err = s.subscriptionRepo.Cancel(ctx, int(subscription.ID), now)
+	} else {
+		subscription, err = s.subscriptionRepo.SetCancelAtPeriodEnd(ctx, subscription, true)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrSubscriptionChanged) {
+			return nil, ErrSubscriptionChanged
+		}
+		return nil, fmt.Errorf("failed to cancel subscription: %w", err)
+	}
+
+	return s.toResponse(ctx, subscription)
+}
+
+// getCurrent returns the user's active subscription as of now.
+func (s *subscriptionService) getCurrent(ctx context.Context, userID int, now time.Time) (db.Subscription, error) {
+	subscription, err := s.subscriptionRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return db.Subscription{}, ErrSubscriptionNotFound
+	}
+
+	return settleSubscription(ctx, s.subscriptionRepo, s.planRepo, subscription, now)
+}
+
+func (s *subscriptionService) getActivePlan(ctx context.Context, id int) (db.Plan, error) {
+	plan, err := s.planRepo.GetByID(ctx, id)
+	if err != nil || !plan.IsActive {
+		return db.Plan{}, ErrPlanNotFound
+	}
+	return plan, nil
+}
+
+// preparePlanChange prorates by the time left in the period. Within the same
+// interval the period is kept and the new plan is charged for the rest of
+// it; a change between monthly and yearly starts a full new period.
+func (s *subscriptionService) preparePlanChange(ctx context.Context, subscription db.Subscription, planID int, now time.Time) (repository.PlanChange, error) {
+	if int(subscription.PlanID) == planID {
+		return repository.PlanChange{}, ErrSamePlan
+	}
+
+	to, err := s.getActivePlan(ctx, planID)
+	if err != nil {
+		return repository.PlanChange{}, err
+	}
+
+	from, err := s.planRepo.GetByID(ctx, int(subscription.PlanID))
+	if err != nil {
+		return repository.PlanChange{}, err
+	}
+
+	total := int64(subscription.CurrentPeriodEnd.Sub(subscription.CurrentPeriodStart) / time.Second)
+	remaining := int64(subscription.CurrentPeriodEnd.Sub(now) / time.Second)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	change := repository.PlanChange{
+		PlanID:      planID,
+		PeriodStart: subscription.CurrentPeriodStart,
+		PeriodEnd:   subscription.CurrentPeriodEnd,
+		Credit:      utils.ProrateDecimal(from.Price, remaining, total, planScale),
+	}
+
+	if from.BillingInterval == to.BillingInterval {
+		change.Charge = utils.ProrateDecimal(to.Price, remaining, total, planScale)
+	} else {
+		change.Charge = utils.ProrateDecimal(to.Price, 1, 1, planScale)
+		change.PeriodStart = now
+		change.PeriodEnd = addInterval(now, to.BillingInterval)
+	}
+
+	change.AmountDue = utils.SubtractDecimal(change.Charge, change.Credit, planScale)
+
+	return change, nil
+}
+
+func (s *subscriptionService) toResponse(ctx context.Context, subscription db.Subscription) (*SubscriptionResponse, error) {
+	plan, err := s.planRepo.GetByID(ctx, int(subscription.PlanID))
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := s.planRepo.GetLimits(ctx, int(subscription.PlanID))
+	if err != nil {
+		return nil, err
+	}
+
+	return ToSubscriptionResponse(subscription, ToPlanResponse(plan, limits)), nil
+}
+
+func toProrationResponse(subscription db.Subscription, change repository.PlanChange) *ProrationResponse {
+	return &ProrationResponse{
+		FromPlanID:  int(subscription.PlanID),
+		ToPlanID:    change.PlanID,
+		Credit:      change.Credit,
+		Charge:      change.Charge,
+		AmountDue:   change.AmountDue,
+		PeriodStart: change.PeriodStart,
+		PeriodEnd:   change.PeriodEnd,
+	}
+}
+
+// settleSubscription brings the periods of an active subscription up to now.
+// Periods are only advanced when the subscription is read, so one that ended
+// since renews, or ends with ErrSubscriptionNotFound if it was set to cancel
+// at period end.
+func settleSubscription(ctx context.Context, subscriptionRepo repository.SubscriptionRepository, planRepo repository.PlanRepository, subscription db.Subscription, now time.Time) (db.Subscription, error) {
+	if now.Before(subscription.CurrentPeriodEnd) {
+		return subscription, nil
+	}
+
+	if subscription.CancelAtPeriodEnd {
+		if _, err := subscriptionRepo.Cancel(ctx, int(subscription.ID), subscription.CurrentPeriodEnd); err != nil {
+			return db.Subscription{}, err
+		}
+		return db.Subscription{}, ErrSubscriptionNotFound
+	}
+
+	plan, err := planRepo.GetByID(ctx, int(subscription.PlanID))
+	if err != nil {
+		return db.Subscription{}, err
+	}
+
+	periodStart, periodEnd := periodAt(subscription, plan.BillingInterval, now)
+
+	renewed, err := subscriptionRepo.Renew(ctx, subscription, periodStart, periodEnd)
+	if errors.Is(err, repository.ErrSubscriptionChanged) {
+		// Another request settled or changed it first
+		current, err := subscriptionRepo.GetActiveByUserID(ctx, int(subscription.UserID))
+		if err != nil {
+			return db.Subscription{}, ErrSubscriptionNotFound
+		}
+		return current, nil
+	}
+	return renewed, err
+}
+
+// periodAt returns the billing period of subscription that contains now,
+// following on from the stored one without writing anything.
+func periodAt(subscription db.Subscription, interval string, now time.Time) (time.Time, time.Time) {
+	periodStart, periodEnd := subscription.CurrentPeriodStart, subscription.CurrentPeriodEnd
+	for !now.Before(periodEnd) {
+		periodStart, periodEnd = periodEnd, addInterval(periodEnd, interval)
+	}
+	return periodStart, periodEnd
+}
+
+// addInterval returns the end of a billing period starting at start.
+func addInterval(start time.Time, interval string) time.Time {
+	if interval == PlanIntervalYear {
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 1, 0)
+}
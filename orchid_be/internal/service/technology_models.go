@@ -0,0 +1,59 @@
+package service
+
+import (
+	"time"
+
+	"orchid_be/internal/db"
+)
+
+type CreateTechnologyRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type UpdateTechnologyRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type TechnologyResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TechnologyEntity serves /api/technologies through the generic CRUD stack. Names
+// are unique regardless of case and a technology that products use cannot be
+// deleted; both are enforced by the database.
+var TechnologyEntity = Entity[db.Technology, *TechnologyResponse]{
+	EntityConfig: EntityConfig{
+		Name:        "technologies",
+		DisplayName: "Technology",
+		Filters:     []string{"name", "created_at", "updated_at"},
+		Sorts:       []string{"id", "name", "created_at", "updated_at"},
+		Search:      []string{"name"},
+		DefaultSort: "name",
+		Permissions: EntityPermissions{
+			Create: PermissionTechnologiesManage,
+			Update: PermissionTechnologiesManage,
+			Delete: PermissionTechnologiesManage,
+		},
+	},
+	ToResponse: ToTechnologyResponse,
+}
+
+func ToTechnologyResponse(technology db.Technology) *TechnologyResponse {
+	resp := &TechnologyResponse{
+		ID:   int(technology.ID),
+		Name: technology.Name,
+	}
+
+	if technology.CreatedAt.Valid {
+		resp.CreatedAt = technology.CreatedAt.Time
+	}
+
+	if technology.UpdatedAt.Valid {
+		resp.UpdatedAt = technology.UpdatedAt.Time
+	}
+
+	return resp
+}
@@ -0,0 +1,28 @@
+package service
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool `json:"enabled"`
+	Pending                bool `json:"pending"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorSetupResponse carries the secret of a new, not yet confirmed
+// authenticator. OTPAuthURI is meant to be rendered as a QR code.
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse is the only time recovery codes are shown in plain text.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"orchid_be/internal/config"
+	"orchid_be/internal/repository"
+	"orchid_be/internal/utils"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorSetupRequired  = errors.New("start two-factor setup before confirming it")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor authentication code")
+)
+
+type TwoFactorService interface {
+	GetStatus(ctx context.Context, userID int) (*TwoFactorStatusResponse, error)
+	Setup(ctx context.Context, userID int) (*TwoFactorSetupResponse, error)
+	Confirm(ctx context.Context, userID int, req *TwoFactorCodeRequest) (*RecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID int, req *DisableTwoFactorRequest) error
+	Reset(ctx context.Context, userID int) error
+	IsEnabled(ctx context.Context, userID int) (bool, error)
+	VerifyCode(ctx context.Context, userID int, code string) error
+}
+
+type twoFactorService struct {
+	*BaseService
+	userRepo      repository.UserRepository
+	twoFactorRepo repository.TwoFactorRepository
+	authConfig    config.AuthConfig
+}
+
+func NewTwoFactorService(userRepo repository.UserRepository, twoFactorRepo repository.TwoFactorRepository, authConfig config.AuthConfig) TwoFactorService {
+	return &twoFactorService{
+		BaseService:   NewBaseService(),
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		authConfig:    authConfig,
+	}
+}
+
+func (s *twoFactorService) GetStatus(ctx context.Context, userID int) (*TwoFactorStatusResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	secret, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return &TwoFactorStatusResponse{}, nil
+	}
+
+	if !secret.ConfirmedAt.Valid {
+		return &TwoFactorStatusResponse{Pending: true}, nil
+	}
+
+	remaining, err := s.twoFactorRepo.CountUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorStatusResponse{
+		Enabled:                true,
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+// Setup generates a new secret that stays inactive until Confirm proves the
+// user's authenticator produces matching codes.
+func (s *twoFactorService) Setup(ctx context.Context, userID int) (*TwoFactorSetupResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if existing, err := s.twoFactorRepo.GetByUserID(ctx, userID); err == nil && existing.ConfirmedAt.Valid {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+
+	if _, err := s.twoFactorRepo.SaveSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPAuthURI(s.authConfig.TOTPIssuer, user.Email, secret),
+	}, nil
+}
+
+func (s *twoFactorService) Confirm(ctx context.Context, userID int, req *TwoFactorCodeRequest) (*RecoveryCodesResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	secret, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, ErrTwoFactorSetupRequired
+	}
+
+	if secret.ConfirmedAt.Valid {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := utils.ValidateTOTP(secret.Secret, req.Code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+
+	confirmed, err := s.twoFactorRepo.Confirm(ctx, userID, hashes)
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	// The confirmation code must not also work for the next login
+	if _, err := s.twoFactorRepo.MarkStepUsed(ctx, userID, step); err != nil {
+		return nil, err
+	}
+
+	return &RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *twoFactorService) Disable(ctx context.Context, userID int, req *DisableTwoFactorRequest) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := utils.CheckPassword(req.Password, user.PasswordHash); err != nil {
+		return ErrIncorrectPassword
+	}
+
+	if err := s.VerifyCode(ctx, userID, req.Code); err != nil {
+		return err
+	}
+
+	return s.twoFactorRepo.Delete(ctx, userID)
+}
+
+// Reset removes two-factor authentication without any proof from the user. It
+// is meant for administrators helping someone who lost their device.
+func (s *twoFactorService) Reset(ctx context.Context, userID int) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return ErrUserNotFound
+	}
+
+	if _, err := s.twoFactorRepo.GetByUserID(ctx, userID); err != nil {
+		return ErrTwoFactorNotEnabled
+	}
+
+	return s.twoFactorRepo.Delete(ctx, userID)
+}
+
+func (s *twoFactorService) IsEnabled(ctx context.Context, userID int) (bool, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	secret, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, nil
+	}
+
+	return secret.ConfirmedAt.Valid, nil
+}
+
+// VerifyCode accepts either a current authenticator code or an unused recovery
+// code. Both are single use.
+func (s *twoFactorService) VerifyCode(ctx context.Context, userID int, code string) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	secret, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil || !secret.ConfirmedAt.Valid {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = normalizeRecoveryCode(code)
+
+	if step, ok := utils.ValidateTOTP(secret.Secret, code, time.Now()); ok {
+		fresh, err := s.twoFactorRepo.MarkStepUsed(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := s.twoFactorRepo.UseRecoveryCode(ctx, userID, utils.HashToken(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+const recoveryCodeAlphabet = "abcdefghijklmnopqrstuvwxyz234567"
+
+// generateRecoveryCodes returns codes formatted as xxxxx-xxxxx together with
+// the hashes that are stored.
+func generateRecoveryCodes(n int) ([]string, []string, error) {
+	codes := make([]string, n)
+	hashes := make([]string, n)
+
+	for i := 0; i < n; i++ {
+		buf := make([]byte, 10)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+
+		for j := range buf {
+			buf[j] = recoveryCodeAlphabet[buf[j]%byte(len(recoveryCodeAlphabet))]
+		}
+
+		code := string(buf)
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = utils.HashToken(code)
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
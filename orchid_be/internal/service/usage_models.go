@@ -0,0 +1,48 @@
+package service
+
+import "time"
+
+// Metered plan limits. api_calls counts the requests made with the user's
+// API keys in the billing period; api_keys caps the keys that are active at
+// the same time.
+const (
+	UsageMetricAPICalls = "api_calls"
+	UsageMetricAPIKeys  = "api_keys"
+)
+
+// UsageResponse is a user's usage in the current billing period. PlanID is
+// nil, and nothing is capped, when the user has no active subscription; the
+// period is then the calendar month.
+type UsageResponse struct {
+	PlanID      *int                   `json:"plan_id,omitempty"`
+	PeriodStart time.Time              `json:"period_start"`
+	PeriodEnd   time.Time              `json:"period_end"`
+	Metrics     []*UsageMetricResponse `json:"metrics"`
+}
+
+// UsageMetricResponse is the usage of one metric. Limit and Remaining are
+// omitted when the plan does not cap the metric.
+type UsageMetricResponse struct {
+	Metric    string `json:"metric" example:"api_calls"`
+	Used      int64  `json:"used" example:"1250"`
+	Limit     *int   `json:"limit,omitempty" example:"10000"`
+	Remaining *int64 `json:"remaining,omitempty" example:"8750"`
+}
+
+func toUsageMetricResponse(metric string, used int64, limits map[string]int) *UsageMetricResponse {
+	response := &UsageMetricResponse{
+		Metric: metric,
+		Used:   used,
+	}
+
+	if limit, ok := limits[metric]; ok {
+		remaining := int64(limit) - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		response.Limit = &limit
+		response.Remaining = &remaining
+	}
+
+	return response
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"orchid_be/internal/repository"
+)
+
+var (
+	ErrPlanLimitExceeded = errors.New("plan limit exceeded")
+	ErrUnknownMetric     = errors.New("unknown usage metric")
+)
+
+// UsageService meters what users consume and enforces the limits of their
+// plan. Metered metrics such as api_calls are recorded with RecordUsage and
+// start from zero every billing period; counted metrics such as api_keys are
+// capped at GetLimit by the repository that creates the resource, in the same
+// transaction as the insert.
+type UsageService interface {
+	RecordUsage(ctx context.Context, userID int, metric string, quantity int) error
+	GetLimit(ctx context.Context, userID int, metric string) (int, bool, error)
+	GetUsage(ctx context.Context, userID int) (*UsageResponse, error)
+}
+
+type usageService struct {
+	*BaseService
+	usageRepo        repository.UsageRepository
+	subscriptionRepo repository.SubscriptionRepository
+	planRepo         repository.PlanRepository
+	apiKeyRepo       repository.APIKeyRepository
+	userRepo         repository.UserRepository
+}
+
+// usagePeriod is the billing period usage is counted in and the limits that
+// apply to it.
+type usagePeriod struct {
+	planID int
+	start  time.Time
+	end    time.Time
+	limits map[string]int
+}
+
+func NewUsageService(usageRepo repository.UsageRepository, subscriptionRepo repository.SubscriptionRepository, planRepo repository.PlanRepository, apiKeyRepo repository.APIKeyRepository, userRepo repository.UserRepository) UsageService {
+	return &usageService{
+		BaseService:      NewBaseService(),
+		usageRepo:        usageRepo,
+		subscriptionRepo: subscriptionRepo,
+		planRepo:         planRepo,
+		apiKeyRepo:       apiKeyRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// RecordUsage adds quantity to the metric for the current billing period. It
+// returns ErrPlanLimitExceeded, and records nothing, when that would go over
+// the plan's limit.
+func (s *usageService) RecordUsage(ctx context.Context, userID int, metric string, quantity int) error {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	period, err := s.currentPeriod(ctx, userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	record := repository.UsageRecord{
+		UserID:      userID,
+		Metric:      metric,
+		Quantity:    quantity,
+		PeriodStart: period.start,
+		PeriodEnd:   period.end,
+	}
+
+	limit, capped := period.limits[metric]
+	if capped {
+		if quantity > limit {
+			return limitExceeded(metric, limit)
+		}
+		maxQuantity := int64(limit)
+		record.MaxQuantity = &maxQuantity
+	}
+
+	_, ok, err := s.usageRepo.Record(ctx, record)
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	if !ok {
+		return limitExceeded(metric, limit)
+	}
+
+	return nil
+}
+
+// GetLimit returns the current plan's limit of a metric. It reports false
+// when the plan does not cap the metric.
+func (s *usageService) GetLimit(ctx context.Context, userID int, metric string) (int, bool, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	period, err := s.currentPeriod(ctx, userID, time.Now())
+	if err != nil {
+		return 0, false, err
+	}
+
+	limit, capped := period.limits[metric]
+	return limit, capped, nil
+}
+
+// GetUsage returns the user's usage of every metric in the current billing
+// period together with the plan's limits.
+func (s *usageService) GetUsage(ctx context.Context, userID int) (*UsageResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	period, err := s.currentPeriod(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	counters, err := s.usageRepo.GetCounters(ctx, userID, period.start)
+	if err != nil {
+		return nil, err
+	}
+
+	apiCalls := int64(0)
+	for _, counter := range counters {
+		if counter.Metric == UsageMetricAPICalls {
+			apiCalls = counter.Quantity
+		}
+	}
+
+	apiKeys, err := s.count(ctx, userID, UsageMetricAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UsageResponse{
+		PeriodStart: period.start,
+		PeriodEnd:   period.end,
+		Metrics: []*UsageMetricResponse{
+			toUsageMetricResponse(UsageMetricAPICalls, apiCalls, period.limits),
+			toUsageMetricResponse(UsageMetricAPIKeys, apiKeys, period.limits),
+		},
+	}
+	if period.planID != 0 {
+		response.PlanID = &period.planID
+	}
+
+	return response, nil
+}
+
+// currentPeriod returns the period of the user's active subscription and the
+// limits of its plan. Without a subscription usage is counted per calendar
+// month and nothing is capped.
+//
+// It runs on every API key request, so it only reads: a subscription whose
+// stored period has passed is treated as renewed, or as ended if it was set
+// to cancel, and writing that down is left to the subscription and invoice
+// paths that settle it.
+func (s *usageService) currentPeriod(ctx context.Context, userID int, now time.Time) (usagePeriod, error) {
+	subscription, err := s.subscriptionRepo.GetActiveByUserID(ctx, userID)
+	if err != nil || (subscription.CancelAtPeriodEnd && !now.Before(subscription.CurrentPeriodEnd)) {
+		now = now.UTC()
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return usagePeriod{start: start, end: start.AddDate(0, 1, 0)}, nil
+	}
+
+	periodStart, periodEnd := subscription.CurrentPeriodStart, subscription.CurrentPeriodEnd
+	if !now.Before(periodEnd) {
+		plan, err := s.planRepo.GetByID(ctx, int(subscription.PlanID))
+		if err != nil {
+			return usagePeriod{}, err
+		}
+		periodStart, periodEnd = periodAt(subscription, plan.BillingInterval, now)
+	}
+
+	limits, err := s.planRepo.GetLimits(ctx, int(subscription.PlanID))
+	if err != nil {
+		return usagePeriod{}, err
+	}
+
+	period := usagePeriod{
+		planID: int(subscription.PlanID),
+		start:  periodStart,
+		end:    periodEnd,
+		limits: make(map[string]int, len(limits)),
+	}
+	for _, limit := range limits {
+		period.limits[limit.Name] = int(limit.MaxValue)
+	}
+
+	return period, nil
+}
+
+// count returns how much of a counted metric the user holds right now.
+func (s *usageService) count(ctx context.Context, userID int, metric string) (int64, error) {
+	switch metric {
+	case UsageMetricAPIKeys:
+		count, err := s.apiKeyRepo.CountActiveForUser(ctx, userID)
+		return int64(count), err
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownMetric, metric)
+	}
+}
+
+func limitExceeded(metric string, limit int) error {
+	return fmt.Errorf("%w: %s is limited to %d by the current plan", ErrPlanLimitExceeded, metric, limit)
+}
@@ -6,30 +6,93 @@ import (
 	"orchid_be/internal/db"
 )
 
+const (
+	UserStatusActive   = "active"
+	UserStatusInactive = "inactive"
+	UserStatusPending  = "pending"
+)
+
+// Country is an upper-case ISO 3166-1 alpha-2 code such as "US". Status
+// defaults to active.
 type CreateUserRequest struct {
-	Name     string `json:"name" validate:"required"`
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Name      string `json:"name" validate:"required"`
+	Email     string `json:"email" validate:"required,email"`
+	Password  string `json:"password" validate:"required" format:"password"`
+	Status    string `json:"status,omitempty" validate:"omitempty,oneof=active inactive pending"`
+	Avatar    string `json:"avatar,omitempty" validate:"max=255"`
+	Biography string `json:"biography,omitempty" validate:"max=2000"`
+	Position  string `json:"position,omitempty" validate:"max=100"`
+	Country   string `json:"country,omitempty" validate:"omitempty,iso3166_1_alpha2"`
 }
 
+// UpdateUserRequest changes only the fields that are present. Send an empty
+// string to clear a profile field. A new email address has to be verified
+// again.
 type UpdateUserRequest struct {
-	Name  *string `json:"name,omitempty"`
-	Email *string `json:"email,omitempty"`
+	Name      *string `json:"name,omitempty" validate:"omitempty,min=1"`
+	Email     *string `json:"email,omitempty" validate:"omitempty,email"`
+	Status    *string `json:"status,omitempty" validate:"omitempty,oneof=active inactive pending"`
+	Avatar    *string `json:"avatar,omitempty" validate:"omitempty,max=255"`
+	Biography *string `json:"biography,omitempty" validate:"omitempty,max=2000"`
+	Position  *string `json:"position,omitempty" validate:"omitempty,max=100"`
+	Country   *string `json:"country,omitempty" validate:"omitempty,eq=|iso3166_1_alpha2"`
 }
 
 type UserResponse struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                  int        `json:"id"`
+	Name                string     `json:"name"`
+	Email               string     `json:"email"`
+	Avatar              string     `json:"avatar"`
+	Biography           string     `json:"biography"`
+	Position            string     `json:"position"`
+	Country             string     `json:"country"`
+	Status              string     `json:"status"`
+	EmailVerifiedAt     *time.Time `json:"email_verified_at,omitempty"`
+	Locked              bool       `json:"locked"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
+	FailedLoginAttempts int        `json:"failed_login_attempts"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
 }
 
+// UserEntityMeta describes /api/users for the metadata endpoint. Its filter,
+// sort and search columns are also the only ones GET /api/users accepts.
+var UserEntityMeta = DescribeEntity[CreateUserRequest, UpdateUserRequest, UserResponse](EntityConfig{
+	Name:        "users",
+	DisplayName: "User",
+	Filters:     []string{"name", "email", "status", "position", "country", "created_at", "updated_at"},
+	Sorts:       []string{"id", "name", "email", "status", "position", "country", "created_at", "updated_at"},
+	Search:      []string{"name", "email", "position"},
+	DefaultSort: "created_at",
+	DefaultDesc: true,
+	Permissions: EntityPermissions{
+		Read:   PermissionUsersList,
+		Create: PermissionUsersCreate,
+		Update: PermissionUsersUpdate,
+		Delete: PermissionUsersDelete,
+	},
+})
+
 func ToUserResponse(user db.User) *UserResponse {
 	resp := &UserResponse{
-		ID:    int(user.ID),
-		Name:  user.Name,
-		Email: user.Email,
+		ID:                  int(user.ID),
+		Name:                user.Name,
+		Email:               user.Email,
+		Avatar:              user.Avatar,
+		Biography:           user.Biography,
+		Position:            user.Position,
+		Country:             user.Country,
+		Status:              user.Status,
+		FailedLoginAttempts: int(user.FailedLoginAttempts),
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		resp.EmailVerifiedAt = &user.EmailVerifiedAt.Time
+	}
+
+	if user.LockedUntil.Valid && user.LockedUntil.Time.After(time.Now()) {
+		resp.Locked = true
+		resp.LockedUntil = &user.LockedUntil.Time
 	}
 
 	if user.CreatedAt.Valid {
@@ -4,28 +4,41 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 
+	"orchid_be/internal/config"
+	"orchid_be/internal/mailer"
 	"orchid_be/internal/repository"
 	"orchid_be/internal/utils"
 )
 
+var (
+	ErrEmailAlreadyExists     = errors.New("email already exists")
+	ErrStatusChangeNotAllowed = errors.New("changing the account status requires the users:update permission")
+)
+
 type UserService interface {
 	CreateUser(ctx context.Context, req *CreateUserRequest) (*UserResponse, error)
 	GetUserByID(ctx context.Context, id int) (*UserResponse, error)
-	GetAllUsers(ctx context.Context, page, limit int) ([]*UserResponse, int, error)
+	GetAllUsers(ctx context.Context, req ListRequest) ([]*UserResponse, int, error)
 	UpdateUser(ctx context.Context, id int, req *UpdateUserRequest) (*UserResponse, error)
 	DeleteUser(ctx context.Context, id int) error
+	UnlockUser(ctx context.Context, id int) (*UserResponse, error)
 }
 
 type userService struct {
 	*BaseService
-	userRepo repository.UserRepository
+	userRepo       repository.UserRepository
+	passwordPolicy *utils.PasswordPolicy
+	verification   *verificationMailer
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
+func NewUserService(userRepo repository.UserRepository, passwordPolicy *utils.PasswordPolicy, jwtManager *utils.JWTManager, mail mailer.Mailer, authConfig config.AuthConfig) UserService {
 	return &userService{
-		BaseService: NewBaseService(),
-		userRepo:    userRepo,
+		BaseService:    NewBaseService(),
+		userRepo:       userRepo,
+		passwordPolicy: passwordPolicy,
+		verification:   newVerificationMailer(jwtManager, mail, authConfig),
 	}
 }
 
@@ -36,7 +49,11 @@ func (s *userService) CreateUser(ctx context.Context, req *CreateUserRequest) (*
 	_, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err == nil {
 		// User found with this email
-		return nil, errors.New("email already exists")
+		return nil, ErrEmailAlreadyExists
+	}
+
+	if err := s.passwordPolicy.Validate(req.Password, req.Email); err != nil {
+		return nil, err
 	}
 
 	hashedPassword, err := utils.HashPassword(req.Password)
@@ -44,7 +61,19 @@ func (s *userService) CreateUser(ctx context.Context, req *CreateUserRequest) (*
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	user, err := s.userRepo.Create(ctx, req.Name, req.Email, hashedPassword)
+	status := req.Status
+	if status == "" {
+		status = UserStatusActive
+	}
+
+	profile := repository.UserProfile{
+		Avatar:    req.Avatar,
+		Biography: req.Biography,
+		Position:  req.Position,
+		Country:   req.Country,
+	}
+
+	user, err := s.userRepo.Create(ctx, req.Name, req.Email, hashedPassword, status, profile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
@@ -64,10 +93,13 @@ func (s *userService) GetUserByID(ctx context.Context, id int) (*UserResponse, e
 	return ToUserResponse(user), nil
 }
 
-func (s *userService) GetAllUsers(ctx context.Context, page, limit int) ([]*UserResponse, int, error) {
+// GetAllUsers filters, searches and sorts on the columns UserEntityMeta
+// allows, which the metadata endpoint reports to the dashboard.
+func (s *userService) GetAllUsers(ctx context.Context, req ListRequest) ([]*UserResponse, int, error) {
 	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
 	defer cancel()
 
+	page, limit := req.Page, req.Limit
 	if page < 1 {
 		page = 1
 	}
@@ -77,14 +109,19 @@ func (s *userService) GetAllUsers(ctx context.Context, page, limit int) ([]*User
 
 	offset := (page - 1) * limit
 
-	users, err := s.userRepo.GetAll(ctx, limit, offset)
+	query, err := buildListQuery(UserEntityMeta.EntityConfig, req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users, err := s.userRepo.GetAll(ctx, query, limit, offset)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get users: %w", err)
+		return nil, 0, listError("failed to get users", err)
 	}
 
-	total, err := s.userRepo.Count(ctx)
+	total, err := s.userRepo.Count(ctx, query)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+		return nil, 0, listError("failed to count users", err)
 	}
 
 	userResponses := make([]*UserResponse, len(users))
@@ -95,6 +132,15 @@ func (s *userService) GetAllUsers(ctx context.Context, page, limit int) ([]*User
 	return userResponses, total, nil
 }
 
+// listError reports a filter value the database could not convert, such as
+// a malformed date, as ErrInvalidListQuery.
+func listError(message string, err error) error {
+	if errors.Is(err, repository.ErrInvalidValue) {
+		return fmt.Errorf("%w: %v", ErrInvalidListQuery, err)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
 func (s *userService) UpdateUser(ctx context.Context, id int, req *UpdateUserRequest) (*UserResponse, error) {
 	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
 	defer cancel()
@@ -105,23 +151,64 @@ func (s *userService) UpdateUser(ctx context.Context, id int, req *UpdateUserReq
 	}
 
 	// Check if email is being updated and if it already exists
-	if req.Email != nil && *req.Email != user.Email {
+	emailChanged := req.Email != nil && *req.Email != user.Email
+	if emailChanged {
+		// The email address is used to sign in and to reset the password
+		if principal, ok := PrincipalFromContext(ctx); !ok || !principal.IsElevated() {
+			return nil, ErrReauthenticationRequired
+		}
+
 		existingUser, err := s.userRepo.GetByEmail(ctx, *req.Email)
 		if err == nil && existingUser.ID != int32(id) {
-			return nil, errors.New("email already exists")
+			return nil, ErrEmailAlreadyExists
 		}
 		user.Email = *req.Email
 	}
 
+	// Users may edit their own profile but not activate or disable themselves
+	if req.Status != nil && *req.Status != user.Status {
+		if principal, ok := PrincipalFromContext(ctx); !ok || !principal.HasPermission(PermissionUsersUpdate) {
+			return nil, ErrStatusChangeNotAllowed
+		}
+		user.Status = *req.Status
+	}
+
 	if req.Name != nil {
 		user.Name = *req.Name
 	}
+	if req.Avatar != nil {
+		user.Avatar = *req.Avatar
+	}
+	if req.Biography != nil {
+		user.Biography = *req.Biography
+	}
+	if req.Position != nil {
+		user.Position = *req.Position
+	}
+	if req.Country != nil {
+		user.Country = *req.Country
+	}
+
+	profile := repository.UserProfile{
+		Avatar:    user.Avatar,
+		Biography: user.Biography,
+		Position:  user.Position,
+		Country:   user.Country,
+	}
 
-	updatedUser, err := s.userRepo.Update(ctx, id, user.Name, user.Email, user.PasswordHash)
+	updatedUser, err := s.userRepo.Update(ctx, id, user.Name, user.Email, user.PasswordHash, user.Status, profile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
+	// The update cleared email_verified_at; the new address has to be
+	// verified before it can be linked to a login provider.
+	if emailChanged {
+		if err := s.verification.send(ctx, updatedUser); err != nil {
+			log.Printf("Failed to send verification email to %s: %v", updatedUser.Email, err)
+		}
+	}
+
 	return ToUserResponse(updatedUser), nil
 }
 
@@ -135,3 +222,24 @@ func (s *userService) DeleteUser(ctx context.Context, id int) error {
 
 	return nil
 }
+
+// UnlockUser clears the failed login counter and any lockout.
+func (s *userService) UnlockUser(ctx context.Context, id int) (*UserResponse, error) {
+	ctx, cancel := s.WithTimeout(ctx, s.DefaultTimeout())
+	defer cancel()
+
+	if _, err := s.userRepo.GetByID(ctx, id); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := s.userRepo.ResetFailedLogins(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to unlock user: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return ToUserResponse(user), nil
+}
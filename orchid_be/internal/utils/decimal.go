@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidDecimal = errors.New("invalid decimal number")
+
+var decimalPattern = regexp.MustCompile(`^(\d+)(?:\.(\d+))?$`)
+
+// NormalizeDecimal checks that value is a non-negative decimal such as "12.5"
+// with at most integerDigits digits before and scale digits after the point,
+// and returns it with exactly scale fractional digits ("12.50"). Amounts stay
+// strings from request to NUMERIC column so they never pass through a float.
+func NormalizeDecimal(value string, integerDigits, scale int) (string, error) {
+	match := decimalPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return "", ErrInvalidDecimal
+	}
+
+	integer := strings.TrimLeft(match[1], "0")
+	if len(integer) > integerDigits || len(match[2]) > scale {
+		return "", ErrInvalidDecimal
+	}
+
+	r, ok := new(big.Rat).SetString(match[0])
+	if !ok {
+		return "", ErrInvalidDecimal
+	}
+
+	return r.FloatString(scale), nil
+}
+
+// CompareDecimal compares two decimals accepted by NormalizeDecimal and
+// returns -1, 0 or +1.
+func CompareDecimal(a, b string) int {
+	x, _ := new(big.Rat).SetString(a)
+	y, _ := new(big.Rat).SetString(b)
+	if x == nil || y == nil {
+		return strings.Compare(a, b)
+	}
+	return x.Cmp(y)
+}
+
+// ApplyDiscount returns price reduced by percent, rounded half away from zero
+// to scale fractional digits.
+func ApplyDiscount(price, percent string, scale int) string {
+	p, ok := new(big.Rat).SetString(price)
+	if !ok {
+		return price
+	}
+	d, ok := new(big.Rat).SetString(percent)
+	if !ok {
+		return p.FloatString(scale)
+	}
+
+	factor := new(big.Rat).Sub(big.NewRat(1, 1), new(big.Rat).Quo(d, big.NewRat(100, 1)))
+	return new(big.Rat).Mul(p, factor).FloatString(scale)
+}
+
+// ProrateDecimal returns the part/whole share of amount, rounded half away
+// from zero to scale fractional digits.
+func ProrateDecimal(amount string, part, whole int64, scale int) string {
+	a, ok := new(big.Rat).SetString(amount)
+	if !ok || whole == 0 {
+		return new(big.Rat).FloatString(scale)
+	}
+	return new(big.Rat).Mul(a, big.NewRat(part, whole)).FloatString(scale)
+}
+
+// SubtractDecimal returns a - b with scale fractional digits. The result may
+// be negative.
+func SubtractDecimal(a, b string, scale int) string {
+	x, ok := new(big.Rat).SetString(a)
+	if !ok {
+		x = new(big.Rat)
+	}
+	y, ok := new(big.Rat).SetString(b)
+	if !ok {
+		y = new(big.Rat)
+	}
+	return new(big.Rat).Sub(x, y).FloatString(scale)
+}
+
+// AddDecimal returns a + b with scale fractional digits.
+func AddDecimal(a, b string, scale int) string {
+	x, ok := new(big.Rat).SetString(a)
+	if !ok {
+		x = new(big.Rat)
+	}
+	y, ok := new(big.Rat).SetString(b)
+	if !ok {
+		y = new(big.Rat)
+	}
+	return new(big.Rat).Add(x, y).FloatString(scale)
+}
+
+// PercentOfDecimal returns percent of amount, rounded half away from zero to
+// scale fractional digits.
+func PercentOfDecimal(amount, percent string, scale int) string {
+	a, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return new(big.Rat).FloatString(scale)
+	}
+	p, ok := new(big.Rat).SetString(percent)
+	if !ok {
+		return new(big.Rat).FloatString(scale)
+	}
+	return new(big.Rat).Mul(a, new(big.Rat).Quo(p, big.NewRat(100, 1))).FloatString(scale)
+}
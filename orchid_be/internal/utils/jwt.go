@@ -0,0 +1,281 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"orchid_be/internal/config"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+// In release mode an HS256 secret must not be the placeholder from
+// configs/config.yaml and must be at least as long as the SHA-256 key.
+const (
+	placeholderSecret = "change-me-in-production"
+	minSecretLength   = 32
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+type Claims struct {
+	Issuer          string      `json:"iss,omitempty"`
+	Subject         string      `json:"sub"`
+	Email           string      `json:"email,omitempty"`
+	TokenType       string      `json:"typ"`
+	ID              string      `json:"jti,omitempty"`
+	SessionID       int         `json:"sid,omitempty"`
+	Actor           *ActorClaim `json:"act,omitempty"`
+	ImpersonationID int         `json:"imp,omitempty"`
+	IssuedAt        int64       `json:"iat"`
+	ExpiresAt       int64       `json:"exp"`
+}
+
+// ActorClaim names the user really making the requests when the token was
+// issued to impersonate its subject, as in RFC 8693.
+type ActorClaim struct {
+	Subject string `json:"sub"`
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+}
+
+type JWTManager struct {
+	algorithm  string
+	secret     []byte
+	privateKey *rsa.PrivateKey
+	publicKey  *rsa.PublicKey
+	issuer     string
+}
+
+// NewJWTManager builds the manager for the configured algorithm. With release
+// set, weak HS256 secrets are refused so a server never starts with one.
+func NewJWTManager(cfg *config.JWTConfig, release bool) (*JWTManager, error) {
+	manager := &JWTManager{
+		algorithm: strings.ToUpper(cfg.Algorithm),
+		issuer:    cfg.Issuer,
+	}
+
+	switch manager.algorithm {
+	case AlgorithmHS256:
+		if cfg.Secret == "" {
+			return nil, errors.New("jwt secret is required for HS256")
+		}
+		if release && (cfg.Secret == placeholderSecret || len(cfg.Secret) < minSecretLength) {
+			return nil, fmt.Errorf("jwt secret must be changed from the default and be at least %d bytes long in release mode", minSecretLength)
+		}
+		manager.secret = []byte(cfg.Secret)
+	case AlgorithmRS256:
+		privateKey, err := loadRSAPrivateKey(cfg.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		manager.privateKey = privateKey
+		manager.publicKey = &privateKey.PublicKey
+
+		if cfg.PublicKeyPath != "" {
+			publicKey, err := loadRSAPublicKey(cfg.PublicKeyPath)
+			if err != nil {
+				return nil, err
+			}
+			manager.publicKey = publicKey
+		}
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", cfg.Algorithm)
+	}
+
+	return manager, nil
+}
+
+// Sign fills in the issuer and issued-at time when missing and returns the compact JWT.
+func (m *JWTManager) Sign(claims *Claims) (string, error) {
+	if claims.Issuer == "" {
+		claims.Issuer = m.issuer
+	}
+	if claims.IssuedAt == 0 {
+		claims.IssuedAt = time.Now().Unix()
+	}
+
+	header, err := json.Marshal(jwtHeader{Algorithm: m.algorithm, Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodeSegment(header) + "." + encodeSegment(payload)
+	signature, err := m.sign([]byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return signingInput + "." + encodeSegment(signature), nil
+}
+
+// Parse verifies the signature, algorithm, issuer and expiry of a token and returns its claims.
+func (m *JWTManager) Parse(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	headerBytes, err := decodeSegment(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, ErrInvalidToken
+	}
+	// Never let the token choose its own algorithm.
+	if header.Algorithm != m.algorithm {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := decodeSegment(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if err := m.verify([]byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if m.issuer != "" && claims.Issuer != m.issuer {
+		return nil, ErrInvalidToken
+	}
+	if claims.ExpiresAt == 0 || time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	return &claims, nil
+}
+
+func (m *JWTManager) sign(signingInput []byte) ([]byte, error) {
+	switch m.algorithm {
+	case AlgorithmHS256:
+		mac := hmac.New(sha256.New, m.secret)
+		mac.Write(signingInput)
+		return mac.Sum(nil), nil
+	case AlgorithmRS256:
+		digest := sha256.Sum256(signingInput)
+		return rsa.SignPKCS1v15(nil, m.privateKey, crypto.SHA256, digest[:])
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", m.algorithm)
+	}
+}
+
+func (m *JWTManager) verify(signingInput, signature []byte) error {
+	switch m.algorithm {
+	case AlgorithmHS256:
+		expected, _ := m.sign(signingInput)
+		if !hmac.Equal(expected, signature) {
+			return ErrInvalidToken
+		}
+		return nil
+	case AlgorithmRS256:
+		digest := sha256.Sum256(signingInput)
+		return rsa.VerifyPKCS1v15(m.publicKey, crypto.SHA256, digest[:], signature)
+	default:
+		return fmt.Errorf("unsupported jwt algorithm %q", m.algorithm)
+	}
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(segment)
+}
+
+func loadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	if path == "" {
+		return nil, errors.New("jwt private key path is required for RS256")
+	}
+
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jwt private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("jwt private key is not an RSA key")
+	}
+
+	return key, nil
+}
+
+func loadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jwt public key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("jwt public key is not an RSA key")
+	}
+
+	return key, nil
+}
+
+func readPEMBlock(path string) (*pem.Block, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %s: %w", path, err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+
+	return block, nil
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"orchid_be/internal/config"
+)
+
+var ErrWeakPassword = errors.New("password does not meet the password policy")
+
+// PasswordPolicy checks new passwords against the configured rules.
+type PasswordPolicy struct {
+	minLength        int
+	requireUppercase bool
+	requireLowercase bool
+	requireDigit     bool
+	requireSymbol    bool
+	commonPasswords  map[string]struct{}
+}
+
+func NewPasswordPolicy(cfg *config.PasswordPolicyConfig) (*PasswordPolicy, error) {
+	policy := &PasswordPolicy{
+		minLength:        cfg.MinLength,
+		requireUppercase: cfg.RequireUppercase,
+		requireLowercase: cfg.RequireLowercase,
+		requireDigit:     cfg.RequireDigit,
+		requireSymbol:    cfg.RequireSymbol,
+		commonPasswords:  map[string]struct{}{},
+	}
+
+	if cfg.CommonPasswordsFile != "" {
+		common, err := loadCommonPasswords(cfg.CommonPasswordsFile)
+		if err != nil {
+			return nil, err
+		}
+		policy.commonPasswords = common
+	}
+
+	return policy, nil
+}
+
+// Validate returns an error wrapping ErrWeakPassword that lists every rule the password breaks.
+func (p *PasswordPolicy) Validate(password, email string) error {
+	var violations []string
+
+	if len([]rune(password)) < p.minLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.minLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.requireUppercase && !hasUpper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.requireLowercase && !hasLower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.requireDigit && !hasDigit {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.requireSymbol && !hasSymbol {
+		violations = append(violations, "must contain a symbol")
+	}
+
+	if email != "" && strings.EqualFold(password, email) {
+		violations = append(violations, "must not be the same as the email address")
+	}
+
+	if _, found := p.commonPasswords[strings.ToLower(password)]; found {
+		violations = append(violations, "is too common")
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%w: password %s", ErrWeakPassword, strings.Join(violations, ", "))
+	}
+
+	return nil
+}
+
+func loadCommonPasswords(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open common passwords file: %w", err)
+	}
+	defer file.Close()
+
+	common := map[string]struct{}{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		common[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read common passwords file: %w", err)
+	}
+
+	return common, nil
+}
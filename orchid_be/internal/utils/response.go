@@ -11,8 +11,14 @@ type Response struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	Code    string      `json:"code,omitempty"`
 }
 
+// Machine-readable error codes, for errors a client is expected to handle.
+const (
+	ErrorCodePlanLimitExceeded = "plan_limit_exceeded"
+)
+
 func SuccessResponse(c *gin.Context, statusCode int, message string, data interface{}) {
 	c.JSON(statusCode, Response{
 		Success: true,
@@ -22,6 +28,10 @@ func SuccessResponse(c *gin.Context, statusCode int, message string, data interf
 }
 
 func ErrorResponse(c *gin.Context, statusCode int, message string, err error) {
+	ErrorResponseWithCode(c, statusCode, "", message, err)
+}
+
+func ErrorResponseWithCode(c *gin.Context, statusCode int, code, message string, err error) {
 	errorMsg := message
 	if err != nil {
 		errorMsg = err.Error()
@@ -31,6 +41,7 @@ func ErrorResponse(c *gin.Context, statusCode int, message string, err error) {
 		Success: false,
 		Message: message,
 		Error:   errorMsg,
+		Code:    code,
 	})
 }
 
@@ -50,10 +61,28 @@ func Unauthorized(c *gin.Context, message string, err error) {
 	ErrorResponse(c, http.StatusUnauthorized, message, err)
 }
 
+func Forbidden(c *gin.Context, message string, err error) {
+	ErrorResponse(c, http.StatusForbidden, message, err)
+}
+
+// PlanLimitExceeded answers 402 Payment Required with the plan_limit_exceeded
+// code, telling the client to upgrade the plan or wait for the next period.
+func PlanLimitExceeded(c *gin.Context, message string, err error) {
+	ErrorResponseWithCode(c, http.StatusPaymentRequired, ErrorCodePlanLimitExceeded, message, err)
+}
+
 func NotFound(c *gin.Context, message string, err error) {
 	ErrorResponse(c, http.StatusNotFound, message, err)
 }
 
+func Conflict(c *gin.Context, message string, err error) {
+	ErrorResponse(c, http.StatusConflict, message, err)
+}
+
+func TooManyRequests(c *gin.Context, message string, err error) {
+	ErrorResponse(c, http.StatusTooManyRequests, message, err)
+}
+
 func InternalServerError(c *gin.Context, message string, err error) {
 	ErrorResponse(c, http.StatusInternalServerError, message, err)
 }
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+)
+
+// JSONName returns the name a struct field is encoded under by encoding/json,
+// or "" for fields that are not encoded.
+func JSONName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
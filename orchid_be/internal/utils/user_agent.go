@@ -0,0 +1,60 @@
+package utils
+
+import "strings"
+
+type userAgentMatch struct {
+	token string
+	name  string
+}
+
+// Order matters: Edge and Opera also claim to be Chrome, Chrome claims to be
+// Safari, and iOS and Android user agents mention Mac OS and Linux.
+var (
+	userAgentBrowsers = []userAgentMatch{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"CriOS/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+		{"PostmanRuntime/", "Postman"},
+		{"Go-http-client/", "Go HTTP client"},
+	}
+	userAgentSystems = []userAgentMatch{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"CrOS", "ChromeOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// DescribeUserAgent turns a User-Agent header into a short label such as
+// "Firefox on Linux" for listing sessions.
+func DescribeUserAgent(userAgent string) string {
+	browser := matchUserAgent(userAgent, userAgentBrowsers)
+	system := matchUserAgent(userAgent, userAgentSystems)
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "Unknown device"
+	}
+}
+
+func matchUserAgent(userAgent string, matches []userAgentMatch) string {
+	for _, match := range matches {
+		if strings.Contains(userAgent, match.token) {
+			return match.name
+		}
+	}
+	return ""
+}
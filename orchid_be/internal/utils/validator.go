@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("validate")
+	return v
+}
+
+// ValidateStruct checks the `validate` tags declared on request structs.
+func ValidateStruct(obj interface{}) error {
+	return validate.Struct(obj)
+}
+
+// ValidateVar checks a single value, such as a path parameter, against a
+// `validate` tag.
+func ValidateVar(value interface{}, tag string) error {
+	return validate.Var(value, tag)
+}
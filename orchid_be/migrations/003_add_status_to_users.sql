@@ -0,0 +1,6 @@
+-- Add account status and email verification to users
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Create index on status for filtering pending accounts
+CREATE INDEX IF NOT EXISTS idx_users_status ON users(status);
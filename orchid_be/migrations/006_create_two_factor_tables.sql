@@ -0,0 +1,38 @@
+-- Create totp_secrets table, one authenticator per user
+CREATE TABLE IF NOT EXISTS totp_secrets (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create recovery_codes table
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on user_id for looking up and replacing a user's codes
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes(user_id);
+
+DROP TRIGGER IF EXISTS update_totp_secrets_updated_at ON totp_secrets;
+CREATE TRIGGER update_totp_secrets_updated_at
+    BEFORE UPDATE ON totp_secrets
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Seed the permission for resetting another user's two-factor authentication
+INSERT INTO permissions (name, description) VALUES
+    ('users:reset_2fa', 'Reset two-factor authentication of any user')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name = 'users:reset_2fa'
+ON CONFLICT DO NOTHING;
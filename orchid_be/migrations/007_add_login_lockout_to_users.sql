@@ -0,0 +1,15 @@
+-- Track failed logins for progressive delays and temporary lockout
+ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_login_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_failed_login_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE;
+
+-- Seed the permission for unlocking accounts
+INSERT INTO permissions (name, description) VALUES
+    ('users:unlock', 'Unlock accounts locked after failed logins')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name = 'users:unlock'
+ON CONFLICT DO NOTHING;
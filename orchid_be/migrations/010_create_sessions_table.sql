@@ -0,0 +1,36 @@
+-- Create sessions table, one row per login (refresh token family)
+CREATE TABLE IF NOT EXISTS sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id VARCHAR(64) UNIQUE NOT NULL,
+    device VARCHAR(100) NOT NULL,
+    user_agent VARCHAR(512) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on user_id for listing a user's sessions
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+
+-- Logins made before sessions were tracked show up without device details
+INSERT INTO sessions (user_id, family_id, device, user_agent, ip_address, expires_at, created_at)
+SELECT user_id, family_id, 'Unknown device', '', '', MAX(expires_at), MIN(created_at)
+FROM refresh_tokens
+WHERE revoked_at IS NULL
+GROUP BY user_id, family_id
+ON CONFLICT (family_id) DO NOTHING;
+
+-- Seed the permissions for managing other users' sessions
+INSERT INTO permissions (name, description) VALUES
+    ('sessions:read', 'List the sessions of any user'),
+    ('sessions:revoke', 'Revoke the sessions of any user')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name IN ('sessions:read', 'sessions:revoke')
+ON CONFLICT DO NOTHING;
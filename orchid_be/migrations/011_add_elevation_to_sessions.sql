@@ -0,0 +1,2 @@
+-- Track re-authentication ("sudo mode") per session
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS elevated_until TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,42 @@
+-- Create impersonations table, one row per support login as another user
+CREATE TABLE IF NOT EXISTS impersonations (
+    id SERIAL PRIMARY KEY,
+    actor_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    subject_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for looking up impersonations by either user
+CREATE INDEX IF NOT EXISTS idx_impersonations_actor_id ON impersonations(actor_id);
+CREATE INDEX IF NOT EXISTS idx_impersonations_subject_id ON impersonations(subject_id);
+
+-- Create impersonation_events table, the audit trail of each impersonation
+CREATE TABLE IF NOT EXISTS impersonation_events (
+    id SERIAL PRIMARY KEY,
+    impersonation_id INTEGER NOT NULL REFERENCES impersonations(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(255) NOT NULL,
+    status_code INTEGER NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on impersonation_id for reading one impersonation's trail
+CREATE INDEX IF NOT EXISTS idx_impersonation_events_impersonation_id ON impersonation_events(impersonation_id);
+
+-- Seed the permissions for impersonating users and reviewing the trail
+INSERT INTO permissions (name, description) VALUES
+    ('users:impersonate', 'Sign in as another user for support'),
+    ('impersonations:read', 'Review impersonations and their audit trail')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name IN ('users:impersonate', 'impersonations:read')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,7 @@
+-- Add the profile fields shown on the dashboard
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS biography TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS position VARCHAR(100) NOT NULL DEFAULT '';
+
+-- ISO 3166-1 alpha-2 code, empty when unknown
+ALTER TABLE users ADD COLUMN IF NOT EXISTS country VARCHAR(2) NOT NULL DEFAULT '';
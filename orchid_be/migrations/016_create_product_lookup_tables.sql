@@ -0,0 +1,73 @@
+-- Create lookup tables for product categories and technologies
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS technologies (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Names are unique regardless of case
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name ON categories(LOWER(name));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_technologies_name ON technologies(LOWER(name));
+
+DROP TRIGGER IF EXISTS update_categories_updated_at ON categories;
+CREATE TRIGGER update_categories_updated_at
+    BEFORE UPDATE ON categories
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_technologies_updated_at ON technologies;
+CREATE TRIGGER update_technologies_updated_at
+    BEFORE UPDATE ON technologies
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Seed the options the product form used to hardcode
+INSERT INTO categories (name) VALUES
+    ('Electronics'), ('Software'), ('Hardware'), ('Services'), ('Education'),
+    ('Books'), ('Clothing'), ('Food'), ('Other')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO technologies (name) VALUES
+    ('React'), ('Vue.js'), ('Angular'), ('Node.js'), ('Python'), ('Java'),
+    ('Docker'), ('Kubernetes'), ('AWS'), ('MongoDB'), ('PostgreSQL'), ('Other')
+ON CONFLICT DO NOTHING;
+
+-- Keep any other value already used by a product
+INSERT INTO categories (name) SELECT DISTINCT category FROM products ON CONFLICT DO NOTHING;
+INSERT INTO technologies (name) SELECT DISTINCT technology FROM products ON CONFLICT DO NOTHING;
+
+-- Point products at the lookup rows instead of free text. RESTRICT keeps a
+-- category or technology from being deleted while products use it.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS technology_id INTEGER REFERENCES technologies(id) ON DELETE RESTRICT;
+
+UPDATE products SET category_id = c.id FROM categories c WHERE LOWER(c.name) = LOWER(products.category);
+UPDATE products SET technology_id = t.id FROM technologies t WHERE LOWER(t.name) = LOWER(products.technology);
+
+ALTER TABLE products ALTER COLUMN category_id SET NOT NULL;
+ALTER TABLE products ALTER COLUMN technology_id SET NOT NULL;
+ALTER TABLE products DROP COLUMN IF EXISTS category;
+ALTER TABLE products DROP COLUMN IF EXISTS technology;
+
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products(category_id);
+CREATE INDEX IF NOT EXISTS idx_products_technology_id ON products(technology_id);
+
+-- Seed the permissions for managing the lookup tables
+INSERT INTO permissions (name, description) VALUES
+    ('categories:manage', 'Create, rename and delete product categories'),
+    ('technologies:manage', 'Create, rename and delete product technologies')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name IN ('categories:manage', 'technologies:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,49 @@
+-- Create inventory tables. inventory_levels holds the on-hand quantity of each
+-- product; it is only changed in the same transaction that appends the
+-- matching row to stock_movements, so it always equals the sum of the ledger.
+CREATE TABLE IF NOT EXISTS inventory_levels (
+    product_id INTEGER PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    on_hand INTEGER NOT NULL DEFAULT 0 CHECK (on_hand >= 0),
+    low_stock_threshold INTEGER NOT NULL DEFAULT 0 CHECK (low_stock_threshold >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('receive', 'sell', 'adjust', 'return')),
+    quantity INTEGER NOT NULL CHECK (quantity <> 0),
+    balance_after INTEGER NOT NULL CHECK (balance_after >= 0),
+    note TEXT NOT NULL DEFAULT '',
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id ON stock_movements(product_id, id);
+CREATE INDEX IF NOT EXISTS idx_inventory_levels_low_stock ON inventory_levels(on_hand) WHERE low_stock_threshold > 0;
+
+-- The ledger is append-only, corrections are new adjust movements
+CREATE OR REPLACE FUNCTION prevent_stock_movement_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS prevent_stock_movements_update ON stock_movements;
+CREATE TRIGGER prevent_stock_movements_update
+    BEFORE UPDATE ON stock_movements
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_stock_movement_update();
+
+-- Seed the inventory permissions
+INSERT INTO permissions (name, description) VALUES
+    ('inventory:read', 'View stock levels, stock movements and low-stock products'),
+    ('inventory:manage', 'Record stock movements and set low-stock thresholds')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name IN ('inventory:read', 'inventory:manage')
+ON CONFLICT DO NOTHING;
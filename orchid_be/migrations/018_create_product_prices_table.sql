@@ -0,0 +1,22 @@
+-- Create product price history. Each row changes the price, the discount or
+-- both from starts_at until ends_at (open-ended when NULL). The value in effect
+-- at a time is taken from the latest row that has started and not ended, so a
+-- time-boxed discount falls back to the previous discount when it ends.
+CREATE TABLE IF NOT EXISTS product_prices (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price NUMERIC(12, 2) CHECK (price >= 0),
+    discount NUMERIC(5, 2) CHECK (discount >= 0 AND discount <= 100),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (price IS NOT NULL OR discount IS NOT NULL),
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_prices_product_id ON product_prices(product_id, starts_at DESC, id DESC);
+
+-- Start the history of existing products with their current values
+INSERT INTO product_prices (product_id, price, discount, starts_at)
+SELECT id, price, discount, COALESCE(created_at, CURRENT_TIMESTAMP) FROM products;
@@ -0,0 +1,75 @@
+-- Create pricing plans. A plan is billed every billing_interval; limits caps
+-- what a subscriber may use, a missing limit means unlimited.
+CREATE TABLE IF NOT EXISTS plans (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price NUMERIC(12, 2) NOT NULL CHECK (price >= 0),
+    billing_interval VARCHAR(10) NOT NULL CHECK (billing_interval IN ('month', 'year')),
+    support_months INTEGER NOT NULL DEFAULT 0 CHECK (support_months >= 0),
+    update_months INTEGER NOT NULL DEFAULT 0 CHECK (update_months >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (name, billing_interval)
+);
+
+DROP TRIGGER IF EXISTS update_plans_updated_at ON plans;
+CREATE TRIGGER update_plans_updated_at
+    BEFORE UPDATE ON plans
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS plan_limits (
+    plan_id INTEGER NOT NULL REFERENCES plans(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    max_value INTEGER NOT NULL CHECK (max_value >= 0),
+    PRIMARY KEY (plan_id, name)
+);
+
+-- A user has at most one active subscription. Canceled ones are kept for
+-- billing history.
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    plan_id INTEGER NOT NULL REFERENCES plans(id) ON DELETE RESTRICT,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'canceled')),
+    current_period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    current_period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+    canceled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (current_period_end > current_period_start)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_active_user_id ON subscriptions(user_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user_id ON subscriptions(user_id, id);
+
+DROP TRIGGER IF EXISTS update_subscriptions_updated_at ON subscriptions;
+CREATE TRIGGER update_subscriptions_updated_at
+    BEFORE UPDATE ON subscriptions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Plan changes in the middle of a period, with the prorated amount to bill
+-- (negative when the subscriber is owed a credit).
+CREATE TABLE IF NOT EXISTS subscription_changes (
+    id SERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    from_plan_id INTEGER NOT NULL REFERENCES plans(id) ON DELETE RESTRICT,
+    to_plan_id INTEGER NOT NULL REFERENCES plans(id) ON DELETE RESTRICT,
+    credit NUMERIC(12, 2) NOT NULL,
+    charge NUMERIC(12, 2) NOT NULL,
+    amount_due NUMERIC(12, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_changes_subscription_id ON subscription_changes(subscription_id, id);
+
+-- Seed the plans shown on the pricing page
+INSERT INTO plans (name, description, price, billing_interval, support_months, update_months) VALUES
+    ('Starter', 'Best option for personal use & for your next project.', 29, 'month', 6, 6),
+    ('Company', 'Relevant for multiple users, extended & premium support.', 99, 'month', 24, 24),
+    ('Enterprise', 'Best for large scale uses and extended redistribution rights.', 499, 'month', 36, 36)
+ON CONFLICT (name, billing_interval) DO NOTHING;
@@ -0,0 +1,87 @@
+-- Create tax rates by ISO 3166-1 alpha-2 country code. Customers in a country
+-- without a rate are invoiced without tax.
+CREATE TABLE IF NOT EXISTS tax_rates (
+    country VARCHAR(2) PRIMARY KEY,
+    name VARCHAR(50) NOT NULL DEFAULT 'VAT',
+    rate NUMERIC(5, 2) NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_tax_rates_updated_at ON tax_rates;
+CREATE TRIGGER update_tax_rates_updated_at
+    BEFORE UPDATE ON tax_rates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Invoice numbers restart every year. The counter row is incremented in the
+-- transaction that finalizes the invoice, which locks it until commit, so a
+-- failed finalization hands its number back and the numbers have no gaps.
+CREATE TABLE IF NOT EXISTS invoice_number_counters (
+    year INTEGER PRIMARY KEY,
+    last_number INTEGER NOT NULL
+);
+
+-- Customer details and the tax rate are copied onto the invoice so it stays
+-- as issued when the user or the rate changes later.
+CREATE TABLE IF NOT EXISTS invoices (
+    id SERIAL PRIMARY KEY,
+    number VARCHAR(20) UNIQUE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    subscription_id INTEGER REFERENCES subscriptions(id) ON DELETE SET NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'open', 'paid', 'void')),
+    customer_name VARCHAR(255) NOT NULL DEFAULT '',
+    customer_email VARCHAR(255) NOT NULL,
+    country VARCHAR(2) NOT NULL DEFAULT '',
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    subtotal NUMERIC(12, 2) NOT NULL,
+    tax_name VARCHAR(50) NOT NULL DEFAULT '',
+    tax_rate NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    tax_amount NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    total NUMERIC(12, 2) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE,
+    due_at TIMESTAMP WITH TIME ZONE,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    voided_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (status IN ('draft', 'void') OR number IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invoices_subscription_period ON invoices(subscription_id, period_start) WHERE subscription_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_invoices_user_id ON invoices(user_id, id);
+
+DROP TRIGGER IF EXISTS update_invoices_updated_at ON invoices;
+CREATE TRIGGER update_invoices_updated_at
+    BEFORE UPDATE ON invoices
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS invoice_line_items (
+    id SERIAL PRIMARY KEY,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    unit_price NUMERIC(12, 2) NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoice_line_items_invoice_id ON invoice_line_items(invoice_id, id);
+
+-- Prorated plan changes are billed on the next invoice of the subscription
+ALTER TABLE subscription_changes ADD COLUMN IF NOT EXISTS invoice_id INTEGER REFERENCES invoices(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subscription_changes_unbilled ON subscription_changes(subscription_id) WHERE invoice_id IS NULL;
+
+-- Seed the billing permissions
+INSERT INTO permissions (name, description) VALUES
+    ('invoices:read', 'View all invoices and tax rates'),
+    ('invoices:manage', 'Generate, finalize, mark paid and void invoices and manage tax rates')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name IN ('invoices:read', 'invoices:manage')
+ON CONFLICT DO NOTHING;
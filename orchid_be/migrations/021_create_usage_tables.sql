@@ -0,0 +1,48 @@
+-- Create usage metering tables. usage_events records every metered operation;
+-- usage_counters holds the total of each metric per billing period and is only
+-- changed in the same transaction that appends the matching event.
+CREATE TABLE IF NOT EXISTS usage_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    metric VARCHAR(50) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_events_user_id ON usage_events(user_id, metric, id);
+
+CREATE TABLE IF NOT EXISTS usage_counters (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    metric VARCHAR(50) NOT NULL,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    quantity BIGINT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, metric, period_start),
+    CHECK (period_end > period_start)
+);
+
+-- Seed the metered limits of the pricing plans
+INSERT INTO plan_limits (plan_id, name, max_value)
+SELECT p.id, l.name, l.max_value
+FROM plans p
+JOIN (VALUES
+    ('Starter', 'api_calls', 10000),
+    ('Starter', 'api_keys', 2),
+    ('Company', 'api_calls', 100000),
+    ('Company', 'api_keys', 20)
+) AS l(plan_name, name, max_value) ON l.plan_name = p.name
+WHERE p.billing_interval = 'month'
+ON CONFLICT DO NOTHING;
+
+-- Seed the usage permission
+INSERT INTO permissions (name, description) VALUES
+    ('usage:read', 'View the plan usage of any user')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name = 'usage:read'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,44 @@
+-- name: GetApiKeyByHash :one
+SELECT id, user_id, name, prefix, key_hash, expires_at, last_used_at, revoked_at, created_at
+FROM api_keys
+WHERE key_hash = $1 LIMIT 1;
+
+-- name: ListUserApiKeys :many
+SELECT id, user_id, name, prefix, key_hash, expires_at, last_used_at, revoked_at, created_at
+FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: CreateApiKey :one
+INSERT INTO api_keys (user_id, name, prefix, key_hash, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, name, prefix, key_hash, expires_at, last_used_at, revoked_at, created_at;
+
+-- name: AddApiKeyPermission :exec
+INSERT INTO api_key_permissions (api_key_id, permission_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: ListApiKeyScopes :many
+SELECT p.name
+FROM permissions p
+JOIN api_key_permissions akp ON akp.permission_id = p.id
+WHERE akp.api_key_id = $1
+ORDER BY p.name;
+
+-- name: RevokeApiKey :execrows
+UPDATE api_keys
+SET revoked_at = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: TouchApiKey :exec
+UPDATE api_keys
+SET last_used_at = sqlc.arg(used_at)
+WHERE id = sqlc.arg(id) AND (last_used_at IS NULL OR last_used_at < sqlc.arg(stale_before));
+
+-- name: CountActiveApiKeys :one
+SELECT COUNT(*) FROM api_keys
+WHERE user_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2);
+
+-- name: LockApiKeyOwner :one
+SELECT id FROM users WHERE id = sqlc.arg(user_id) FOR UPDATE;
@@ -0,0 +1,4 @@
+-- name: ListCategories :many
+SELECT id, name, created_at, updated_at
+FROM categories
+ORDER BY name, id;
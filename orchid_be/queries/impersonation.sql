@@ -0,0 +1,33 @@
+-- name: CreateImpersonation :one
+INSERT INTO impersonations (actor_id, subject_id, reason, ip_address, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, actor_id, subject_id, reason, ip_address, expires_at, ended_at, created_at;
+
+-- name: GetImpersonationByID :one
+SELECT id, actor_id, subject_id, reason, ip_address, expires_at, ended_at, created_at
+FROM impersonations
+WHERE id = $1 LIMIT 1;
+
+-- name: ListImpersonations :many
+SELECT id, actor_id, subject_id, reason, ip_address, expires_at, ended_at, created_at
+FROM impersonations
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountImpersonations :one
+SELECT COUNT(*) FROM impersonations;
+
+-- name: EndImpersonation :execrows
+UPDATE impersonations
+SET ended_at = $2
+WHERE id = $1 AND ended_at IS NULL;
+
+-- name: CreateImpersonationEvent :exec
+INSERT INTO impersonation_events (impersonation_id, action, method, path, status_code, ip_address, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListImpersonationEvents :many
+SELECT id, impersonation_id, action, method, path, status_code, ip_address, created_at
+FROM impersonation_events
+WHERE impersonation_id = $1
+ORDER BY created_at, id;
@@ -0,0 +1,48 @@
+-- name: GetInventoryLevel :one
+SELECT product_id, on_hand, low_stock_threshold, updated_at
+FROM inventory_levels
+WHERE product_id = $1 LIMIT 1;
+
+-- name: EnsureInventoryLevel :exec
+INSERT INTO inventory_levels (product_id, updated_at)
+VALUES ($1, $2)
+ON CONFLICT (product_id) DO NOTHING;
+
+-- name: ApplyStockChange :one
+UPDATE inventory_levels
+SET on_hand = on_hand + sqlc.arg(delta)::int, updated_at = sqlc.arg(updated_at)
+WHERE product_id = sqlc.arg(product_id) AND on_hand + sqlc.arg(delta)::int >= 0
+RETURNING product_id, on_hand, low_stock_threshold, updated_at;
+
+-- name: SetLowStockThreshold :one
+INSERT INTO inventory_levels (product_id, low_stock_threshold, updated_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (product_id) DO UPDATE SET low_stock_threshold = EXCLUDED.low_stock_threshold, updated_at = EXCLUDED.updated_at
+RETURNING product_id, on_hand, low_stock_threshold, updated_at;
+
+-- name: CreateStockMovement :one
+INSERT INTO stock_movements (product_id, type, quantity, balance_after, note, created_by, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, product_id, type, quantity, balance_after, note, created_by, created_at;
+
+-- name: ListStockMovements :many
+SELECT id, product_id, type, quantity, balance_after, note, created_by, created_at
+FROM stock_movements
+WHERE product_id = $1
+ORDER BY id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountStockMovements :one
+SELECT COUNT(*) FROM stock_movements WHERE product_id = $1;
+
+-- name: ListLowStockProducts :many
+SELECT il.product_id, p.name AS product_name, il.on_hand, il.low_stock_threshold, il.updated_at
+FROM inventory_levels il
+JOIN products p ON p.id = il.product_id
+WHERE il.low_stock_threshold > 0 AND il.on_hand <= il.low_stock_threshold
+ORDER BY il.on_hand, il.product_id
+LIMIT $1 OFFSET $2;
+
+-- name: CountLowStockProducts :one
+SELECT COUNT(*) FROM inventory_levels
+WHERE low_stock_threshold > 0 AND on_hand <= low_stock_threshold;
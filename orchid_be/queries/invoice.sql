@@ -0,0 +1,74 @@
+-- name: GetInvoiceByID :one
+SELECT id, number, user_id, subscription_id, status, customer_name, customer_email, country, period_start, period_end, subtotal, tax_name, tax_rate, tax_amount, total, issued_at, due_at, paid_at, voided_at, created_at, updated_at
+FROM invoices
+WHERE id = $1 LIMIT 1;
+
+-- name: GetInvoiceBySubscriptionPeriod :one
+SELECT id, number, user_id, subscription_id, status, customer_name, customer_email, country, period_start, period_end, subtotal, tax_name, tax_rate, tax_amount, total, issued_at, due_at, paid_at, voided_at, created_at, updated_at
+FROM invoices
+WHERE subscription_id = $1 AND period_start = $2 LIMIT 1;
+
+-- name: ListInvoices :many
+SELECT id, number, user_id, subscription_id, status, customer_name, customer_email, country, period_start, period_end, subtotal, tax_name, tax_rate, tax_amount, total, issued_at, due_at, paid_at, voided_at, created_at, updated_at
+FROM invoices
+ORDER BY id DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountInvoices :one
+SELECT COUNT(*) FROM invoices;
+
+-- name: ListIssuedInvoicesByUserID :many
+SELECT id, number, user_id, subscription_id, status, customer_name, customer_email, country, period_start, period_end, subtotal, tax_name, tax_rate, tax_amount, total, issued_at, due_at, paid_at, voided_at, created_at, updated_at
+FROM invoices
+WHERE user_id = $1 AND status <> 'draft'
+ORDER BY id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountIssuedInvoicesByUserID :one
+SELECT COUNT(*) FROM invoices WHERE user_id = $1 AND status <> 'draft';
+
+-- name: ListDraftInvoices :many
+SELECT id, number, user_id, subscription_id, status, customer_name, customer_email, country, period_start, period_end, subtotal, tax_name, tax_rate, tax_amount, total, issued_at, due_at, paid_at, voided_at, created_at, updated_at
+FROM invoices
+WHERE status = 'draft'
+ORDER BY id;
+
+-- name: CreateInvoice :one
+INSERT INTO invoices (user_id, subscription_id, customer_name, customer_email, country, period_start, period_end, subtotal, tax_name, tax_rate, tax_amount, total, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING id, number, user_id, subscription_id, status, customer_name, customer_email, country, period_start, period_end, subtotal, tax_name, tax_rate, tax_amount, total, issued_at, due_at, paid_at, voided_at, created_at, updated_at;
+
+-- name: CreateInvoiceLineItem :one
+INSERT INTO invoice_line_items (invoice_id, description, quantity, unit_price, amount)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, invoice_id, description, quantity, unit_price, amount;
+
+-- name: ListInvoiceLineItems :many
+SELECT id, invoice_id, description, quantity, unit_price, amount
+FROM invoice_line_items
+WHERE invoice_id = $1
+ORDER BY id;
+
+-- name: NextInvoiceNumber :one
+INSERT INTO invoice_number_counters (year, last_number)
+VALUES ($1, 1)
+ON CONFLICT (year) DO UPDATE SET last_number = invoice_number_counters.last_number + 1
+RETURNING last_number;
+
+-- name: FinalizeInvoice :one
+UPDATE invoices
+SET status = 'open', number = $2, issued_at = $3, due_at = $4, updated_at = $3
+WHERE id = $1 AND status = 'draft'
+RETURNING id, number, user_id, subscription_id, status, customer_name, customer_email, country, period_start, period_end, subtotal, tax_name, tax_rate, tax_amount, total, issued_at, due_at, paid_at, voided_at, created_at, updated_at;
+
+-- name: MarkInvoicePaid :one
+UPDATE invoices
+SET status = 'paid', paid_at = $2, updated_at = $2
+WHERE id = $1 AND status = 'open'
+RETURNING id, number, user_id, subscription_id, status, customer_name, customer_email, country, period_start, period_end, subtotal, tax_name, tax_rate, tax_amount, total, issued_at, due_at, paid_at, voided_at, created_at, updated_at;
+
+-- name: VoidInvoice :one
+UPDATE invoices
+SET status = 'void', voided_at = $2, updated_at = $2
+WHERE id = $1 AND status IN ('draft', 'open')
+RETURNING id, number, user_id, subscription_id, status, customer_name, customer_email, country, period_start, period_end, subtotal, tax_name, tax_rate, tax_amount, total, issued_at, due_at, paid_at, voided_at, created_at, updated_at;
@@ -0,0 +1,15 @@
+-- name: CreateMagicLinkToken :one
+INSERT INTO magic_link_tokens (user_id, token_hash, expires_at, created_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, token_hash, expires_at, used_at, created_at;
+
+-- name: ConsumeMagicLinkToken :one
+UPDATE magic_link_tokens
+SET used_at = $2
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+RETURNING id, user_id, token_hash, expires_at, used_at, created_at;
+
+-- name: InvalidateUserMagicLinkTokens :exec
+UPDATE magic_link_tokens
+SET used_at = $2
+WHERE user_id = $1 AND used_at IS NULL;
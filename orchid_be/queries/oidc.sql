@@ -0,0 +1,26 @@
+-- name: GetUserIdentity :one
+SELECT id, user_id, provider, subject, email, last_login_at, created_at
+FROM user_identities
+WHERE provider = $1 AND subject = $2 LIMIT 1;
+
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (user_id, provider, subject, email, last_login_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, provider, subject, email, last_login_at, created_at;
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET email = $2, last_login_at = $3
+WHERE id = $1;
+
+-- name: CreateOidcLoginState :exec
+INSERT INTO oidc_login_states (state_hash, provider, code_verifier, nonce, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ConsumeOidcLoginState :one
+DELETE FROM oidc_login_states
+WHERE state_hash = $1
+RETURNING state_hash, provider, code_verifier, nonce, expires_at, created_at;
+
+-- name: DeleteExpiredOidcLoginStates :exec
+DELETE FROM oidc_login_states WHERE expires_at < $1;
@@ -0,0 +1,19 @@
+-- name: GetPasswordResetTokenByHash :one
+SELECT id, user_id, token_hash, expires_at, used_at, created_at
+FROM password_reset_tokens
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (user_id, token_hash, expires_at, created_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, token_hash, expires_at, used_at, created_at;
+
+-- name: MarkPasswordResetTokenUsed :execrows
+UPDATE password_reset_tokens
+SET used_at = $2
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: InvalidateUserPasswordResetTokens :exec
+UPDATE password_reset_tokens
+SET used_at = $2
+WHERE user_id = $1 AND used_at IS NULL;
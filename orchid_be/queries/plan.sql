@@ -0,0 +1,26 @@
+-- name: GetPlanByID :one
+SELECT id, name, description, price, billing_interval, support_months, update_months, is_active, created_at, updated_at
+FROM plans
+WHERE id = $1 LIMIT 1;
+
+-- name: ListActivePlans :many
+SELECT id, name, description, price, billing_interval, support_months, update_months, is_active, created_at, updated_at
+FROM plans
+WHERE is_active = TRUE
+ORDER BY billing_interval, price, id;
+
+-- name: ListPlans :many
+SELECT id, name, description, price, billing_interval, support_months, update_months, is_active, created_at, updated_at
+FROM plans
+ORDER BY id;
+
+-- name: GetPlanLimits :many
+SELECT plan_id, name, max_value
+FROM plan_limits
+WHERE plan_id = $1
+ORDER BY name;
+
+-- name: ListPlanLimits :many
+SELECT plan_id, name, max_value
+FROM plan_limits
+ORDER BY plan_id, name;
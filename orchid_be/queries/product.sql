@@ -0,0 +1,60 @@
+-- name: GetProductByID :one
+SELECT id, name, description, price, discount, created_at, updated_at, category_id, technology_id
+FROM products
+WHERE id = $1 LIMIT 1;
+
+-- name: CreateProduct :one
+INSERT INTO products (name, category_id, technology_id, description, price, discount, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, name, description, price, discount, created_at, updated_at, category_id, technology_id;
+
+-- name: UpdateProduct :one
+UPDATE products
+SET name = $2, category_id = $3, technology_id = $4, description = $5, price = $6, discount = $7, updated_at = $8
+WHERE id = $1
+RETURNING id, name, description, price, discount, created_at, updated_at, category_id, technology_id;
+
+-- name: DeleteProduct :execrows
+DELETE FROM products WHERE id = $1;
+
+-- name: CountProducts :one
+SELECT COUNT(*) FROM products;
+
+-- name: GetPricedProduct :one
+SELECT p.id, p.name, p.description, p.price, p.discount, p.created_at, p.updated_at, p.category_id, p.technology_id,
+    COALESCE(ep.price, p.price)::numeric AS effective_price,
+    COALESCE(ed.discount, p.discount)::numeric AS effective_discount
+FROM products p
+LEFT JOIN LATERAL (
+    SELECT price FROM product_prices
+    WHERE product_id = p.id AND price IS NOT NULL AND starts_at <= sqlc.arg(at) AND (ends_at IS NULL OR ends_at > sqlc.arg(at))
+    ORDER BY starts_at DESC, id DESC
+    LIMIT 1
+) ep ON true
+LEFT JOIN LATERAL (
+    SELECT discount FROM product_prices
+    WHERE product_id = p.id AND discount IS NOT NULL AND starts_at <= sqlc.arg(at) AND (ends_at IS NULL OR ends_at > sqlc.arg(at))
+    ORDER BY starts_at DESC, id DESC
+    LIMIT 1
+) ed ON true
+WHERE p.id = sqlc.arg(id) LIMIT 1;
+
+-- name: GetAllPricedProducts :many
+SELECT p.id, p.name, p.description, p.price, p.discount, p.created_at, p.updated_at, p.category_id, p.technology_id,
+    COALESCE(ep.price, p.price)::numeric AS effective_price,
+    COALESCE(ed.discount, p.discount)::numeric AS effective_discount
+FROM products p
+LEFT JOIN LATERAL (
+    SELECT price FROM product_prices
+    WHERE product_id = p.id AND price IS NOT NULL AND starts_at <= sqlc.arg(at) AND (ends_at IS NULL OR ends_at > sqlc.arg(at))
+    ORDER BY starts_at DESC, id DESC
+    LIMIT 1
+) ep ON true
+LEFT JOIN LATERAL (
+    SELECT discount FROM product_prices
+    WHERE product_id = p.id AND discount IS NOT NULL AND starts_at <= sqlc.arg(at) AND (ends_at IS NULL OR ends_at > sqlc.arg(at))
+    ORDER BY starts_at DESC, id DESC
+    LIMIT 1
+) ed ON true
+ORDER BY p.created_at DESC, p.id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
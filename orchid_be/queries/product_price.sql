@@ -0,0 +1,18 @@
+-- name: CreateProductPrice :one
+INSERT INTO product_prices (product_id, price, discount, starts_at, ends_at, created_by, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, product_id, price, discount, starts_at, ends_at, created_by, created_at;
+
+-- name: ListProductPrices :many
+SELECT id, product_id, price, discount, starts_at, ends_at, created_by, created_at
+FROM product_prices
+WHERE product_id = $1
+ORDER BY starts_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountProductPrices :one
+SELECT COUNT(*) FROM product_prices WHERE product_id = $1;
+
+-- name: DeletePendingProductPrice :execrows
+DELETE FROM product_prices
+WHERE id = $1 AND product_id = $2 AND starts_at > $3;
@@ -0,0 +1,29 @@
+-- name: GetRefreshTokenByHash :one
+SELECT id, user_id, token_hash, family_id, expires_at, rotated_at, revoked_at, created_at
+FROM refresh_tokens
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (user_id, token_hash, family_id, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, token_hash, family_id, expires_at, rotated_at, revoked_at, created_at;
+
+-- name: MarkRefreshTokenRotated :execrows
+UPDATE refresh_tokens
+SET rotated_at = $2
+WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = $2
+WHERE family_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserRefreshTokens :exec
+UPDATE refresh_tokens
+SET revoked_at = $2
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeOtherUserRefreshTokens :exec
+UPDATE refresh_tokens
+SET revoked_at = $3
+WHERE user_id = $1 AND family_id <> $2 AND revoked_at IS NULL;
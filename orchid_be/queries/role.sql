@@ -0,0 +1,73 @@
+-- name: GetRoleByID :one
+SELECT id, name, description, created_at, updated_at
+FROM roles
+WHERE id = $1 LIMIT 1;
+
+-- name: GetRoleByName :one
+SELECT id, name, description, created_at, updated_at
+FROM roles
+WHERE name = $1 LIMIT 1;
+
+-- name: ListRoles :many
+SELECT id, name, description, created_at, updated_at
+FROM roles
+ORDER BY name;
+
+-- name: CreateRole :one
+INSERT INTO roles (name, description, created_at, updated_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, name, description, created_at, updated_at;
+
+-- name: DeleteRole :exec
+DELETE FROM roles WHERE id = $1;
+
+-- name: GetPermissionByName :one
+SELECT id, name, description, created_at
+FROM permissions
+WHERE name = $1 LIMIT 1;
+
+-- name: ListPermissions :many
+SELECT id, name, description, created_at
+FROM permissions
+ORDER BY name;
+
+-- name: ListRolePermissions :many
+SELECT p.id, p.name, p.description, p.created_at
+FROM permissions p
+JOIN role_permissions rp ON rp.permission_id = p.id
+WHERE rp.role_id = $1
+ORDER BY p.name;
+
+-- name: AddRolePermission :exec
+INSERT INTO role_permissions (role_id, permission_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: ListUserRoles :many
+SELECT r.id, r.name, r.description, r.created_at, r.updated_at
+FROM roles r
+JOIN user_roles ur ON ur.role_id = r.id
+WHERE ur.user_id = $1
+ORDER BY r.name;
+
+-- name: ListUserPermissionNames :many
+SELECT DISTINCT p.name
+FROM permissions p
+JOIN role_permissions rp ON rp.permission_id = p.id
+JOIN user_roles ur ON ur.role_id = rp.role_id
+WHERE ur.user_id = $1
+ORDER BY p.name;
+
+-- name: AssignUserRole :exec
+INSERT INTO user_roles (user_id, role_id, created_at)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveUserRole :execrows
+DELETE FROM user_roles WHERE user_id = $1 AND role_id = $2;
+
+-- name: CountRoleUsers :one
+SELECT COUNT(*) FROM user_roles WHERE role_id = $1;
+
+-- name: LockRole :one
+SELECT name FROM roles WHERE id = $1 FOR UPDATE;
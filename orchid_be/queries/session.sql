@@ -0,0 +1,52 @@
+-- name: CreateSession :one
+INSERT INTO sessions (user_id, family_id, device, user_agent, ip_address, expires_at, last_seen_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, user_id, family_id, device, user_agent, ip_address, expires_at, last_seen_at, revoked_at, created_at, elevated_until;
+
+-- name: GetSessionByID :one
+SELECT id, user_id, family_id, device, user_agent, ip_address, expires_at, last_seen_at, revoked_at, created_at, elevated_until
+FROM sessions
+WHERE id = $1 LIMIT 1;
+
+-- name: ListActiveUserSessions :many
+SELECT id, user_id, family_id, device, user_agent, ip_address, expires_at, last_seen_at, revoked_at, created_at, elevated_until
+FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+ORDER BY COALESCE(last_seen_at, created_at) DESC;
+
+-- name: RefreshSession :one
+UPDATE sessions
+SET user_agent = $2, ip_address = $3, expires_at = $4, last_seen_at = $5
+WHERE family_id = $1 AND revoked_at IS NULL
+RETURNING id, user_id, family_id, device, user_agent, ip_address, expires_at, last_seen_at, revoked_at, created_at, elevated_until;
+
+-- name: TouchSession :exec
+UPDATE sessions
+SET last_seen_at = sqlc.arg(seen_at)
+WHERE id = sqlc.arg(id) AND (last_seen_at IS NULL OR last_seen_at < sqlc.arg(stale_before));
+
+-- name: RevokeSession :one
+UPDATE sessions
+SET revoked_at = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+RETURNING family_id;
+
+-- name: RevokeSessionByFamily :exec
+UPDATE sessions
+SET revoked_at = $2
+WHERE family_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :exec
+UPDATE sessions
+SET revoked_at = $2
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeOtherUserSessions :exec
+UPDATE sessions
+SET revoked_at = $3
+WHERE user_id = $1 AND family_id <> $2 AND revoked_at IS NULL;
+
+-- name: SetSessionElevation :execrows
+UPDATE sessions
+SET elevated_until = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
@@ -0,0 +1,45 @@
+-- name: GetActiveSubscriptionByUserID :one
+SELECT id, user_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at
+FROM subscriptions
+WHERE user_id = $1 AND status = 'active' LIMIT 1;
+
+-- name: CreateSubscription :one
+INSERT INTO subscriptions (user_id, plan_id, current_period_start, current_period_end, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at;
+
+-- name: UpdateSubscriptionPeriod :one
+UPDATE subscriptions
+SET plan_id = sqlc.arg(plan_id), current_period_start = sqlc.arg(current_period_start), current_period_end = sqlc.arg(current_period_end),
+    cancel_at_period_end = sqlc.arg(cancel_at_period_end), updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id) AND status = 'active'
+    AND plan_id = sqlc.arg(expected_plan_id) AND current_period_start = sqlc.arg(expected_period_start)
+RETURNING id, user_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at;
+
+-- name: CancelSubscription :one
+UPDATE subscriptions
+SET status = 'canceled', cancel_at_period_end = FALSE, canceled_at = $2, updated_at = $3
+WHERE id = $1 AND status = 'active'
+RETURNING id, user_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at;
+
+-- name: CreateSubscriptionChange :one
+INSERT INTO subscription_changes (subscription_id, from_plan_id, to_plan_id, credit, charge, amount_due, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, subscription_id, from_plan_id, to_plan_id, credit, charge, amount_due, created_at, invoice_id;
+
+-- name: ListActiveSubscriptions :many
+SELECT id, user_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at
+FROM subscriptions
+WHERE status = 'active'
+ORDER BY id;
+
+-- name: ListUnbilledSubscriptionChanges :many
+SELECT id, subscription_id, from_plan_id, to_plan_id, credit, charge, amount_due, created_at, invoice_id
+FROM subscription_changes
+WHERE subscription_id = $1 AND invoice_id IS NULL
+ORDER BY id;
+
+-- name: SetSubscriptionChangeInvoice :exec
+UPDATE subscription_changes
+SET invoice_id = $2
+WHERE id = $1 AND invoice_id IS NULL;
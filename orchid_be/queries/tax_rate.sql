@@ -0,0 +1,18 @@
+-- name: GetTaxRate :one
+SELECT country, name, rate, created_at, updated_at
+FROM tax_rates
+WHERE country = $1 LIMIT 1;
+
+-- name: ListTaxRates :many
+SELECT country, name, rate, created_at, updated_at
+FROM tax_rates
+ORDER BY country;
+
+-- name: UpsertTaxRate :one
+INSERT INTO tax_rates (country, name, rate, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (country) DO UPDATE SET name = EXCLUDED.name, rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at
+RETURNING country, name, rate, created_at, updated_at;
+
+-- name: DeleteTaxRate :execrows
+DELETE FROM tax_rates WHERE country = $1;
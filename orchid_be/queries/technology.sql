@@ -0,0 +1,4 @@
+-- name: ListTechnologies :many
+SELECT id, name, created_at, updated_at
+FROM technologies
+ORDER BY name, id;
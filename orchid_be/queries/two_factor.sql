@@ -0,0 +1,39 @@
+-- name: GetTotpSecret :one
+SELECT user_id, secret, confirmed_at, last_used_step, created_at, updated_at
+FROM totp_secrets
+WHERE user_id = $1 LIMIT 1;
+
+-- name: UpsertTotpSecret :one
+INSERT INTO totp_secrets (user_id, secret, created_at, updated_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret, confirmed_at = NULL, last_used_step = 0, updated_at = EXCLUDED.updated_at
+RETURNING user_id, secret, confirmed_at, last_used_step, created_at, updated_at;
+
+-- name: ConfirmTotpSecret :execrows
+UPDATE totp_secrets
+SET confirmed_at = $2
+WHERE user_id = $1 AND confirmed_at IS NULL;
+
+-- name: UpdateTotpLastUsedStep :execrows
+UPDATE totp_secrets
+SET last_used_step = $2
+WHERE user_id = $1 AND last_used_step < $2;
+
+-- name: DeleteTotpSecret :exec
+DELETE FROM totp_secrets WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO recovery_codes (user_id, code_hash, created_at)
+VALUES ($1, $2, $3);
+
+-- name: UseRecoveryCode :execrows
+UPDATE recovery_codes
+SET used_at = $3
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*) FROM recovery_codes WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes WHERE user_id = $1;
@@ -0,0 +1,21 @@
+-- name: EnsureUsageCounter :exec
+INSERT INTO usage_counters (user_id, metric, period_start, period_end, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, metric, period_start) DO NOTHING;
+
+-- name: IncrementUsageCounter :one
+UPDATE usage_counters
+SET quantity = quantity + sqlc.arg(quantity)::bigint, updated_at = sqlc.arg(updated_at)
+WHERE user_id = sqlc.arg(user_id) AND metric = sqlc.arg(metric) AND period_start = sqlc.arg(period_start)
+    AND (sqlc.narg(max_quantity)::bigint IS NULL OR quantity + sqlc.arg(quantity)::bigint <= sqlc.narg(max_quantity)::bigint)
+RETURNING user_id, metric, period_start, period_end, quantity, updated_at;
+
+-- name: CreateUsageEvent :exec
+INSERT INTO usage_events (user_id, metric, quantity, period_start, created_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListUsageCounters :many
+SELECT user_id, metric, period_start, period_end, quantity, updated_at
+FROM usage_counters
+WHERE user_id = $1 AND period_start = $2
+ORDER BY metric;
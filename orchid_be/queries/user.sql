@@ -1,32 +1,56 @@
 -- name: GetUserByID :one
-SELECT id, name, email, password_hash, created_at, updated_at
+SELECT id, name, email, password_hash, created_at, updated_at, status, email_verified_at, failed_login_attempts, last_failed_login_at, locked_until, avatar, biography, position, country
 FROM users
 WHERE id = $1 LIMIT 1;
 
 -- name: GetUserByEmail :one
-SELECT id, name, email, password_hash, created_at, updated_at
+SELECT id, name, email, password_hash, created_at, updated_at, status, email_verified_at, failed_login_attempts, last_failed_login_at, locked_until, avatar, biography, position, country
 FROM users
 WHERE email = $1 LIMIT 1;
 
--- name: GetAllUsers :many
-SELECT id, name, email, password_hash, created_at, updated_at
-FROM users
-ORDER BY created_at DESC
-LIMIT $1 OFFSET $2;
-
 -- name: CreateUser :one
-INSERT INTO users (name, email, password_hash, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, name, email, password_hash, created_at, updated_at;
+INSERT INTO users (name, email, password_hash, status, avatar, biography, position, country, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, name, email, password_hash, created_at, updated_at, status, email_verified_at, failed_login_attempts, last_failed_login_at, locked_until, avatar, biography, position, country;
 
 -- name: UpdateUser :one
 UPDATE users
-SET name = $2, email = $3, password_hash = $4, updated_at = $5
+SET name = $2, email = $3, password_hash = $4, status = $5, avatar = $6, biography = $7, position = $8, country = $9, updated_at = $10,
+    email_verified_at = CASE WHEN email = $3 THEN email_verified_at END
+WHERE id = $1
+RETURNING id, name, email, password_hash, created_at, updated_at, status, email_verified_at, failed_login_attempts, last_failed_login_at, locked_until, avatar, biography, position, country;
+
+-- name: UpdateUserPassword :one
+UPDATE users
+SET password_hash = $2, updated_at = $3
+WHERE id = $1
+RETURNING id, name, email, password_hash, created_at, updated_at, status, email_verified_at, failed_login_attempts, last_failed_login_at, locked_until, avatar, biography, position, country;
+
+-- name: VerifyUserEmail :one
+UPDATE users
+SET status = $2, email_verified_at = $3, updated_at = $3
 WHERE id = $1
-RETURNING id, name, email, password_hash, created_at, updated_at;
+RETURNING id, name, email, password_hash, created_at, updated_at, status, email_verified_at, failed_login_attempts, last_failed_login_at, locked_until, avatar, biography, position, country;
+
+-- name: RecordFailedLogin :one
+UPDATE users
+SET failed_login_attempts = CASE
+        WHEN last_failed_login_at IS NULL OR last_failed_login_at < sqlc.arg(window_start) THEN 1
+        ELSE failed_login_attempts + 1
+    END,
+    last_failed_login_at = sqlc.arg(failed_at)
+WHERE id = sqlc.arg(id)
+RETURNING failed_login_attempts;
+
+-- name: LockUser :exec
+UPDATE users
+SET locked_until = $2
+WHERE id = $1;
+
+-- name: ResetFailedLogins :exec
+UPDATE users
+SET failed_login_attempts = 0, last_failed_login_at = NULL, locked_until = NULL
+WHERE id = $1;
 
 -- name: DeleteUser :exec
 DELETE FROM users WHERE id = $1;
-
--- name: CountUsers :one
-SELECT COUNT(*) FROM users;